@@ -1 +1,52 @@
 package bingx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type accountServiceTestSuite struct {
+	serverTestSuite
+}
+
+func TestAccountService(t *testing.T) {
+	suite.Run(t, new(accountServiceTestSuite))
+}
+
+func (s *accountServiceTestSuite) TestGetBalance() {
+	r := s.r()
+	s.server.SetBalance(1000)
+	s.server.SetLeverage("BTC-USDT", 10)
+	s.server.SetFeeRate(0, 0.001)
+	s.server.SetPrice("BTC-USDT", 40000)
+
+	_, err := s.client.NewCreateOrderService().Symbol("BTC-USDT").Type(MarketOrderType).Side(BuySideType).Quantity(0.1).Do(newContext())
+	r.NoError(err)
+	s.server.SetPrice("BTC-USDT", 41000)
+
+	balance, err := s.client.NewGetBalanceService().Do(newContext())
+	r.NoError(err)
+	r.Equal(&Balance{
+		Asset:            "USDT",
+		Balance:          "996",
+		Equity:           "1096",
+		UnrealizedProfit: "100",
+		RealisedProfit:   "0",
+		AavailableMargin: "696",
+		UsedMargin:       "400",
+		FreezedMargin:    "0",
+	}, balance)
+}
+
+func (s *accountServiceTestSuite) TestGetListenKey() {
+	r := s.r()
+	listenKey, err := s.client.NewGetAccountListenKeyService().Do(newContext())
+	r.NoError(err)
+	r.Equal(s.server.ListenKey, listenKey)
+
+	s.server.Fail(http.MethodPost, "/openApi/user/auth/userDataStream", 100500, "internal error")
+	_, err = s.client.NewGetAccountListenKeyService().Do(newContext())
+	r.Equal(int64(100500), s.apiErrorCode(err))
+}
@@ -0,0 +1,475 @@
+// Package algo executes large orders as a series of child orders: TWAP over a duration,
+// iceberg showing a visible slice and post only limit order chasing the best price.
+// Child orders are placed with CreateOrderService and CancelOrderService and followed with
+// bingx.OrderTracker, which should be started on the order update stream.
+package algo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+const cancelTimeout = 10 * time.Second
+
+// ErrInvalidAlgo algo is not configured properly
+var ErrInvalidAlgo = errors.New("algo: invalid parameters")
+
+// Status of execution
+type Status string
+
+const (
+	RunningStatus Status = "RUNNING"
+	PausedStatus  Status = "PAUSED"
+	// DoneStatus algo completed, FilledQty may be below Quantity when child orders were not filled
+	DoneStatus     Status = "DONE"
+	CanceledStatus Status = "CANCELED"
+	FailedStatus   Status = "FAILED"
+)
+
+// Quoter Define source of the best prices, implemented by bingx.OrderBook
+type Quoter interface {
+	BestBid() (bingx.WsDepthLevel, bool)
+	BestAsk() (bingx.WsDepthLevel, bool)
+}
+
+// ProgressHandler Define handler called on every change of execution, Pause and Resume call it
+// in goroutine of caller
+type ProgressHandler func(progress Progress)
+
+// Progress Define state of execution
+type Progress struct {
+	Status       Status
+	Quantity     float64
+	FilledQty    float64
+	AveragePrice float64
+	// Orders number of child orders placed
+	Orders int
+	// WorkingOrderId child order waiting on exchange, zero when there is none
+	WorkingOrderId int64
+}
+
+// RemainingQty Quantity left to fill
+func (p Progress) RemainingQty() float64 {
+	if p.FilledQty >= p.Quantity {
+		return 0
+	}
+	return p.Quantity - p.FilledQty
+}
+
+// Summary Define result of execution
+type Summary struct {
+	Symbol       string
+	Side         bingx.SideType
+	Status       Status
+	Quantity     float64
+	FilledQty    float64
+	AveragePrice float64
+	// ArrivalPrice reference price of slippage, price of the first fill when it is unknown at start
+	ArrivalPrice float64
+	// Slippage of average price from arrival price as a fraction, positive when execution is worse
+	Slippage float64
+	// Fee accumulated, negative when paid
+	Fee       float64
+	Orders    int
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// Execution Define running algo, returned by Start of TWAP, Iceberg and Chase
+type Execution struct {
+	client          *bingx.Client
+	tracker         *bingx.OrderTracker
+	order           bingx.OrderRequest
+	progressHandler ProgressHandler
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	doneC  chan struct{}
+
+	mu           sync.Mutex
+	status       Status
+	resumeC      chan struct{}
+	interrupt    context.CancelFunc
+	filledQty    float64
+	notional     float64
+	fee          float64
+	orders       int
+	working      int64
+	arrivalPrice float64
+	startTime    time.Time
+	endTime      time.Time
+	err          error
+}
+
+func newExecution(client *bingx.Client, tracker *bingx.OrderTracker, order *bingx.OrderRequest, progressHandler ProgressHandler) *Execution {
+	return &Execution{
+		client:          client,
+		tracker:         tracker,
+		order:           *order,
+		progressHandler: progressHandler,
+		doneC:           make(chan struct{}),
+		status:          RunningStatus,
+	}
+}
+
+func validate(order *bingx.OrderRequest) error {
+	switch {
+	case order == nil:
+		return fmt.Errorf("%w: order is required", ErrInvalidAlgo)
+	case order.Symbol == "":
+		return fmt.Errorf("%w: symbol is required", ErrInvalidAlgo)
+	case order.Side != bingx.BuySideType && order.Side != bingx.SellSideType:
+		return fmt.Errorf("%w: invalid side %q", ErrInvalidAlgo, order.Side)
+	case order.Quantity <= 0:
+		return fmt.Errorf("%w: quantity must be positive", ErrInvalidAlgo)
+	}
+	return nil
+}
+
+// start runs algo in background until it returns, ctx is done or Cancel is called
+func (e *Execution) start(ctx context.Context, arrivalPrice float64, run func() error) {
+	e.ctx, e.cancel = context.WithCancel(ctx)
+	e.arrivalPrice = arrivalPrice
+	e.startTime = time.Now()
+
+	go func() {
+		e.finish(run())
+	}()
+}
+
+func (e *Execution) finish(err error) {
+	e.mu.Lock()
+	switch {
+	case err == nil:
+		e.status = DoneStatus
+	case e.ctx.Err() != nil && errors.Is(err, e.ctx.Err()):
+		e.status = CanceledStatus
+	default:
+		e.status = FailedStatus
+	}
+	e.err = err
+	e.endTime = time.Now()
+	e.mu.Unlock()
+
+	e.report()
+	e.cancel()
+	close(e.doneC)
+}
+
+// Pause Cancel working child order and stop placing new ones until Resume is called
+func (e *Execution) Pause() {
+	e.mu.Lock()
+	if e.status != RunningStatus {
+		e.mu.Unlock()
+		return
+	}
+	e.status = PausedStatus
+	e.resumeC = make(chan struct{})
+	if e.interrupt != nil {
+		e.interrupt()
+	}
+	e.mu.Unlock()
+
+	e.report()
+}
+
+// Resume Continue paused execution
+func (e *Execution) Resume() {
+	e.mu.Lock()
+	if e.status != PausedStatus {
+		e.mu.Unlock()
+		return
+	}
+	e.status = RunningStatus
+	close(e.resumeC)
+	e.resumeC = nil
+	e.mu.Unlock()
+
+	e.report()
+}
+
+// Cancel Cancel working child order and stop execution, safe to call many times
+func (e *Execution) Cancel() {
+	e.cancel()
+}
+
+// Done Closed when execution is finished
+func (e *Execution) Done() <-chan struct{} {
+	return e.doneC
+}
+
+// Err Error which stopped execution, context.Canceled when it is canceled
+func (e *Execution) Err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// Wait Block until execution is finished or ctx is done, returns error which stopped execution
+func (e *Execution) Wait(ctx context.Context) (*Summary, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-e.doneC:
+	}
+	return e.Summary(), e.Err()
+}
+
+// Progress Current state of execution
+func (e *Execution) Progress() Progress {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.progress()
+}
+
+func (e *Execution) progress() Progress {
+	progress := Progress{
+		Status:         e.status,
+		Quantity:       e.order.Quantity,
+		FilledQty:      e.filledQty,
+		Orders:         e.orders,
+		WorkingOrderId: e.working,
+	}
+	if e.filledQty > 0 {
+		progress.AveragePrice = e.notional / e.filledQty
+	}
+	return progress
+}
+
+// Summary Execution result, filled part is reported while execution is running
+func (e *Execution) Summary() *Summary {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	progress := e.progress()
+	summary := &Summary{
+		Symbol:       e.order.Symbol,
+		Side:         e.order.Side,
+		Status:       e.status,
+		Quantity:     e.order.Quantity,
+		FilledQty:    e.filledQty,
+		AveragePrice: progress.AveragePrice,
+		ArrivalPrice: e.arrivalPrice,
+		Fee:          e.fee,
+		Orders:       e.orders,
+		StartTime:    e.startTime,
+		EndTime:      e.endTime,
+	}
+	if summary.AveragePrice > 0 && summary.ArrivalPrice > 0 {
+		summary.Slippage = (summary.AveragePrice - summary.ArrivalPrice) / summary.ArrivalPrice
+		if summary.Side == bingx.SellSideType {
+			summary.Slippage = -summary.Slippage
+		}
+	}
+	return summary
+}
+
+func (e *Execution) report() {
+	if e.progressHandler == nil {
+		return
+	}
+
+	e.progressHandler(e.Progress())
+}
+
+func (e *Execution) remainingQty() float64 {
+	return e.Progress().RemainingQty()
+}
+
+// checkpoint blocks while execution is paused
+func (e *Execution) checkpoint() error {
+	for {
+		if err := e.ctx.Err(); err != nil {
+			return err
+		}
+
+		e.mu.Lock()
+		resumeC := e.resumeC
+		e.mu.Unlock()
+
+		if resumeC == nil {
+			return nil
+		}
+		select {
+		case <-e.ctx.Done():
+		case <-resumeC:
+		}
+	}
+}
+
+// interrupted reports whether execution is paused or canceled
+func (e *Execution) interrupted() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status == PausedStatus || e.ctx.Err() != nil
+}
+
+// waitContext is done after timeout, when execution is paused or canceled, zero timeout never expires
+func (e *Execution) waitContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(e.ctx)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(e.ctx, timeout)
+	}
+
+	e.mu.Lock()
+	if e.status == PausedStatus {
+		cancel()
+	}
+	e.interrupt = cancel
+	e.mu.Unlock()
+
+	return ctx, cancel
+}
+
+// sleep waits for d of running time, time spent in pause is not counted
+func (e *Execution) sleep(d time.Duration) error {
+	for d > 0 {
+		if err := e.checkpoint(); err != nil {
+			return err
+		}
+
+		ctx, cancel := e.waitContext(d)
+		start := time.Now()
+		<-ctx.Done()
+		expired := errors.Is(ctx.Err(), context.DeadlineExceeded)
+		cancel()
+
+		if expired {
+			return nil
+		}
+		d -= time.Since(start)
+	}
+	return e.checkpoint()
+}
+
+// place creates child order with parameters of parent order
+func (e *Execution) place(child bingx.OrderRequest) (*bingx.TrackedOrder, error) {
+	child.Symbol = e.order.Symbol
+	child.Side = e.order.Side
+	child.PositionSide = e.order.PositionSide
+	child.ReduceOnly = e.order.ReduceOnly
+
+	e.mu.Lock()
+	if e.order.ClientOrderID != "" {
+		child.ClientOrderID = fmt.Sprintf("%s-%d", e.order.ClientOrderID, e.orders+1)
+	}
+	e.mu.Unlock()
+
+	order, err := e.tracker.Create(e.ctx, &child)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	e.orders++
+	e.working = order.OrderId
+	e.mu.Unlock()
+
+	e.report()
+	return order, nil
+}
+
+// wait blocks until child order is done, returns nil order when timeout expires or execution is interrupted
+func (e *Execution) wait(orderId int64, timeout time.Duration) (*bingx.TrackedOrder, error) {
+	ctx, cancel := e.waitContext(timeout)
+	defer cancel()
+
+	order, err := e.tracker.Wait(ctx, orderId)
+	if err != nil && ctx.Err() != nil {
+		return nil, nil
+	}
+	return order, err
+}
+
+// cancelOrder cancels child order and returns its final state
+func (e *Execution) cancelOrder(orderId int64) (*bingx.TrackedOrder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+	defer cancel()
+
+	// order filled meanwhile can not be canceled, its snapshot tells the truth
+	_, cancelErr := e.client.NewCancelOrderService().Symbol(e.order.Symbol).OrderId(orderId).Do(ctx)
+	order, err := e.tracker.Track(ctx, e.order.Symbol, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.Done() {
+		return order, nil
+	}
+	if cancelErr != nil {
+		return nil, cancelErr
+	}
+	return e.tracker.Wait(ctx, orderId)
+}
+
+// settle adds fills of done child order to execution
+func (e *Execution) settle(order *bingx.TrackedOrder) {
+	e.mu.Lock()
+	e.working = 0
+	if order.FilledQty > 0 {
+		e.filledQty += order.FilledQty
+		e.notional += order.FilledQty * order.AveragePrice
+		e.fee += order.Fee
+		if e.arrivalPrice == 0 {
+			e.arrivalPrice = order.AveragePrice
+		}
+	}
+	e.mu.Unlock()
+
+	e.report()
+}
+
+// execute places child order and waits until it is done, order is canceled when timeout expires
+// or execution is interrupted. Reports whether order finished by itself.
+func (e *Execution) execute(child bingx.OrderRequest, timeout time.Duration) (*bingx.TrackedOrder, bool, error) {
+	order, err := e.place(child)
+	if err != nil {
+		return nil, false, err
+	}
+
+	done, err := e.wait(order.OrderId, timeout)
+	if err != nil {
+		return nil, false, err
+	}
+	interrupted := done == nil
+	if interrupted {
+		if done, err = e.cancelOrder(order.OrderId); err != nil {
+			return nil, false, err
+		}
+	}
+
+	e.settle(done)
+	return done, !interrupted, nil
+}
+
+// midPrice of quoter, the only side when the other one is empty
+func midPrice(quoter Quoter) float64 {
+	if quoter == nil {
+		return 0
+	}
+	bid, hasBid := quoter.BestBid()
+	ask, hasAsk := quoter.BestAsk()
+	switch {
+	case hasBid && hasAsk:
+		return (bid.Price + ask.Price) / 2
+	case hasBid:
+		return bid.Price
+	case hasAsk:
+		return ask.Price
+	}
+	return 0
+}
+
+// roundQuantity rounds quantity down to precision decimals, negative precision keeps it as is
+func roundQuantity(quantity float64, precision int) float64 {
+	if precision < 0 {
+		return quantity
+	}
+	scale := math.Pow10(precision)
+	return math.Floor(quantity*scale+1e-9) / scale
+}
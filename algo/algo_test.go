@@ -0,0 +1,282 @@
+package algo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+	"github.com/stretchr/testify/require"
+)
+
+func newPaperClient(t *testing.T) (*bingx.PaperClient, *bingx.OrderTracker) {
+	paper := bingx.NewPaperClient(bingx.NewClient("apiKey", "secretKey")).
+		Balance(100000).
+		Leverage("BTC-USDT", 10).
+		Fees(0.0002, 0.0005)
+	tracker := paper.NewOrderTracker()
+	paper.OrderUpdateHandler(func(order *bingx.WsOrder) {
+		require.NoError(t, tracker.ApplyUpdate(order))
+	})
+	paper.UpdatePrice("BTC-USDT", 100)
+	return paper, tracker
+}
+
+func waitContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// eventually waits until progress of execution satisfies condition
+func eventually(t *testing.T, e *Execution, condition func(progress Progress) bool) Progress {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		progress := e.Progress()
+		if condition(progress) {
+			return progress
+		}
+		if time.Now().After(deadline) {
+			require.FailNow(t, "execution is not updated", "%+v", progress)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type testQuoter struct {
+	mu  sync.Mutex
+	bid float64
+	ask float64
+}
+
+func (q *testQuoter) set(bid, ask float64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.bid, q.ask = bid, ask
+}
+
+func (q *testQuoter) BestBid() (bingx.WsDepthLevel, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return bingx.WsDepthLevel{Price: q.bid, Quantity: 1}, q.bid > 0
+}
+
+func (q *testQuoter) BestAsk() (bingx.WsDepthLevel, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return bingx.WsDepthLevel{Price: q.ask, Quantity: 1}, q.ask > 0
+}
+
+func TestTWAP(t *testing.T) {
+	r := require.New(t)
+	paper, tracker := newPaperClient(t)
+	paper.Slippage(0.001)
+
+	var mu sync.Mutex
+	var filled []float64
+	e, err := NewTWAP(paper.Client, tracker, &bingx.OrderRequest{
+		Symbol:        "BTC-USDT",
+		Side:          bingx.BuySideType,
+		ClientOrderID: "twap",
+		Quantity:      1,
+	}).
+		Duration(60 * time.Millisecond).
+		Slices(3).
+		QuantityPrecision(2).
+		ArrivalPrice(100).
+		ProgressHandler(func(progress Progress) {
+			mu.Lock()
+			defer mu.Unlock()
+			if progress.WorkingOrderId == 0 && progress.Status == RunningStatus {
+				filled = append(filled, progress.FilledQty)
+			}
+		}).
+		Start(context.Background())
+	r.NoError(err)
+
+	summary, err := e.Wait(waitContext(t))
+	r.NoError(err)
+	r.Equal(DoneStatus, summary.Status)
+	r.Equal(3, summary.Orders)
+	r.InDelta(1, summary.FilledQty, 1e-9)
+	r.InDelta(100.1, summary.AveragePrice, 1e-9)
+	r.InDelta(0.001, summary.Slippage, 1e-9)
+	r.InDelta(-0.05005, summary.Fee, 1e-9)
+	r.GreaterOrEqual(summary.EndTime.Sub(summary.StartTime), 60*time.Millisecond)
+
+	mu.Lock()
+	r.InDeltaSlice([]float64{0.33, 0.66, 1}, filled, 1e-9)
+	mu.Unlock()
+	last, err := tracker.OrderByClientID("twap-3")
+	r.NoError(err)
+	r.InDelta(0.34, last.Quantity, 1e-9)
+
+	_, err = NewTWAP(paper.Client, tracker, &bingx.OrderRequest{Symbol: "BTC-USDT", Side: bingx.BuySideType}).Start(context.Background())
+	r.True(errors.Is(err, ErrInvalidAlgo), err)
+}
+
+func TestTWAPPause(t *testing.T) {
+	r := require.New(t)
+	paper, tracker := newPaperClient(t)
+
+	e, err := NewTWAP(paper.Client, tracker, &bingx.OrderRequest{Symbol: "BTC-USDT", Side: bingx.SellSideType, Quantity: 2}).
+		Duration(100 * time.Millisecond).
+		Slices(2).
+		Start(context.Background())
+	r.NoError(err)
+	eventually(t, e, func(progress Progress) bool {
+		return progress.FilledQty == 1
+	})
+	e.Pause()
+	r.Equal(PausedStatus, e.Progress().Status)
+
+	// schedule is frozen while paused
+	time.Sleep(200 * time.Millisecond)
+	r.Equal(1, e.Progress().Orders)
+
+	e.Resume()
+	summary, err := e.Wait(waitContext(t))
+	r.NoError(err)
+	r.Equal(float64(2), summary.FilledQty)
+	r.Equal(float64(100), summary.ArrivalPrice)
+	r.Zero(summary.Slippage)
+}
+
+func TestIceberg(t *testing.T) {
+	r := require.New(t)
+	paper, tracker := newPaperClient(t)
+
+	e, err := NewIceberg(paper.Client, tracker, &bingx.OrderRequest{
+		Symbol:   "BTC-USDT",
+		Side:     bingx.BuySideType,
+		Price:    99,
+		Quantity: 3,
+	}).
+		Visible(1).
+		ArrivalPrice(100).
+		Start(context.Background())
+	r.NoError(err)
+	progress := eventually(t, e, func(progress Progress) bool {
+		return progress.WorkingOrderId != 0
+	})
+	working, err := tracker.Order(progress.WorkingOrderId)
+	r.NoError(err)
+	r.Equal(float64(1), working.Quantity)
+
+	// pause cancels visible slice
+	e.Pause()
+	eventually(t, e, func(progress Progress) bool {
+		return progress.WorkingOrderId == 0
+	})
+	open, err := paper.GetOpenOrders(context.Background(), "BTC-USDT")
+	r.NoError(err)
+	r.Empty(open)
+
+	e.Resume()
+	eventually(t, e, func(progress Progress) bool {
+		return progress.Orders == 2 && progress.WorkingOrderId != 0
+	})
+	paper.UpdatePrice("BTC-USDT", 99)
+
+	summary, err := e.Wait(waitContext(t))
+	r.NoError(err)
+	r.Equal(DoneStatus, summary.Status)
+	r.Equal(4, summary.Orders)
+	r.Equal(float64(3), summary.FilledQty)
+	r.Equal(float64(99), summary.AveragePrice)
+	r.InDelta(-0.01, summary.Slippage, 1e-9)
+}
+
+func TestCancel(t *testing.T) {
+	r := require.New(t)
+	paper, tracker := newPaperClient(t)
+
+	e, err := NewIceberg(paper.Client, tracker, &bingx.OrderRequest{Symbol: "BTC-USDT", Side: bingx.SellSideType, Price: 101, Quantity: 3}).
+		Visible(2).
+		Start(context.Background())
+	r.NoError(err)
+	eventually(t, e, func(progress Progress) bool {
+		return progress.WorkingOrderId != 0
+	})
+
+	e.Cancel()
+	summary, err := e.Wait(waitContext(t))
+	r.True(errors.Is(err, context.Canceled), err)
+	r.Equal(CanceledStatus, summary.Status)
+	r.Zero(summary.FilledQty)
+	r.Zero(summary.Slippage)
+	open, err := paper.GetOpenOrders(context.Background(), "BTC-USDT")
+	r.NoError(err)
+	r.Empty(open)
+}
+
+func TestChase(t *testing.T) {
+	r := require.New(t)
+	paper, tracker := newPaperClient(t)
+	quoter := &testQuoter{bid: 99, ask: 100}
+
+	e, err := NewChase(paper.Client, tracker, quoter, &bingx.OrderRequest{
+		Symbol:   "BTC-USDT",
+		Side:     bingx.BuySideType,
+		Price:    99.8,
+		Quantity: 2,
+	}).
+		Interval(10 * time.Millisecond).
+		Start(context.Background())
+	r.NoError(err)
+	first := eventually(t, e, func(progress Progress) bool {
+		return progress.WorkingOrderId != 0
+	})
+	order, err := tracker.Order(first.WorkingOrderId)
+	r.NoError(err)
+	r.Equal(float64(99), order.Price)
+
+	// order follows best bid up to limit price
+	quoter.set(99.9, 100)
+	second := eventually(t, e, func(progress Progress) bool {
+		return progress.Orders == 2 && progress.WorkingOrderId != 0
+	})
+	order, err = tracker.Order(second.WorkingOrderId)
+	r.NoError(err)
+	r.Equal(99.8, order.Price)
+	canceled, err := tracker.Order(first.WorkingOrderId)
+	r.NoError(err)
+	r.Equal(bingx.CanceledOrderStatus, canceled.Status)
+
+	paper.UpdatePrice("BTC-USDT", 99.8)
+	summary, err := e.Wait(waitContext(t))
+	r.NoError(err)
+	r.Equal(float64(2), summary.FilledQty)
+	r.Equal(99.8, summary.AveragePrice)
+	r.Equal(99.5, summary.ArrivalPrice)
+	r.InDelta(0.3/99.5, summary.Slippage, 1e-9)
+	// maker fee
+	r.InDelta(-2*99.8*0.0002, summary.Fee, 1e-9)
+}
+
+func TestChasePostOnly(t *testing.T) {
+	r := require.New(t)
+	paper, tracker := newPaperClient(t)
+	// stale quote crosses the market
+	quoter := &testQuoter{bid: 98.5, ask: 99}
+
+	e, err := NewChase(paper.Client, tracker, quoter, &bingx.OrderRequest{Symbol: "BTC-USDT", Side: bingx.SellSideType, Quantity: 1}).
+		Interval(10 * time.Millisecond).
+		Start(context.Background())
+	r.NoError(err)
+	eventually(t, e, func(progress Progress) bool {
+		return progress.Orders >= 1 && progress.WorkingOrderId == 0
+	})
+	r.Zero(e.Progress().FilledQty)
+
+	quoter.set(99.5, 100.5)
+	eventually(t, e, func(progress Progress) bool {
+		return progress.WorkingOrderId != 0
+	})
+	paper.UpdatePrice("BTC-USDT", 100.5)
+	summary, err := e.Wait(waitContext(t))
+	r.NoError(err)
+	r.Equal(100.5, summary.AveragePrice)
+}
@@ -0,0 +1,165 @@
+package algo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+const defaultChaseInterval = time.Second
+
+// Chase Define post only limit order kept at the best bid when buying and the best ask when
+// selling, it is replaced when price moves away. Create it with NewChase and run with Start.
+type Chase struct {
+	client          *bingx.Client
+	tracker         *bingx.OrderTracker
+	quoter          Quoter
+	order           *bingx.OrderRequest
+	interval        time.Duration
+	arrivalPrice    float64
+	progressHandler ProgressHandler
+}
+
+// NewChase Init chase of order quoted by quoter, Symbol, Side and Quantity are required. Price is
+// optional limit, buy order is not placed above it and sell order below it. PositionSide, ReduceOnly
+// and ClientOrderID are passed to child orders, the latter with sequence number suffix.
+func NewChase(client *bingx.Client, tracker *bingx.OrderTracker, quoter Quoter, order *bingx.OrderRequest) *Chase {
+	return &Chase{
+		client:   client,
+		tracker:  tracker,
+		quoter:   quoter,
+		order:    order,
+		interval: defaultChaseInterval,
+	}
+}
+
+// Interval Set period of quote checks, default is 1 second
+func (c *Chase) Interval(interval time.Duration) *Chase {
+	c.interval = interval
+	return c
+}
+
+// ArrivalPrice Set reference price of slippage, default is mid price at start
+func (c *Chase) ArrivalPrice(price float64) *Chase {
+	c.arrivalPrice = price
+	return c
+}
+
+// ProgressHandler Set handler of execution progress
+func (c *Chase) ProgressHandler(handler ProgressHandler) *Chase {
+	c.progressHandler = handler
+	return c
+}
+
+// Start Place order at the best price and keep chasing it in background until it is filled or ctx is done
+func (c *Chase) Start(ctx context.Context) (*Execution, error) {
+	if err := validate(c.order); err != nil {
+		return nil, err
+	}
+	if c.quoter == nil {
+		return nil, fmt.Errorf("%w: quoter is required", ErrInvalidAlgo)
+	}
+	if c.interval <= 0 {
+		return nil, fmt.Errorf("%w: interval must be positive", ErrInvalidAlgo)
+	}
+
+	arrivalPrice := c.arrivalPrice
+	if arrivalPrice == 0 {
+		arrivalPrice = midPrice(c.quoter)
+	}
+
+	e := newExecution(c.client, c.tracker, c.order, c.progressHandler)
+	e.start(ctx, arrivalPrice, func() error {
+		return c.run(e)
+	})
+	return e, nil
+}
+
+// quote Price of the same side of book limited by order price
+func (c *Chase) quote() (float64, bool) {
+	var level bingx.WsDepthLevel
+	var ok bool
+	if c.order.Side == bingx.BuySideType {
+		level, ok = c.quoter.BestBid()
+	} else {
+		level, ok = c.quoter.BestAsk()
+	}
+	if !ok || level.Price <= 0 {
+		return 0, false
+	}
+
+	price := level.Price
+	if c.order.Price > 0 && (c.order.Side == bingx.BuySideType) == (price > c.order.Price) {
+		price = c.order.Price
+	}
+	return price, true
+}
+
+// movedAway reports whether the best price left order behind
+func (c *Chase) movedAway(price float64) bool {
+	quote, ok := c.quote()
+	if !ok {
+		return false
+	}
+	if c.order.Side == bingx.BuySideType {
+		return quote > price
+	}
+	return quote < price
+}
+
+func (c *Chase) run(e *Execution) error {
+	for {
+		if err := e.checkpoint(); err != nil {
+			return err
+		}
+
+		quantity := e.remainingQty()
+		if quantity <= 0 {
+			return nil
+		}
+		price, ok := c.quote()
+		if !ok {
+			if err := e.sleep(c.interval); err != nil {
+				return err
+			}
+			continue
+		}
+
+		order, err := e.place(bingx.OrderRequest{
+			Type:        bingx.LimitOrderType,
+			TimeInForce: bingx.PostOnlyTimeInForceType,
+			Price:       price,
+			Quantity:    quantity,
+		})
+		if err != nil {
+			return err
+		}
+
+		var done *bingx.TrackedOrder
+		requoted := false
+		for done == nil {
+			if done, err = e.wait(order.OrderId, c.interval); err != nil {
+				return err
+			}
+			if done == nil && (e.interrupted() || c.movedAway(price)) {
+				requoted = true
+				if done, err = e.cancelOrder(order.OrderId); err != nil {
+					return err
+				}
+			}
+		}
+		e.settle(done)
+
+		switch {
+		case done.Status == bingx.ExpiredOrderStatus && !requoted:
+			// post only order crossed the book, wait for quote to catch up
+			if err := e.sleep(c.interval); err != nil {
+				return err
+			}
+		case done.Status == bingx.CanceledOrderStatus && !requoted:
+			return fmt.Errorf("algo: order %d is canceled", done.OrderId)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package algo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+// Iceberg Define limit order showing only a visible part of quantity, the next slice is placed
+// once the previous one is filled. Create it with NewIceberg and run with Start.
+type Iceberg struct {
+	client            *bingx.Client
+	tracker           *bingx.OrderTracker
+	order             *bingx.OrderRequest
+	visible           float64
+	quantityPrecision int
+	arrivalPrice      float64
+	quoter            Quoter
+	progressHandler   ProgressHandler
+}
+
+// NewIceberg Init iceberg of order, Symbol, Side, Price and Quantity are required. TimeInForce,
+// PositionSide, ReduceOnly and ClientOrderID are passed to child orders, the latter with sequence number suffix.
+func NewIceberg(client *bingx.Client, tracker *bingx.OrderTracker, order *bingx.OrderRequest) *Iceberg {
+	return &Iceberg{
+		client:            client,
+		tracker:           tracker,
+		order:             order,
+		quantityPrecision: -1,
+	}
+}
+
+// Visible Set quantity of every slice, required
+func (i *Iceberg) Visible(quantity float64) *Iceberg {
+	i.visible = quantity
+	return i
+}
+
+// QuantityPrecision Round slices down to precision decimals
+func (i *Iceberg) QuantityPrecision(precision int) *Iceberg {
+	i.quantityPrecision = precision
+	return i
+}
+
+// ArrivalPrice Set reference price of slippage
+func (i *Iceberg) ArrivalPrice(price float64) *Iceberg {
+	i.arrivalPrice = price
+	return i
+}
+
+// Quoter Set source of arrival price, mid price at start is used unless ArrivalPrice is set
+func (i *Iceberg) Quoter(quoter Quoter) *Iceberg {
+	i.quoter = quoter
+	return i
+}
+
+// ProgressHandler Set handler of execution progress
+func (i *Iceberg) ProgressHandler(handler ProgressHandler) *Iceberg {
+	i.progressHandler = handler
+	return i
+}
+
+// Start Place the first slice and keep replacing filled slices in background until ctx is done
+func (i *Iceberg) Start(ctx context.Context) (*Execution, error) {
+	if err := validate(i.order); err != nil {
+		return nil, err
+	}
+	if i.order.Price <= 0 {
+		return nil, fmt.Errorf("%w: price must be positive", ErrInvalidAlgo)
+	}
+	if i.visible <= 0 {
+		return nil, fmt.Errorf("%w: visible quantity must be positive", ErrInvalidAlgo)
+	}
+
+	arrivalPrice := i.arrivalPrice
+	if arrivalPrice == 0 {
+		arrivalPrice = midPrice(i.quoter)
+	}
+
+	e := newExecution(i.client, i.tracker, i.order, i.progressHandler)
+	e.start(ctx, arrivalPrice, func() error {
+		return i.run(e)
+	})
+	return e, nil
+}
+
+func (i *Iceberg) run(e *Execution) error {
+	for {
+		if err := e.checkpoint(); err != nil {
+			return err
+		}
+
+		quantity := roundQuantity(e.remainingQty(), i.quantityPrecision)
+		if quantity > i.visible {
+			quantity = i.visible
+		}
+		if quantity <= 0 {
+			return nil
+		}
+
+		order, completed, err := e.execute(bingx.OrderRequest{
+			Type:        bingx.LimitOrderType,
+			TimeInForce: i.order.TimeInForce,
+			Price:       i.order.Price,
+			Quantity:    quantity,
+		}, 0)
+		if err != nil {
+			return err
+		}
+		// slice canceled by pause is placed again after resume
+		if completed && order.Status != bingx.FilledOrderStatus {
+			return fmt.Errorf("algo: slice %d is %s", order.OrderId, order.Status)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package algo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+const defaultTWAPSlices = 10
+
+// TWAP Define time weighted execution, order quantity is split into market orders placed
+// evenly over duration. Create it with NewTWAP and run with Start.
+type TWAP struct {
+	client            *bingx.Client
+	tracker           *bingx.OrderTracker
+	order             *bingx.OrderRequest
+	duration          time.Duration
+	slices            int
+	quantityPrecision int
+	arrivalPrice      float64
+	quoter            Quoter
+	progressHandler   ProgressHandler
+}
+
+// NewTWAP Init TWAP of order, Symbol, Side and Quantity are required, PositionSide, ReduceOnly
+// and ClientOrderID are passed to child orders, the latter with sequence number suffix
+func NewTWAP(client *bingx.Client, tracker *bingx.OrderTracker, order *bingx.OrderRequest) *TWAP {
+	return &TWAP{
+		client:            client,
+		tracker:           tracker,
+		order:             order,
+		slices:            defaultTWAPSlices,
+		quantityPrecision: -1,
+	}
+}
+
+// Duration Set time between the first and the last slice
+func (t *TWAP) Duration(duration time.Duration) *TWAP {
+	t.duration = duration
+	return t
+}
+
+// Slices Set number of child orders, default is 10
+func (t *TWAP) Slices(slices int) *TWAP {
+	t.slices = slices
+	return t
+}
+
+// QuantityPrecision Round slices down to precision decimals, the last slice takes the rest
+func (t *TWAP) QuantityPrecision(precision int) *TWAP {
+	t.quantityPrecision = precision
+	return t
+}
+
+// ArrivalPrice Set reference price of slippage
+func (t *TWAP) ArrivalPrice(price float64) *TWAP {
+	t.arrivalPrice = price
+	return t
+}
+
+// Quoter Set source of arrival price, mid price at start is used unless ArrivalPrice is set
+func (t *TWAP) Quoter(quoter Quoter) *TWAP {
+	t.quoter = quoter
+	return t
+}
+
+// ProgressHandler Set handler of execution progress
+func (t *TWAP) ProgressHandler(handler ProgressHandler) *TWAP {
+	t.progressHandler = handler
+	return t
+}
+
+// Start Place the first slice immediately and the rest in background until ctx is done
+func (t *TWAP) Start(ctx context.Context) (*Execution, error) {
+	if err := validate(t.order); err != nil {
+		return nil, err
+	}
+	if t.slices <= 0 {
+		return nil, fmt.Errorf("%w: slices must be positive", ErrInvalidAlgo)
+	}
+	if t.duration < 0 {
+		return nil, fmt.Errorf("%w: duration must not be negative", ErrInvalidAlgo)
+	}
+
+	arrivalPrice := t.arrivalPrice
+	if arrivalPrice == 0 {
+		arrivalPrice = midPrice(t.quoter)
+	}
+
+	e := newExecution(t.client, t.tracker, t.order, t.progressHandler)
+	e.start(ctx, arrivalPrice, func() error {
+		return t.run(e)
+	})
+	return e, nil
+}
+
+func (t *TWAP) run(e *Execution) error {
+	var interval time.Duration
+	if t.slices > 1 {
+		interval = t.duration / time.Duration(t.slices-1)
+	}
+
+	for i := 0; i < t.slices; i++ {
+		if i > 0 {
+			if err := e.sleep(interval); err != nil {
+				return err
+			}
+		}
+
+		// catch up with schedule, slices which were not filled are added to the next one
+		quantity := roundQuantity(t.order.Quantity*float64(i+1)/float64(t.slices), t.quantityPrecision) - e.Progress().FilledQty
+		if i == t.slices-1 {
+			quantity = roundQuantity(e.remainingQty(), t.quantityPrecision)
+		}
+		if quantity <= 0 {
+			continue
+		}
+
+		for quantity > 0 {
+			if err := e.checkpoint(); err != nil {
+				return err
+			}
+			order, completed, err := e.execute(bingx.OrderRequest{Type: bingx.MarketOrderType, Quantity: quantity}, 0)
+			if err != nil {
+				return err
+			}
+			// market order is interrupted only by pause, the rest of slice is placed again after resume
+			if completed {
+				break
+			}
+			quantity = roundQuantity(quantity-order.FilledQty, t.quantityPrecision)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package bingx
+
+import (
+	"context"
+)
+
+// OrderRequest Define parameters of new order, zero values are not sent
+type OrderRequest struct {
+	Symbol        string
+	Type          OrderType
+	Side          SideType
+	PositionSide  PositionSideType
+	ClientOrderID string
+	ReduceOnly    bool
+	TimeInForce   TimeInForceType
+	Price         float64
+	Quantity      float64
+}
+
+// OrderAPI Define order operations, implemented by Client and PaperClient
+type OrderAPI interface {
+	CreateOrder(ctx context.Context, order *OrderRequest) (*CreateOrderResponse, error)
+	// CancelOrder Cancel order by id, or by client order id when orderId is zero
+	CancelOrder(ctx context.Context, symbol string, orderId int64, clientOrderID string) (*CancelOrderResponse, error)
+	CancelAllOrders(ctx context.Context, symbol string) (*CancelAllOrdersResponse, error)
+	// GetOrder Get order by id, or by client order id when orderId is zero
+	GetOrder(ctx context.Context, symbol string, orderId int64, clientOrderID string) (*GetOrderResponse, error)
+	// GetOpenOrders Open orders of symbol, of all symbols when it is empty
+	GetOpenOrders(ctx context.Context, symbol string) ([]*GetOrderResponse, error)
+}
+
+// AccountAPI Define account operations, implemented by Client and PaperClient
+type AccountAPI interface {
+	GetBalance(ctx context.Context) (*Balance, error)
+	// GetOpenPositions Positions of symbol, of all symbols when it is empty
+	GetOpenPositions(ctx context.Context, symbol string) ([]Position, error)
+	GetListenKey(ctx context.Context) (string, error)
+}
+
+// MarketDataAPI Define market data requests, implemented by Client
+type MarketDataAPI interface {
+	GetServerTime(ctx context.Context) (int64, error)
+	// GetKlines Klines of symbol in milliseconds range, zero start, end or limit are not sent
+	GetKlines(ctx context.Context, symbol string, interval Interval, startTime, endTime, limit int64) ([]*Kline, error)
+	GetContracts(ctx context.Context, symbol string) ([]*Contract, error)
+	GetDepth(ctx context.Context, symbol string, limit int) (*Depth, error)
+	GetTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error)
+	GetTicker(ctx context.Context, symbol string) ([]*Ticker, error)
+	GetPremiumIndex(ctx context.Context, symbol string) ([]*PremiumIndex, error)
+	GetFundingRate(ctx context.Context, symbol string, startTime, endTime int64, limit int) ([]*FundingRate, error)
+	GetOpenInterest(ctx context.Context, symbol string) (*OpenInterest, error)
+}
+
+// StreamAPI Define websocket streams, implemented by Client with Ws*Serve functions
+type StreamAPI interface {
+	WsKlineServe(ctx context.Context, symbol string, interval Interval, handler WsKlineHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error)
+	WsDepthServe(ctx context.Context, symbol string, level int, handler WsDepthHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error)
+	WsTradeServe(ctx context.Context, symbol string, handler WsTradeHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error)
+	WsTickerServe(ctx context.Context, symbol string, handler WsTickerHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error)
+	WsMarkPriceServe(ctx context.Context, symbol string, handler WsMarkPriceHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error)
+	WsBookTickerServe(ctx context.Context, symbol string, handler WsBookTickerHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error)
+	WsLastPriceServe(ctx context.Context, symbol string, handler WsLastPriceHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error)
+	WsOrderUpdateServe(ctx context.Context, listenKey string, handler WsOrderUpdateHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error)
+}
+
+var (
+	_ OrderAPI      = (*Client)(nil)
+	_ AccountAPI    = (*Client)(nil)
+	_ MarketDataAPI = (*Client)(nil)
+	_ StreamAPI     = (*Client)(nil)
+)
+
+func (c *Client) CreateOrder(ctx context.Context, order *OrderRequest) (*CreateOrderResponse, error) {
+	s := c.NewCreateOrderService().
+		Symbol(order.Symbol).
+		Type(order.Type).
+		Side(order.Side).
+		PositionSide(order.PositionSide).
+		ClientOrderID(order.ClientOrderID).
+		TimeInForce(order.TimeInForce).
+		Price(order.Price).
+		Quantity(order.Quantity)
+	if order.ReduceOnly {
+		s.ReduceOnly()
+	}
+	return s.Do(ctx)
+}
+
+func (c *Client) CancelOrder(ctx context.Context, symbol string, orderId int64, clientOrderID string) (*CancelOrderResponse, error) {
+	return c.NewCancelOrderService().Symbol(symbol).OrderId(orderId).ClientOrderId(clientOrderID).Do(ctx)
+}
+
+func (c *Client) CancelAllOrders(ctx context.Context, symbol string) (*CancelAllOrdersResponse, error) {
+	return c.NewCancelAllOrdersService().Symbol(symbol).Do(ctx)
+}
+
+func (c *Client) GetOrder(ctx context.Context, symbol string, orderId int64, clientOrderID string) (*GetOrderResponse, error) {
+	return c.NewGetOrderService().Symbol(symbol).OrderId(orderId).ClientOrderId(clientOrderID).Do(ctx)
+}
+
+func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]*GetOrderResponse, error) {
+	res, err := c.NewGetOpenOrdersService().Symbol(symbol).Do(ctx)
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return res.Orders, nil
+}
+
+func (c *Client) GetBalance(ctx context.Context) (*Balance, error) {
+	return c.NewGetBalanceService().Do(ctx)
+}
+
+func (c *Client) GetOpenPositions(ctx context.Context, symbol string) ([]Position, error) {
+	res, err := c.NewGetOpenPositionsService().Symbol(symbol).Do(ctx)
+	if err != nil || res == nil {
+		return nil, err
+	}
+	return *res, nil
+}
+
+func (c *Client) GetListenKey(ctx context.Context) (string, error) {
+	return c.NewGetAccountListenKeyService().Do(ctx)
+}
+
+func (c *Client) GetServerTime(ctx context.Context) (int64, error) {
+	return c.NewGetServerTimeService().Do(ctx)
+}
+
+func (c *Client) GetKlines(ctx context.Context, symbol string, interval Interval, startTime, endTime, limit int64) ([]*Kline, error) {
+	return c.NewGetKlinesService().Symbol(symbol).Interval(interval).StartTime(startTime).EndTime(endTime).Limit(limit).Do(ctx)
+}
+
+func (c *Client) GetContracts(ctx context.Context, symbol string) ([]*Contract, error) {
+	return c.NewGetContractsService().Symbol(symbol).Do(ctx)
+}
+
+func (c *Client) GetDepth(ctx context.Context, symbol string, limit int) (*Depth, error) {
+	return c.NewGetDepthService().Symbol(symbol).Limit(limit).Do(ctx)
+}
+
+func (c *Client) GetTrades(ctx context.Context, symbol string, limit int) ([]*Trade, error) {
+	return c.NewGetTradesService().Symbol(symbol).Limit(limit).Do(ctx)
+}
+
+func (c *Client) GetTicker(ctx context.Context, symbol string) ([]*Ticker, error) {
+	return c.NewGetTickerService().Symbol(symbol).Do(ctx)
+}
+
+func (c *Client) GetPremiumIndex(ctx context.Context, symbol string) ([]*PremiumIndex, error) {
+	return c.NewGetPremiumIndexService().Symbol(symbol).Do(ctx)
+}
+
+func (c *Client) GetFundingRate(ctx context.Context, symbol string, startTime, endTime int64, limit int) ([]*FundingRate, error) {
+	return c.NewGetFundingRateService().Symbol(symbol).StartTime(startTime).EndTime(endTime).Limit(limit).Do(ctx)
+}
+
+func (c *Client) GetOpenInterest(ctx context.Context, symbol string) (*OpenInterest, error) {
+	return c.NewGetOpenInterestService().Symbol(symbol).Do(ctx)
+}
+
+func (c *Client) WsKlineServe(ctx context.Context, symbol string, interval Interval, handler WsKlineHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsKlineServe(ctx, symbol, interval, handler, errHandler, opts...)
+}
+
+func (c *Client) WsDepthServe(ctx context.Context, symbol string, level int, handler WsDepthHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsDepthServe(ctx, symbol, level, handler, errHandler, opts...)
+}
+
+func (c *Client) WsTradeServe(ctx context.Context, symbol string, handler WsTradeHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsTradeServe(ctx, symbol, handler, errHandler, opts...)
+}
+
+func (c *Client) WsTickerServe(ctx context.Context, symbol string, handler WsTickerHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsTickerServe(ctx, symbol, handler, errHandler, opts...)
+}
+
+func (c *Client) WsMarkPriceServe(ctx context.Context, symbol string, handler WsMarkPriceHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsMarkPriceServe(ctx, symbol, handler, errHandler, opts...)
+}
+
+func (c *Client) WsBookTickerServe(ctx context.Context, symbol string, handler WsBookTickerHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsBookTickerServe(ctx, symbol, handler, errHandler, opts...)
+}
+
+func (c *Client) WsLastPriceServe(ctx context.Context, symbol string, handler WsLastPriceHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsLastPriceServe(ctx, symbol, handler, errHandler, opts...)
+}
+
+func (c *Client) WsOrderUpdateServe(ctx context.Context, listenKey string, handler WsOrderUpdateHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsOrderUpdateServe(ctx, listenKey, handler, errHandler, opts...)
+}
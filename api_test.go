@@ -0,0 +1,85 @@
+package bingx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type apiTestSuite struct {
+	serverTestSuite
+}
+
+func TestAPI(t *testing.T) {
+	suite.Run(t, new(apiTestSuite))
+}
+
+// trade uses operations only through interfaces
+func trade(orders OrderAPI, account AccountAPI) ([]Position, error) {
+	ctx := newContext()
+	if _, err := orders.CreateOrder(ctx, &OrderRequest{
+		Symbol:   "BTC-USDT",
+		Type:     MarketOrderType,
+		Side:     BuySideType,
+		Quantity: 0.1,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := orders.CreateOrder(ctx, &OrderRequest{
+		Symbol:        "BTC-USDT",
+		Type:          LimitOrderType,
+		Side:          SellSideType,
+		ClientOrderID: "take-profit",
+		ReduceOnly:    true,
+		Price:         44000,
+		Quantity:      0.1,
+	}); err != nil {
+		return nil, err
+	}
+	return account.GetOpenPositions(ctx, "BTC-USDT")
+}
+
+func (s *apiTestSuite) TestClient() {
+	r := s.r()
+	s.server.SetPrice("BTC-USDT", 43000)
+
+	positions, err := trade(s.client, s.client)
+	r.NoError(err)
+	r.Len(positions, 1)
+	r.Equal("0.1", positions[0].PositionAmt)
+
+	open, err := s.client.GetOpenOrders(newContext(), "BTC-USDT")
+	r.NoError(err)
+	r.Len(open, 1)
+	r.True(open[0].ReduceOnly)
+
+	order, err := s.client.GetOrder(newContext(), "BTC-USDT", 0, "take-profit")
+	r.NoError(err)
+	r.Equal(open[0].OrderId, order.OrderId)
+	canceled, err := s.client.CancelOrder(newContext(), "BTC-USDT", order.OrderId, "")
+	r.NoError(err)
+	r.Equal(CanceledOrderStatus, canceled.Status)
+
+	balance, err := s.client.GetBalance(newContext())
+	r.NoError(err)
+	r.Equal("USDT", balance.Asset)
+	serverTime, err := s.client.GetServerTime(newContext())
+	r.NoError(err)
+	r.NotZero(serverTime)
+}
+
+func (s *apiTestSuite) TestPaperClient() {
+	r := s.r()
+	paper := NewPaperClient(s.client).Balance(10000)
+	paper.UpdatePrice("BTC-USDT", 43000)
+
+	positions, err := trade(paper, paper)
+	r.NoError(err)
+	r.Len(positions, 1)
+
+	paper.UpdatePrice("BTC-USDT", 44000)
+	positions, err = paper.GetOpenPositions(newContext(), "")
+	r.NoError(err)
+	r.Empty(positions)
+	r.Empty(s.server.Requests())
+}
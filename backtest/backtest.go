@@ -0,0 +1,208 @@
+// Package backtest replays klines through trading strategy with simulated fills, fees and funding.
+// Strategy places orders with the same bingx.Client services it uses in paper and live trading.
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+const (
+	defaultBalance         = 10000
+	defaultFundingInterval = 8 * time.Hour
+)
+
+// ErrUnavailable request can not be served in backtest, e.g. market data request
+var ErrUnavailable = errors.New("backtest: request is not available in backtest")
+
+// Strategy Define callback called on close of every kline. Client serves order, position and
+// balance services in simulation, the same callback may be run by bingx.KlineStream in live trading.
+// Returned error stops backtest.
+type Strategy func(ctx context.Context, client *bingx.Client, kline *bingx.WsKlineEvent) error
+
+// Backtest Define simulation settings, create it with New and run with Run
+type Backtest struct {
+	symbol          string
+	interval        bingx.Interval
+	balance         float64
+	leverage        int
+	makerFee        float64
+	takerFee        float64
+	slippage        float64
+	fundingRate     float64
+	fundingInterval time.Duration
+	feesSet         bool
+}
+
+func New(symbol string, interval bingx.Interval) *Backtest {
+	return &Backtest{
+		symbol:          symbol,
+		interval:        interval,
+		balance:         defaultBalance,
+		leverage:        1,
+		fundingInterval: defaultFundingInterval,
+	}
+}
+
+// Balance Set initial balance in USDT, default is 10000
+func (b *Backtest) Balance(balance float64) *Backtest {
+	b.balance = balance
+	return b
+}
+
+// Leverage Set leverage of symbol, default is 1
+func (b *Backtest) Leverage(leverage int) *Backtest {
+	b.leverage = leverage
+	return b
+}
+
+// Fees Set fee rates of resting (maker) and immediately filled (taker) orders, default are rates of PaperClient
+func (b *Backtest) Fees(maker, taker float64) *Backtest {
+	b.makerFee, b.takerFee, b.feesSet = maker, taker, true
+	return b
+}
+
+// Slippage Set price move against orders taking liquidity as share of price
+func (b *Backtest) Slippage(rate float64) *Backtest {
+	b.slippage = rate
+	return b
+}
+
+// Funding Set funding rate charged every interval at open price of kline, zero interval keeps 8 hours
+func (b *Backtest) Funding(rate float64, interval time.Duration) *Backtest {
+	b.fundingRate = rate
+	if interval > 0 {
+		b.fundingInterval = interval
+	}
+	return b
+}
+
+// Run Replay klines through strategy. Prices move within kline from open to the nearest extreme,
+// then to the other one and to close, resting limit orders are filled on the way.
+// Strategy is called at close of kline, market orders it places are filled at close price.
+func (b *Backtest) Run(ctx context.Context, klines []*bingx.Kline, strategy Strategy) (*Report, error) {
+	if !b.interval.Valid() {
+		return nil, fmt.Errorf("backtest: unsupported kline interval %q", b.interval)
+	}
+
+	events, err := parseKlines(b.symbol, klines)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Time{}
+	client := bingx.NewClient("", "")
+	client.HTTPClient = &http.Client{Transport: unavailableTransport{}}
+	paper := bingx.NewPaperClient(client).
+		Balance(b.balance).
+		Leverage(b.symbol, b.leverage).
+		Slippage(b.slippage).
+		Clock(func() time.Time {
+			return now
+		})
+	if b.feesSet {
+		paper.Fees(b.makerFee, b.takerFee)
+	}
+
+	report := &Report{InitialBalance: b.balance}
+	paper.OrderUpdateHandler(func(order *bingx.WsOrder) {
+		if order.Spec == bingx.TradeOrderSpecType {
+			report.addTrade(order)
+		}
+	})
+	paper.AccountUpdateHandler(func(event *bingx.WsAccountUpdateEvent) {
+		if event.Update.Reason == "FUNDING_FEE" {
+			change, _ := strconv.ParseFloat(event.Update.Balances[0].BalanceChange, 64)
+			report.Funding -= change
+		}
+	})
+
+	var lastFunding time.Time
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		openTime := time.UnixMilli(int64(event.Time)).UTC()
+		now = openTime
+		if b.fundingRate != 0 {
+			funding := openTime.Truncate(b.fundingInterval)
+			if !lastFunding.IsZero() && funding.After(lastFunding) {
+				paper.UpdatePrice(b.symbol, event.Open)
+				paper.ApplyFunding(b.symbol, b.fundingRate)
+			}
+			lastFunding = funding
+		}
+		for _, price := range pricePath(event) {
+			paper.UpdatePrice(b.symbol, price)
+		}
+
+		now = b.interval.Next(openTime).Add(-time.Millisecond)
+		if err := strategy(ctx, paper.Client, event); err != nil {
+			return nil, fmt.Errorf("backtest: strategy failed at %s: %w", openTime.Format(time.RFC3339), err)
+		}
+
+		balance, err := paper.NewGetBalanceService().Do(ctx)
+		if err != nil {
+			return nil, err
+		}
+		equity, err := strconv.ParseFloat(balance.Equity, 64)
+		if err != nil {
+			return nil, err
+		}
+		report.Equity = append(report.Equity, EquityPoint{Time: now, Equity: equity})
+	}
+
+	report.finish(b.interval)
+	return report, nil
+}
+
+// pricePath prices kline passes, extreme closer to open is visited first
+func pricePath(kline *bingx.WsKlineEvent) []float64 {
+	if kline.High-kline.Open < kline.Open-kline.Low {
+		return []float64{kline.Open, kline.High, kline.Low, kline.Close}
+	}
+	return []float64{kline.Open, kline.Low, kline.High, kline.Close}
+}
+
+// parseKlines converts klines to closed kline events sorted by open time
+func parseKlines(symbol string, klines []*bingx.Kline) ([]*bingx.WsKlineEvent, error) {
+	events := make([]*bingx.WsKlineEvent, 0, len(klines))
+	for _, kline := range klines {
+		var values [5]float64
+		for i, v := range []string{kline.Open, kline.High, kline.Low, kline.Close, kline.Volume} {
+			value, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("backtest: invalid kline at %d: %w", kline.Time, err)
+			}
+			values[i] = value
+		}
+		events = append(events, &bingx.WsKlineEvent{
+			Symbol:    symbol,
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    values[4],
+			Time:      float64(kline.Time),
+			Completed: true,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time < events[j].Time
+	})
+	return events, nil
+}
+
+type unavailableTransport struct{}
+
+func (unavailableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("%w: %s %s", ErrUnavailable, req.Method, req.URL.Path)
+}
@@ -0,0 +1,154 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+	"github.com/stretchr/testify/require"
+)
+
+func testKlines(prices ...[4]float64) []*bingx.Kline {
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	klines := make([]*bingx.Kline, len(prices))
+	for i, p := range prices {
+		klines[i] = &bingx.Kline{
+			Time:   int64(i) * time.Hour.Milliseconds(),
+			Open:   format(p[0]),
+			High:   format(p[1]),
+			Low:    format(p[2]),
+			Close:  format(p[3]),
+			Volume: "1",
+		}
+	}
+	return klines
+}
+
+func createOrder(ctx context.Context, client *bingx.Client, orderType bingx.OrderType, side bingx.SideType, price float64) error {
+	s := client.NewCreateOrderService().Symbol("BTC-USDT").Type(orderType).Side(side).Price(price).Quantity(1)
+	if orderType == bingx.LimitOrderType {
+		s.ReduceOnly()
+	}
+	_, err := s.Do(ctx)
+	return err
+}
+
+func TestRun(t *testing.T) {
+	r := require.New(t)
+	klines := testKlines(
+		[4]float64{105, 111, 104, 110},
+		[4]float64{110, 110, 95, 96},
+		[4]float64{100, 101, 99, 100},
+		[4]float64{100, 106, 100, 105},
+	)
+	// stored series are not guaranteed to be sorted
+	klines[0].Time, klines[1].Time, klines[2].Time, klines[3].Time = 2*3600000, 3*3600000, 0, 3600000
+
+	var closes []float64
+	report, err := New("BTC-USDT", bingx.Interval60).
+		Balance(1000).
+		Fees(0, 0.001).
+		Run(context.Background(), klines, func(ctx context.Context, client *bingx.Client, kline *bingx.WsKlineEvent) error {
+			closes = append(closes, kline.Close)
+			switch len(closes) {
+			case 1:
+				if err := createOrder(ctx, client, bingx.MarketOrderType, bingx.BuySideType, 0); err != nil {
+					return err
+				}
+				return createOrder(ctx, client, bingx.LimitOrderType, bingx.SellSideType, 110)
+			case 3:
+				positions, err := client.NewGetOpenPositionsService().Do(ctx)
+				if err != nil || len(*positions) > 0 {
+					return err
+				}
+				return createOrder(ctx, client, bingx.MarketOrderType, bingx.SellSideType, 0)
+			}
+			return nil
+		})
+	r.NoError(err)
+	r.Equal([]float64{100, 105, 110, 96}, closes)
+
+	var equity []float64
+	for _, point := range report.Equity {
+		equity = append(equity, point.Equity)
+	}
+	r.InDeltaSlice([]float64{999.9, 1004.9, 1009.79, 1023.79}, equity, 1e-9)
+	r.Equal(time.UnixMilli(3600000-1).UTC(), report.Equity[0].Time)
+
+	r.Len(report.Trades, 3)
+	r.Equal(Trade{
+		Time:         time.UnixMilli(3600000 - 1).UTC(),
+		OrderId:      1,
+		Side:         bingx.BuySideType,
+		PositionSide: bingx.BothPositionSideType,
+		Price:        100,
+		Quantity:     1,
+		Fee:          0.1,
+	}, report.Trades[0])
+	r.Equal(time.UnixMilli(2*3600000).UTC(), report.Trades[1].Time)
+	r.Equal(float64(110), report.Trades[1].Price)
+	r.Equal(float64(10), report.Trades[1].RealizedPnl)
+	r.Equal(bingx.SellSideType, report.Trades[2].Side)
+
+	r.InDelta(1023.79, report.FinalEquity, 1e-9)
+	r.InDelta(0.02379, report.Return, 1e-9)
+	r.InDelta(0.0001, report.MaxDrawdown, 1e-9)
+	r.InDelta(0.21, report.Fees, 1e-9)
+	r.Equal(float64(1), report.WinRate)
+	r.Greater(report.Sharpe, float64(0))
+	r.Zero(report.Funding)
+}
+
+func TestFundingAndSlippage(t *testing.T) {
+	r := require.New(t)
+	klines := testKlines(
+		[4]float64{100, 100, 100, 100},
+		[4]float64{100, 100, 100, 100},
+		[4]float64{105, 105, 105, 105},
+		[4]float64{110, 110, 110, 110},
+	)
+
+	report, err := New("BTC-USDT", bingx.Interval60).
+		Balance(1000).
+		Fees(0, 0).
+		Slippage(0.01).
+		Funding(0.001, time.Hour).
+		Run(context.Background(), klines, func(ctx context.Context, client *bingx.Client, kline *bingx.WsKlineEvent) error {
+			if kline.Time == 0 {
+				return createOrder(ctx, client, bingx.MarketOrderType, bingx.BuySideType, 0)
+			}
+			return nil
+		})
+	r.NoError(err)
+
+	r.Len(report.Trades, 1)
+	r.Equal(float64(101), report.Trades[0].Price)
+	r.InDelta(0.1+0.105+0.11, report.Funding, 1e-9)
+	r.InDelta(1000+9-0.315, report.FinalEquity, 1e-9)
+}
+
+func TestStrategyError(t *testing.T) {
+	r := require.New(t)
+	klines := testKlines([4]float64{100, 100, 100, 100}, [4]float64{100, 100, 100, 100})
+
+	var calls int
+	_, err := New("BTC-USDT", bingx.Interval60).Run(context.Background(), klines, func(ctx context.Context, client *bingx.Client, kline *bingx.WsKlineEvent) error {
+		calls++
+		_, err := client.NewGetServerTimeService().Do(ctx)
+		return err
+	})
+	r.True(errors.Is(err, ErrUnavailable), err)
+	r.Equal(1, calls)
+
+	_, err = New("BTC-USDT", "7m").Run(context.Background(), klines, nil)
+	r.EqualError(err, `backtest: unsupported kline interval "7m"`)
+
+	klines[1].Close = "x"
+	_, err = New("BTC-USDT", bingx.Interval60).Run(context.Background(), klines, nil)
+	r.Error(err)
+}
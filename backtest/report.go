@@ -0,0 +1,136 @@
+package backtest
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+// EquityPoint Define account equity at close of kline
+type EquityPoint struct {
+	Time   time.Time
+	Equity float64
+}
+
+// Trade Define fill of order
+type Trade struct {
+	Time          time.Time
+	OrderId       int64
+	ClientOrderID string
+	Side          bingx.SideType
+	PositionSide  bingx.PositionSideType
+	Price         float64
+	Quantity      float64
+	// Fee paid for trade, positive
+	Fee float64
+	// RealizedPnl profit of closed part of position, fee excluded
+	RealizedPnl float64
+}
+
+// Report Define result of backtest
+type Report struct {
+	InitialBalance float64
+	FinalEquity    float64
+	// Return of final equity to initial balance, 0.1 is 10%
+	Return float64
+	// MaxDrawdown largest drop of equity from its peak as share of the peak
+	MaxDrawdown float64
+	// Sharpe annualized Sharpe ratio of per kline returns, risk free rate is zero
+	Sharpe float64
+	// WinRate share of trades closing position with profit among all closing trades
+	WinRate float64
+	Fees    float64
+	// Funding paid, negative if received
+	Funding float64
+	Equity  []EquityPoint
+	Trades  []Trade
+}
+
+func (r *Report) addTrade(order *bingx.WsOrder) {
+	price, _ := strconv.ParseFloat(order.LastFilledPrice, 64)
+	if price == 0 {
+		price, _ = strconv.ParseFloat(order.AveragePrice, 64)
+	}
+	qty, _ := strconv.ParseFloat(order.LastFilledQty, 64)
+	fee, _ := strconv.ParseFloat(order.Fee, 64)
+	pnl, _ := strconv.ParseFloat(order.RealizedPnl, 64)
+
+	r.Trades = append(r.Trades, Trade{
+		Time:          time.UnixMilli(int64(order.Timestamp)).UTC(),
+		OrderId:       order.OrderId,
+		ClientOrderID: order.ClientOrderID,
+		Side:          order.Side,
+		PositionSide:  order.PositionSide,
+		Price:         price,
+		Quantity:      qty,
+		Fee:           -fee,
+		RealizedPnl:   pnl,
+	})
+	r.Fees -= fee
+}
+
+func (r *Report) finish(interval bingx.Interval) {
+	r.FinalEquity = r.InitialBalance
+	if len(r.Equity) > 0 {
+		r.FinalEquity = r.Equity[len(r.Equity)-1].Equity
+	}
+	if r.InitialBalance > 0 {
+		r.Return = r.FinalEquity/r.InitialBalance - 1
+	}
+
+	peak := r.InitialBalance
+	previous := r.InitialBalance
+	var returns []float64
+	for _, point := range r.Equity {
+		if point.Equity > peak {
+			peak = point.Equity
+		}
+		if peak > 0 {
+			if drawdown := (peak - point.Equity) / peak; drawdown > r.MaxDrawdown {
+				r.MaxDrawdown = drawdown
+			}
+		}
+		if previous > 0 {
+			returns = append(returns, point.Equity/previous-1)
+		}
+		previous = point.Equity
+	}
+	r.Sharpe = sharpe(returns, float64(365*24*time.Hour)/float64(interval.Duration()))
+
+	var closing, wins int
+	for _, trade := range r.Trades {
+		if trade.RealizedPnl != 0 {
+			closing++
+			if trade.RealizedPnl > 0 {
+				wins++
+			}
+		}
+	}
+	if closing > 0 {
+		r.WinRate = float64(wins) / float64(closing)
+	}
+}
+
+// sharpe annualized ratio of mean to standard deviation of returns
+func sharpe(returns []float64, periodsPerYear float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	var mean float64
+	for _, v := range returns {
+		mean += v
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, v := range returns {
+		variance += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	if std == 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(periodsPerYear)
+}
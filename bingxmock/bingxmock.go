@@ -0,0 +1,187 @@
+// Package bingxmock provides testify mocks of bingx client interfaces, so code depending on
+// OrderAPI, AccountAPI, MarketDataAPI or StreamAPI can be unit tested without a server.
+package bingxmock
+
+import (
+	"context"
+
+	"github.com/magicaleks/go-bingx"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	_ bingx.OrderAPI      = (*OrderAPI)(nil)
+	_ bingx.AccountAPI    = (*AccountAPI)(nil)
+	_ bingx.MarketDataAPI = (*MarketDataAPI)(nil)
+	_ bingx.StreamAPI     = (*StreamAPI)(nil)
+)
+
+// OrderAPI Define mock of bingx.OrderAPI
+type OrderAPI struct {
+	mock.Mock
+}
+
+func (m *OrderAPI) CreateOrder(ctx context.Context, order *bingx.OrderRequest) (*bingx.CreateOrderResponse, error) {
+	args := m.Called(ctx, order)
+	res, _ := args.Get(0).(*bingx.CreateOrderResponse)
+	return res, args.Error(1)
+}
+
+func (m *OrderAPI) CancelOrder(ctx context.Context, symbol string, orderId int64, clientOrderID string) (*bingx.CancelOrderResponse, error) {
+	args := m.Called(ctx, symbol, orderId, clientOrderID)
+	res, _ := args.Get(0).(*bingx.CancelOrderResponse)
+	return res, args.Error(1)
+}
+
+func (m *OrderAPI) CancelAllOrders(ctx context.Context, symbol string) (*bingx.CancelAllOrdersResponse, error) {
+	args := m.Called(ctx, symbol)
+	res, _ := args.Get(0).(*bingx.CancelAllOrdersResponse)
+	return res, args.Error(1)
+}
+
+func (m *OrderAPI) GetOrder(ctx context.Context, symbol string, orderId int64, clientOrderID string) (*bingx.GetOrderResponse, error) {
+	args := m.Called(ctx, symbol, orderId, clientOrderID)
+	res, _ := args.Get(0).(*bingx.GetOrderResponse)
+	return res, args.Error(1)
+}
+
+func (m *OrderAPI) GetOpenOrders(ctx context.Context, symbol string) ([]*bingx.GetOrderResponse, error) {
+	args := m.Called(ctx, symbol)
+	res, _ := args.Get(0).([]*bingx.GetOrderResponse)
+	return res, args.Error(1)
+}
+
+// AccountAPI Define mock of bingx.AccountAPI
+type AccountAPI struct {
+	mock.Mock
+}
+
+func (m *AccountAPI) GetBalance(ctx context.Context) (*bingx.Balance, error) {
+	args := m.Called(ctx)
+	res, _ := args.Get(0).(*bingx.Balance)
+	return res, args.Error(1)
+}
+
+func (m *AccountAPI) GetOpenPositions(ctx context.Context, symbol string) ([]bingx.Position, error) {
+	args := m.Called(ctx, symbol)
+	res, _ := args.Get(0).([]bingx.Position)
+	return res, args.Error(1)
+}
+
+func (m *AccountAPI) GetListenKey(ctx context.Context) (string, error) {
+	args := m.Called(ctx)
+	res, _ := args.Get(0).(string)
+	return res, args.Error(1)
+}
+
+// MarketDataAPI Define mock of bingx.MarketDataAPI
+type MarketDataAPI struct {
+	mock.Mock
+}
+
+func (m *MarketDataAPI) GetServerTime(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	res, _ := args.Get(0).(int64)
+	return res, args.Error(1)
+}
+
+func (m *MarketDataAPI) GetKlines(ctx context.Context, symbol string, interval bingx.Interval, startTime, endTime, limit int64) ([]*bingx.Kline, error) {
+	args := m.Called(ctx, symbol, interval, startTime, endTime, limit)
+	res, _ := args.Get(0).([]*bingx.Kline)
+	return res, args.Error(1)
+}
+
+func (m *MarketDataAPI) GetContracts(ctx context.Context, symbol string) ([]*bingx.Contract, error) {
+	args := m.Called(ctx, symbol)
+	res, _ := args.Get(0).([]*bingx.Contract)
+	return res, args.Error(1)
+}
+
+func (m *MarketDataAPI) GetDepth(ctx context.Context, symbol string, limit int) (*bingx.Depth, error) {
+	args := m.Called(ctx, symbol, limit)
+	res, _ := args.Get(0).(*bingx.Depth)
+	return res, args.Error(1)
+}
+
+func (m *MarketDataAPI) GetTrades(ctx context.Context, symbol string, limit int) ([]*bingx.Trade, error) {
+	args := m.Called(ctx, symbol, limit)
+	res, _ := args.Get(0).([]*bingx.Trade)
+	return res, args.Error(1)
+}
+
+func (m *MarketDataAPI) GetTicker(ctx context.Context, symbol string) ([]*bingx.Ticker, error) {
+	args := m.Called(ctx, symbol)
+	res, _ := args.Get(0).([]*bingx.Ticker)
+	return res, args.Error(1)
+}
+
+func (m *MarketDataAPI) GetPremiumIndex(ctx context.Context, symbol string) ([]*bingx.PremiumIndex, error) {
+	args := m.Called(ctx, symbol)
+	res, _ := args.Get(0).([]*bingx.PremiumIndex)
+	return res, args.Error(1)
+}
+
+func (m *MarketDataAPI) GetFundingRate(ctx context.Context, symbol string, startTime, endTime int64, limit int) ([]*bingx.FundingRate, error) {
+	args := m.Called(ctx, symbol, startTime, endTime, limit)
+	res, _ := args.Get(0).([]*bingx.FundingRate)
+	return res, args.Error(1)
+}
+
+func (m *MarketDataAPI) GetOpenInterest(ctx context.Context, symbol string) (*bingx.OpenInterest, error) {
+	args := m.Called(ctx, symbol)
+	res, _ := args.Get(0).(*bingx.OpenInterest)
+	return res, args.Error(1)
+}
+
+// StreamAPI Define mock of bingx.StreamAPI
+type StreamAPI struct {
+	mock.Mock
+}
+
+func (m *StreamAPI) WsKlineServe(ctx context.Context, symbol string, interval bingx.Interval, handler bingx.WsKlineHandler, errHandler bingx.ErrHandler, opts ...bingx.WsOption) (*bingx.Stream, error) {
+	args := m.Called(ctx, symbol, interval, handler, errHandler, opts)
+	res, _ := args.Get(0).(*bingx.Stream)
+	return res, args.Error(1)
+}
+
+func (m *StreamAPI) WsDepthServe(ctx context.Context, symbol string, level int, handler bingx.WsDepthHandler, errHandler bingx.ErrHandler, opts ...bingx.WsOption) (*bingx.Stream, error) {
+	args := m.Called(ctx, symbol, level, handler, errHandler, opts)
+	res, _ := args.Get(0).(*bingx.Stream)
+	return res, args.Error(1)
+}
+
+func (m *StreamAPI) WsTradeServe(ctx context.Context, symbol string, handler bingx.WsTradeHandler, errHandler bingx.ErrHandler, opts ...bingx.WsOption) (*bingx.Stream, error) {
+	args := m.Called(ctx, symbol, handler, errHandler, opts)
+	res, _ := args.Get(0).(*bingx.Stream)
+	return res, args.Error(1)
+}
+
+func (m *StreamAPI) WsTickerServe(ctx context.Context, symbol string, handler bingx.WsTickerHandler, errHandler bingx.ErrHandler, opts ...bingx.WsOption) (*bingx.Stream, error) {
+	args := m.Called(ctx, symbol, handler, errHandler, opts)
+	res, _ := args.Get(0).(*bingx.Stream)
+	return res, args.Error(1)
+}
+
+func (m *StreamAPI) WsMarkPriceServe(ctx context.Context, symbol string, handler bingx.WsMarkPriceHandler, errHandler bingx.ErrHandler, opts ...bingx.WsOption) (*bingx.Stream, error) {
+	args := m.Called(ctx, symbol, handler, errHandler, opts)
+	res, _ := args.Get(0).(*bingx.Stream)
+	return res, args.Error(1)
+}
+
+func (m *StreamAPI) WsBookTickerServe(ctx context.Context, symbol string, handler bingx.WsBookTickerHandler, errHandler bingx.ErrHandler, opts ...bingx.WsOption) (*bingx.Stream, error) {
+	args := m.Called(ctx, symbol, handler, errHandler, opts)
+	res, _ := args.Get(0).(*bingx.Stream)
+	return res, args.Error(1)
+}
+
+func (m *StreamAPI) WsLastPriceServe(ctx context.Context, symbol string, handler bingx.WsLastPriceHandler, errHandler bingx.ErrHandler, opts ...bingx.WsOption) (*bingx.Stream, error) {
+	args := m.Called(ctx, symbol, handler, errHandler, opts)
+	res, _ := args.Get(0).(*bingx.Stream)
+	return res, args.Error(1)
+}
+
+func (m *StreamAPI) WsOrderUpdateServe(ctx context.Context, listenKey string, handler bingx.WsOrderUpdateHandler, errHandler bingx.ErrHandler, opts ...bingx.WsOption) (*bingx.Stream, error) {
+	args := m.Called(ctx, listenKey, handler, errHandler, opts)
+	res, _ := args.Get(0).(*bingx.Stream)
+	return res, args.Error(1)
+}
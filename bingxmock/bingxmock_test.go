@@ -0,0 +1,59 @@
+package bingxmock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/magicaleks/go-bingx"
+	"github.com/magicaleks/go-bingx/bingxmock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// closeAll closes positions of symbol with reduce only market orders
+func closeAll(ctx context.Context, orders bingx.OrderAPI, account bingx.AccountAPI, symbol string) error {
+	positions, err := account.GetOpenPositions(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	for _, position := range positions {
+		side := bingx.SellSideType
+		if position.PositionSide == string(bingx.ShortPositionSideType) {
+			side = bingx.BuySideType
+		}
+		_, err := orders.CreateOrder(ctx, &bingx.OrderRequest{
+			Symbol:       symbol,
+			Type:         bingx.MarketOrderType,
+			Side:         side,
+			PositionSide: bingx.PositionSideType(position.PositionSide),
+			ReduceOnly:   true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestMocks(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	orders := new(bingxmock.OrderAPI)
+	account := new(bingxmock.AccountAPI)
+
+	account.On("GetOpenPositions", ctx, "BTC-USDT").Return([]bingx.Position{
+		{Symbol: "BTC-USDT", PositionSide: "LONG"},
+		{Symbol: "BTC-USDT", PositionSide: "SHORT"},
+	}, nil)
+	orders.On("CreateOrder", ctx, mock.MatchedBy(func(order *bingx.OrderRequest) bool {
+		return order.ReduceOnly && order.Side == bingx.SellSideType
+	})).Return(&bingx.CreateOrderResponse{OrderId: 1}, nil).Once()
+	orders.On("CreateOrder", ctx, mock.MatchedBy(func(order *bingx.OrderRequest) bool {
+		return order.ReduceOnly && order.Side == bingx.BuySideType
+	})).Return(nil, errors.New("rejected")).Once()
+
+	r.EqualError(closeAll(ctx, orders, account, "BTC-USDT"), "rejected")
+	orders.AssertExpectations(t)
+	account.AssertExpectations(t)
+}
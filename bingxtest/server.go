@@ -0,0 +1,488 @@
+// Package bingxtest runs in-process fake of BingX perpetual swap API for tests.
+// Server speaks REST and websocket protocols of the exchange, verifies request signatures,
+// keeps orders, positions and balance in memory and supports scripted responses and error injection.
+package bingxtest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/magicaleks/go-bingx/internal/simulator"
+)
+
+// API error codes returned by Server
+const (
+	SignatureErrorCode          = 100001
+	APIKeyErrorCode             = 100413
+	InvalidParamsErrorCode      = simulator.InvalidParamsCode
+	NotFoundErrorCode           = 100404
+	OrderNotExistErrorCode      = simulator.OrderNotExistCode
+	InsufficientMarginErrorCode = simulator.InsufficientMarginCode
+	NoPositionErrorCode         = simulator.NoPositionCode
+	NoPriceErrorCode            = simulator.NoPriceCode
+)
+
+const defaultBalance = 10000
+
+// Order Define order kept by Server
+type Order = simulator.Order
+
+// Position Define position kept by Server. Amount is negative for short positions.
+type Position = simulator.Position
+
+// Request Define request received by Server
+type Request struct {
+	Method string
+	Path   string
+	Params url.Values
+	Header http.Header
+}
+
+type response struct {
+	status int
+	body   []byte
+}
+
+// Server Define fake BingX server, create it with NewServer and stop with Close
+type Server struct {
+	// URL base URL of REST API, set it as Client.BaseURL
+	URL string
+	// WsURL market websocket endpoint
+	WsURL     string
+	APIKey    string
+	SecretKey string
+	ListenKey string
+
+	http *httptest.Server
+
+	mu       sync.Mutex
+	requests []Request
+	scripted map[string][]response
+	handlers map[string]http.Handler
+	exchange *simulator.Exchange
+
+	ws *wsHub
+}
+
+// NewServer Start server accepting requests signed with apiKey and secretKey
+func NewServer(apiKey, secretKey string) *Server {
+	s := &Server{
+		APIKey:    apiKey,
+		SecretKey: secretKey,
+		ListenKey: randomKey(),
+		scripted:  map[string][]response{},
+		handlers:  map[string]http.Handler{},
+		exchange:  simulator.New(),
+		ws:        newWsHub(),
+	}
+	s.exchange.Balance = defaultBalance
+	s.exchange.LastOrderId = 1000
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/swap-market", s.serveWs)
+	mux.HandleFunc("/", s.serveHTTP)
+	s.http = httptest.NewServer(mux)
+	s.URL = s.http.URL
+	s.WsURL = "ws" + strings.TrimPrefix(s.http.URL, "http") + "/swap-market"
+
+	return s
+}
+
+// Close Stop server and drop websocket connections
+func (s *Server) Close() {
+	s.ws.closeAll()
+	s.http.Close()
+}
+
+// UserWsURL User data stream endpoint for ListenKey
+func (s *Server) UserWsURL() string {
+	return s.WsURL + "?listenKey=" + s.ListenKey
+}
+
+// Respond Queue raw body returned by the next request to method and path instead of built-in behaviour
+func (s *Server) Respond(method, path string, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := method + " " + path
+	s.scripted[key] = append(s.scripted[key], response{status: status, body: []byte(body)})
+}
+
+// Fail Queue API error returned by the next request to method and path
+func (s *Server) Fail(method, path string, code int, msg string) {
+	body, _ := json.Marshal(map[string]interface{}{"code": code, "msg": msg})
+	s.Respond(method, path, http.StatusOK, string(body))
+}
+
+// Handle Serve requests to method and path by h, e.g. to fake market data endpoints
+func (s *Server) Handle(method, path string, h http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method+" "+path] = h
+}
+
+// Requests Received requests with valid signature, in order of arrival
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// SetBalance Set wallet balance
+func (s *Server) SetBalance(balance float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exchange.Balance = balance
+}
+
+// Balance Wallet balance, realised profit and fees included
+func (s *Server) Balance() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.exchange.Balance
+}
+
+// SetFeeRate Set fee rates of resting (maker) and immediately filled (taker) orders
+func (s *Server) SetFeeRate(maker, taker float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exchange.MakerFee, s.exchange.TakerFee = maker, taker
+}
+
+// SetLeverage Set leverage of symbol, default is 1
+func (s *Server) SetLeverage(symbol string, leverage int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exchange.Leverage[symbol] = leverage
+}
+
+// SetPosition Replace position of symbol and side
+func (s *Server) SetPosition(position Position) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.exchange.SetPosition(position)
+}
+
+// Positions Open positions sorted by symbol and side
+func (s *Server) Positions() []Position {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []Position
+	for _, p := range s.exchange.Positions("") {
+		res = append(res, *p)
+	}
+	return res
+}
+
+// Order Order by id
+func (s *Server) Order(orderId int64) (Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.exchange.Order(orderId)
+	if !ok {
+		return Order{}, false
+	}
+	return *o, true
+}
+
+// Orders All orders sorted by id
+func (s *Server) Orders() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []Order
+	for _, o := range s.exchange.Orders() {
+		res = append(res, *o)
+	}
+	return res
+}
+
+// SetPrice Set price of symbol, market orders are filled at it, open limit orders crossed by it
+// are filled at their price and positions are liquidated if margin is not enough
+func (s *Server) SetPrice(symbol string, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.publish(s.exchange.SetPrice(symbol, price))
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, InvalidParamsErrorCode, err.Error())
+		return
+	}
+
+	if r.Header.Get("X-BX-APIKEY") != s.APIKey {
+		writeError(w, APIKeyErrorCode, "Incorrect apiKey")
+		return
+	}
+	if !s.verify(r) {
+		writeError(w, SignatureErrorCode, "Signature verification failed")
+		return
+	}
+
+	key := r.Method + " " + r.URL.Path
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Params: r.Form, Header: r.Header.Clone()})
+	if queue := s.scripted[key]; len(queue) > 0 {
+		s.scripted[key] = queue[1:]
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(queue[0].status)
+		w.Write(queue[0].body)
+		return
+	}
+	h, ok := s.handlers[key]
+	s.mu.Unlock()
+	if ok {
+		h.ServeHTTP(w, r)
+		return
+	}
+
+	switch key {
+	case "GET /openApi/swap/v2/server/time":
+		writeData(w, map[string]int64{"serverTime": time.Now().UnixMilli()})
+	case "POST /openApi/user/auth/userDataStream":
+		writeJSON(w, map[string]string{"listenKey": s.ListenKey})
+	case "PUT /openApi/user/auth/userDataStream", "DELETE /openApi/user/auth/userDataStream":
+		writeJSON(w, map[string]string{})
+	case "GET /openApi/swap/v2/user/balance":
+		s.getBalance(w)
+	case "GET /openApi/swap/v2/user/positions":
+		s.getPositions(w, r.Form)
+	case "POST /openApi/swap/v2/trade/order":
+		s.createOrder(w, r.Form)
+	case "DELETE /openApi/swap/v2/trade/order":
+		s.cancelOrder(w, r.Form)
+	case "GET /openApi/swap/v2/trade/order":
+		s.getOrder(w, r.Form)
+	case "DELETE /openApi/swap/v2/trade/allOpenOrders":
+		s.cancelAllOrders(w, r.Form)
+	case "GET /openApi/swap/v2/trade/openOrders":
+		s.getOpenOrders(w, r.Form)
+	default:
+		// headers are sent with status, so they are set before it
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		writeError(w, NotFoundErrorCode, "api not found: "+key)
+	}
+}
+
+// verify checks signature of query string or form body, the one which carries it
+func (s *Server) verify(r *http.Request) bool {
+	params := r.URL.Query()
+	if params.Get("signature") == "" {
+		params = r.PostForm
+	}
+	signature := params.Get("signature")
+	if signature == "" || params.Get("timestamp") == "" {
+		return false
+	}
+
+	unsigned := url.Values{}
+	for k, v := range params {
+		if k != "signature" {
+			unsigned[k] = v
+		}
+	}
+	h := hmac.New(sha256.New, []byte(s.SecretKey))
+	h.Write([]byte(unsigned.Encode()))
+	return hmac.Equal([]byte(hex.EncodeToString(h.Sum(nil))), []byte(signature))
+}
+
+func (s *Server) getBalance(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account := s.exchange.Account()
+	writeData(w, map[string]interface{}{"balance": map[string]string{
+		"asset":            simulator.Asset,
+		"balance":          formatFloat(account.Balance),
+		"equity":           formatFloat(account.Equity),
+		"unrealizedProfit": formatFloat(account.UnrealizedProfit),
+		"realisedProfit":   formatFloat(account.RealisedProfit),
+		"availableMargin":  formatFloat(account.AvailableMargin),
+		"usedMargin":       formatFloat(account.UsedMargin),
+		"freezedMargin":    formatFloat(account.FrozenMargin),
+	}})
+}
+
+func (s *Server) getPositions(w http.ResponseWriter, params url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := []map[string]interface{}{}
+	for _, p := range s.exchange.Positions(params.Get("symbol")) {
+		side := p.PositionSide
+		if side == "BOTH" {
+			side = "LONG"
+			if p.Amount < 0 {
+				side = "SHORT"
+			}
+		}
+		markPrice := s.exchange.MarkPrice(p)
+		res = append(res, map[string]interface{}{
+			"symbol":           p.Symbol,
+			"positionId":       p.Key(),
+			"positionSide":     side,
+			"isolated":         false,
+			"positionAmt":      formatFloat(math.Abs(p.Amount)),
+			"availableAmt":     formatFloat(math.Abs(p.Amount)),
+			"unrealizedProfit": formatFloat(s.exchange.UnrealizedProfit(p)),
+			"realisedProfit":   formatFloat(p.RealisedProfit),
+			"initialMargin":    formatFloat(s.exchange.PositionMargin(p)),
+			"avgPrice":         formatFloat(p.AvgPrice),
+			"liquidationPrice": s.exchange.LiquidationPrice(p),
+			"leverage":         s.exchange.LeverageOf(p.Symbol),
+			"positionValue":    formatFloat(math.Abs(p.Amount) * markPrice),
+			"markPrice":        formatFloat(markPrice),
+		})
+	}
+	writeData(w, res)
+}
+
+func (s *Server) createOrder(w http.ResponseWriter, params url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := simulator.ParseOrder(params)
+	if err == nil {
+		var events []interface{}
+		o, events, err = s.exchange.CreateOrder(o)
+		s.publish(events)
+	}
+	if err != nil {
+		writeSimulatorError(w, err)
+		return
+	}
+	writeData(w, map[string]interface{}{"order": orderJSON(o)})
+}
+
+func (s *Server) cancelOrder(w http.ResponseWriter, params url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, events, err := s.exchange.CancelOrder(params)
+	if err != nil {
+		writeSimulatorError(w, err)
+		return
+	}
+	s.publish(events)
+	writeData(w, map[string]interface{}{"order": orderJSON(o)})
+}
+
+func (s *Server) cancelAllOrders(w http.ResponseWriter, params url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	canceled, events := s.exchange.CancelAllOrders(params.Get("symbol"), params.Get("type"))
+	s.publish(events)
+	success := []map[string]interface{}{}
+	for _, o := range canceled {
+		success = append(success, orderJSON(o))
+	}
+	writeData(w, map[string]interface{}{"order": map[string]interface{}{
+		"success": success,
+		"failed":  []map[string]interface{}{},
+	}})
+}
+
+func (s *Server) getOrder(w http.ResponseWriter, params url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.exchange.FindOrder(params)
+	if err != nil {
+		writeSimulatorError(w, err)
+		return
+	}
+	writeData(w, map[string]interface{}{"order": orderJSON(o)})
+}
+
+func (s *Server) getOpenOrders(w http.ResponseWriter, params url.Values) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := []map[string]interface{}{}
+	for _, o := range s.exchange.OpenOrders(params.Get("symbol")) {
+		orders = append(orders, orderJSON(o))
+	}
+	writeData(w, map[string]interface{}{"orders": orders})
+}
+
+func orderJSON(o *Order) map[string]interface{} {
+	return map[string]interface{}{
+		"time":          o.Time,
+		"symbol":        o.Symbol,
+		"side":          o.Side,
+		"type":          o.Type,
+		"positionSide":  o.PositionSide,
+		"reduceOnly":    o.ReduceOnly,
+		"cumQuote":      formatFloat(o.ExecutedQty * o.AvgPrice),
+		"status":        o.Status,
+		"stopPrice":     "0",
+		"price":         formatFloat(o.Price),
+		"origQty":       formatFloat(o.Quantity),
+		"avgPrice":      formatFloat(o.AvgPrice),
+		"executedQty":   formatFloat(o.ExecutedQty),
+		"orderId":       o.OrderId,
+		"profit":        formatFloat(o.Profit),
+		"commission":    formatFloat(-o.Commission),
+		"updateTime":    o.UpdateTime,
+		"workingType":   "MARK_PRICE",
+		"clientOrderID": o.ClientOrderID,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, map[string]interface{}{"code": 0, "msg": "", "data": data})
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, map[string]interface{}{"code": code, "msg": msg})
+}
+
+// writeSimulatorError writes request rejected by simulator
+func writeSimulatorError(w http.ResponseWriter, err error) {
+	if simErr, ok := err.(*simulator.Error); ok {
+		writeError(w, int(simErr.Code), simErr.Message)
+		return
+	}
+	writeError(w, InvalidParamsErrorCode, err.Error())
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func randomKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,131 @@
+package bingxtest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+	"github.com/magicaleks/go-bingx/bingxtest"
+	"github.com/stretchr/testify/suite"
+)
+
+type serverTestSuite struct {
+	suite.Suite
+	server *bingxtest.Server
+}
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(serverTestSuite))
+}
+
+func (s *serverTestSuite) SetupTest() {
+	s.server = bingxtest.NewServer("apiKey", "secretKey")
+}
+
+func (s *serverTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *serverTestSuite) TestPublish() {
+	r := s.Require()
+	var gaps int
+	trades, err := bingx.WsTradeChan(context.Background(), "BTC-USDT",
+		bingx.WithWsEndpoint(s.server.WsURL),
+		bingx.WithWsBackoff(10*time.Millisecond, 10*time.Millisecond),
+		bingx.WithWsGapHandler(func(gap bingx.WsGap) {
+			gaps++
+		}),
+	)
+	r.NoError(err)
+	defer trades.Close()
+
+	r.NoError(s.server.WaitSubscription("BTC-USDT@trade", 5*time.Second))
+	r.NoError(s.server.Publish("BTC-USDT@trade", []map[string]interface{}{
+		{"s": "BTC-USDT", "p": "43000.5", "q": "0.01", "T": 1702719166000, "m": true},
+	}))
+	r.Equal(&bingx.WsTradeEvent{Symbol: "BTC-USDT", Price: 43000.5, Quantity: 0.01, Time: 1702719166000, IsBuyerMaker: true}, s.next(trades))
+
+	s.server.Disconnect()
+	r.NoError(s.server.WaitSubscription("BTC-USDT@trade", 5*time.Second))
+	r.NoError(s.server.Publish("BTC-USDT@trade", []map[string]interface{}{{"s": "BTC-USDT", "p": "43001", "q": "1"}}))
+	r.Equal(float64(43001), s.next(trades).Price)
+	r.Equal(1, gaps)
+}
+
+func (s *serverTestSuite) TestRejectSubscription() {
+	r := s.Require()
+	s.server.RejectSubscription("FOO-USDT@trade", 80015, "unknown symbol")
+
+	trades, err := bingx.WsTradeChan(context.Background(), "FOO-USDT", bingx.WithWsEndpoint(s.server.WsURL))
+	r.NoError(err)
+	defer trades.Close()
+
+	select {
+	case err := <-trades.Errors():
+		var reqErr *bingx.WsRequestError
+		r.ErrorAs(err, &reqErr)
+		r.Equal(80015, reqErr.Code)
+	case <-time.After(5 * time.Second):
+		s.FailNow("subscription is not rejected")
+	}
+}
+
+func (s *serverTestSuite) TestReduceOnly() {
+	r := s.Require()
+	client := bingx.NewClient(s.server.APIKey, s.server.SecretKey)
+	client.BaseURL = s.server.URL
+	s.server.SetPosition(bingxtest.Position{Symbol: "BTC-USDT", PositionSide: "BOTH", Amount: 1, AvgPrice: 100})
+	s.server.SetPrice("BTC-USDT", 110)
+
+	// order larger than position is limited by it and keeps original quantity
+	res, err := client.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(bingx.MarketOrderType).
+		Side(bingx.SellSideType).
+		ReduceOnly().
+		Quantity(3).
+		Do(context.Background())
+	r.NoError(err)
+	order, err := client.GetOrder(context.Background(), "BTC-USDT", res.OrderId, "")
+	r.NoError(err)
+	r.Equal("3", order.OrigQuantity)
+	r.Equal("1", order.Quantity)
+	r.Equal(bingx.ExpiredOrderStatus, order.Status)
+	r.Empty(s.server.Positions())
+}
+
+func (s *serverTestSuite) TestNotFound() {
+	r := s.Require()
+	var header http.Header
+	client := bingx.NewClient(s.server.APIKey, s.server.SecretKey)
+	client.BaseURL = s.server.URL
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		res, err := http.DefaultTransport.RoundTrip(req)
+		if err == nil {
+			header = res.Header
+		}
+		return res, err
+	})}
+
+	_, err := client.GetOpenInterest(context.Background(), "BTC-USDT")
+	r.Error(err)
+	r.Equal("application/json", header.Get("Content-Type"))
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (s *serverTestSuite) next(trades *bingx.WsChan[*bingx.WsTradeEvent]) *bingx.WsTradeEvent {
+	select {
+	case trade := <-trades.C():
+		return trade
+	case <-time.After(5 * time.Second):
+		s.FailNow("no trade")
+		return nil
+	}
+}
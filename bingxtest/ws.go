@@ -0,0 +1,340 @@
+package bingxtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/magicaleks/go-bingx/internal/simulator"
+)
+
+const writeTimeout = 5 * time.Second
+
+// wsConn Define accepted websocket connection, user connections receive account events
+type wsConn struct {
+	conn *websocket.Conn
+	user bool
+
+	mu   sync.Mutex
+	subs map[string]bool
+}
+
+func (c *wsConn) write(msg []byte) error {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(msg)
+	w.Close()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return c.conn.WriteMessage(websocket.BinaryMessage, buf.Bytes())
+}
+
+func (c *wsConn) subscribed(dataType string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.subs[dataType]
+}
+
+type wsRejection struct {
+	code int
+	msg  string
+}
+
+// wsHub Define connections of Server, changedC is closed and replaced on every change
+type wsHub struct {
+	upgrader websocket.Upgrader
+
+	mu       sync.Mutex
+	conns    map[*wsConn]bool
+	rejected map[string]wsRejection
+	changedC chan struct{}
+}
+
+func newWsHub() *wsHub {
+	return &wsHub{
+		conns:    map[*wsConn]bool{},
+		rejected: map[string]wsRejection{},
+		changedC: make(chan struct{}),
+	}
+}
+
+func (h *wsHub) changed() {
+	close(h.changedC)
+	h.changedC = make(chan struct{})
+}
+
+func (h *wsHub) add(c *wsConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.conns[c] = true
+	h.changed()
+}
+
+func (h *wsHub) remove(c *wsConn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.conns, c)
+	h.changed()
+}
+
+func (h *wsHub) list() []*wsConn {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	conns := make([]*wsConn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+func (h *wsHub) closeAll() {
+	for _, c := range h.list() {
+		c.conn.Close()
+	}
+}
+
+// wait returns when cond holds or timeout expires
+func (h *wsHub) wait(timeout time.Duration, cond func() bool) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		h.mu.Lock()
+		changedC := h.changedC
+		h.mu.Unlock()
+
+		if cond() {
+			return true
+		}
+		select {
+		case <-changedC:
+		case <-timer.C:
+			return cond()
+		}
+	}
+}
+
+func (s *Server) serveWs(w http.ResponseWriter, r *http.Request) {
+	listenKey := r.URL.Query().Get("listenKey")
+	if listenKey != "" && listenKey != s.ListenKey {
+		http.Error(w, "invalid listenKey", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := s.ws.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &wsConn{conn: conn, user: listenKey != "", subs: map[string]bool{}}
+	s.ws.add(c)
+	defer func() {
+		conn.Close()
+		s.ws.remove(c)
+	}()
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if string(msg) == "Pong" {
+			continue
+		}
+		s.ws.request(c, msg)
+	}
+}
+
+// request replies to subscribe or unsubscribe request
+func (h *wsHub) request(c *wsConn, msg []byte) {
+	req := new(struct {
+		Id       string `json:"id"`
+		ReqType  string `json:"reqType"`
+		DataType string `json:"dataType"`
+	})
+	if err := json.Unmarshal(msg, req); err != nil || req.Id == "" {
+		return
+	}
+
+	h.mu.Lock()
+	rejected, ok := h.rejected[req.DataType]
+	h.mu.Unlock()
+
+	if !ok {
+		c.mu.Lock()
+		switch req.ReqType {
+		case "sub":
+			c.subs[req.DataType] = true
+		case "unsub":
+			delete(c.subs, req.DataType)
+		}
+		c.mu.Unlock()
+	}
+
+	res, _ := json.Marshal(map[string]interface{}{"id": req.Id, "code": rejected.code, "msg": rejected.msg, "dataType": req.DataType})
+	c.write(res)
+
+	h.mu.Lock()
+	h.changed()
+	h.mu.Unlock()
+}
+
+// RejectSubscription Reply to subscriptions of dataType with error code
+func (s *Server) RejectSubscription(dataType string, code int, msg string) {
+	s.ws.mu.Lock()
+	defer s.ws.mu.Unlock()
+
+	s.ws.rejected[dataType] = wsRejection{code: code, msg: msg}
+}
+
+// WaitSubscription Wait until some connection subscribes to dataType
+func (s *Server) WaitSubscription(dataType string, timeout time.Duration) error {
+	ok := s.ws.wait(timeout, func() bool {
+		for _, c := range s.ws.list() {
+			if c.subscribed(dataType) {
+				return true
+			}
+		}
+		return false
+	})
+	if !ok {
+		return fmt.Errorf("bingxtest: no subscription to %s in %s", dataType, timeout)
+	}
+	return nil
+}
+
+// WaitUserStream Wait until user data stream connects
+func (s *Server) WaitUserStream(timeout time.Duration) error {
+	ok := s.ws.wait(timeout, func() bool {
+		for _, c := range s.ws.list() {
+			if c.user {
+				return true
+			}
+		}
+		return false
+	})
+	if !ok {
+		return errors.New("bingxtest: user data stream is not connected")
+	}
+	return nil
+}
+
+// Publish Send event of dataType to connections subscribed to it, data is marshaled as "data" field
+func (s *Server) Publish(dataType string, data interface{}) error {
+	msg, err := json.Marshal(map[string]interface{}{"code": 0, "dataType": dataType, "data": data})
+	if err != nil {
+		return err
+	}
+	for _, c := range s.ws.list() {
+		if c.subscribed(dataType) {
+			c.write(msg)
+		}
+	}
+	return nil
+}
+
+// PublishUser Send raw event to user data streams
+func (s *Server) PublishUser(event interface{}) error {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	for _, c := range s.ws.list() {
+		if c.user {
+			c.write(msg)
+		}
+	}
+	return nil
+}
+
+// Ping Send Ping to every connection, clients reply with Pong
+func (s *Server) Ping() {
+	for _, c := range s.ws.list() {
+		c.write([]byte("Ping"))
+	}
+}
+
+// Disconnect Drop all websocket connections, clients are expected to reconnect
+func (s *Server) Disconnect() {
+	s.ws.closeAll()
+}
+
+// publish sends order and account events of simulator to user data streams, called with Server locked
+func (s *Server) publish(events []interface{}) {
+	for _, event := range events {
+		switch event := event.(type) {
+		case *simulator.OrderEvent:
+			s.publishOrder(event)
+		case *simulator.AccountEvent:
+			s.publishAccount(event)
+		}
+	}
+}
+
+func (s *Server) publishOrder(event *simulator.OrderEvent) {
+	o := &event.Order
+	s.PublishUser(map[string]interface{}{
+		"e": "ORDER_TRADE_UPDATE",
+		"E": o.UpdateTime,
+		"o": map[string]interface{}{
+			"s":  o.Symbol,
+			"c":  o.ClientOrderID,
+			"i":  o.OrderId,
+			"S":  o.Side,
+			"o":  o.Type,
+			"ps": o.PositionSide,
+			"wt": "MARK_PRICE",
+			"q":  formatFloat(o.Quantity),
+			"p":  formatFloat(o.Price),
+			"sp": "0",
+			"ap": formatFloat(o.AvgPrice),
+			"x":  event.Spec,
+			"X":  o.Status,
+			"T":  o.UpdateTime,
+			"l":  formatFloat(event.LastQty),
+			"L":  formatFloat(event.LastPrice),
+			"z":  formatFloat(o.ExecutedQty),
+			"N":  simulator.Asset,
+			"n":  formatFloat(-event.Fee),
+			"rp": formatFloat(event.RealizedPnl),
+		},
+	})
+}
+
+func (s *Server) publishAccount(event *simulator.AccountEvent) {
+	p := &event.Position
+	s.PublishUser(map[string]interface{}{
+		"e": "ACCOUNT_UPDATE",
+		"E": event.Time,
+		"a": map[string]interface{}{
+			"m": event.Reason,
+			"B": []map[string]string{{
+				"a":  simulator.Asset,
+				"wb": formatFloat(event.Balance),
+				"cw": formatFloat(event.Balance),
+				"bc": formatFloat(event.BalanceChange),
+			}},
+			"P": []map[string]string{{
+				"s":  p.Symbol,
+				"ps": p.PositionSide,
+				"pa": formatFloat(p.Amount),
+				"ep": formatFloat(p.AvgPrice),
+				"up": formatFloat(event.UnrealizedProfit),
+				"mt": "cross",
+				"iw": "0",
+			}},
+		},
+	})
+}
@@ -38,6 +38,9 @@ type OrderSpecType string
 
 type OrderWorkingType string
 
+// TimeInForceType of limit order
+type TimeInForceType string
+
 const (
 	timestampKey  = "timestamp"
 	signatureKey  = "signature"
@@ -68,6 +71,12 @@ const (
 	MarkOrderWorkingType     OrderWorkingType = "MARK_PRICE"
 	ContractOrderWorkingType OrderWorkingType = "CONTRACT_PRICE"
 	IndexOrderWorkingType    OrderWorkingType = "INDEX_PRICE"
+
+	GTCTimeInForceType TimeInForceType = "GTC"
+	IOCTimeInForceType TimeInForceType = "IOC"
+	FOKTimeInForceType TimeInForceType = "FOK"
+	// PostOnlyTimeInForceType order expires instead of taking liquidity
+	PostOnlyTimeInForceType TimeInForceType = "PostOnly"
 )
 
 type Interval string
@@ -110,7 +119,11 @@ type Client struct {
 	Debug      bool
 	Logger     *log.Logger
 	TimeOffset int64
-	do         doFunc
+	// Recorder saves every request with its response when set
+	Recorder *Recorder
+	// RiskGuard checks orders before they are sent when set
+	RiskGuard *RiskGuard
+	do        doFunc
 }
 
 // Init Api Client from apiKey & secretKey
@@ -211,9 +224,15 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 	}
 	res, err := f(req)
 	if err != nil {
+		if c.Recorder != nil {
+			c.Recorder.recordRest(req, r.form.Encode(), nil, nil, err)
+		}
 		return []byte{}, err
 	}
 	data, err = io.ReadAll(res.Body)
+	if c.Recorder != nil {
+		c.Recorder.recordRest(req, r.form.Encode(), res, data, err)
+	}
 	if err != nil {
 		return []byte{}, err
 	}
@@ -305,6 +324,34 @@ func (c *Client) NewGetSymbolDataService() *GetSymbolDataService {
 	return &GetSymbolDataService{c: c}
 }
 
+func (c *Client) NewGetContractsService() *GetContractsService {
+	return &GetContractsService{c: c}
+}
+
+func (c *Client) NewGetDepthService() *GetDepthService {
+	return &GetDepthService{c: c}
+}
+
+func (c *Client) NewGetTradesService() *GetTradesService {
+	return &GetTradesService{c: c}
+}
+
+func (c *Client) NewGetTickerService() *GetTickerService {
+	return &GetTickerService{c: c}
+}
+
+func (c *Client) NewGetPremiumIndexService() *GetPremiumIndexService {
+	return &GetPremiumIndexService{c: c}
+}
+
+func (c *Client) NewGetFundingRateService() *GetFundingRateService {
+	return &GetFundingRateService{c: c}
+}
+
+func (c *Client) NewGetOpenInterestService() *GetOpenInterestService {
+	return &GetOpenInterestService{c: c}
+}
+
 func (c *Client) NewCancelAllOrdersService() *CancelAllOrdersService {
 	return &CancelAllOrdersService{c: c}
 }
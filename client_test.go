@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/magicaleks/go-bingx/bingxtest"
+	"github.com/magicaleks/go-bingx/common"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -71,6 +73,34 @@ func (s *baseTestSuite) assertURLValuesEqual(e, a url.Values) {
 	}
 }
 
+// serverTestSuite runs client against fake BingX server
+type serverTestSuite struct {
+	suite.Suite
+	server *bingxtest.Server
+	client *Client
+}
+
+func (s *serverTestSuite) SetupTest() {
+	s.server = bingxtest.NewServer("dummyAPIKey", "dummySecretKey")
+	s.client = NewClient("dummyAPIKey", "dummySecretKey")
+	s.client.BaseURL = s.server.URL
+}
+
+func (s *serverTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *serverTestSuite) r() *require.Assertions {
+	return s.Require()
+}
+
+// apiErrorCode code of APIError returned by client
+func (s *serverTestSuite) apiErrorCode(err error) int64 {
+	apiErr := new(common.APIError)
+	s.r().ErrorAs(err, &apiErr)
+	return apiErr.Code
+}
+
 func anythingOfType(t string) mock.AnythingOfTypeArgument {
 	return mock.AnythingOfType(t)
 }
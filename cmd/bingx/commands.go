@@ -0,0 +1,472 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+const (
+	defaultKlinesLimit = 500
+	csvFormat          = "csv"
+	jsonFormat         = "json"
+	textFormat         = "text"
+)
+
+func (c *cli) time(ctx context.Context, args []string) error {
+	if err := c.newFlagSet("time").Parse(args); err != nil {
+		return err
+	}
+
+	serverTime, err := c.client.GetServerTime(ctx)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(map[string]int64{"serverTime": serverTime})
+	}
+	_, err = fmt.Fprintln(c.stdout, formatMillis(serverTime))
+	return err
+}
+
+func (c *cli) balance(ctx context.Context, args []string) error {
+	if err := c.newFlagSet("balance").Parse(args); err != nil {
+		return err
+	}
+
+	balance, err := c.client.GetBalance(ctx)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(balance)
+	}
+	return c.printTable([]string{"ASSET", "BALANCE", "EQUITY", "UNREALIZED PNL", "REALISED PNL", "AVAILABLE", "USED MARGIN", "FROZEN MARGIN"}, [][]string{{
+		balance.Asset,
+		balance.Balance,
+		balance.Equity,
+		balance.UnrealizedProfit,
+		balance.RealisedProfit,
+		balance.AavailableMargin,
+		balance.UsedMargin,
+		balance.FreezedMargin,
+	}})
+}
+
+func (c *cli) positions(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("positions")
+	symbol := flags.String("symbol", "", "symbol, e.g. BTC-USDT, all symbols when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	positions, err := c.client.GetOpenPositions(ctx, *symbol)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(positions)
+	}
+
+	rows := make([][]string, len(positions))
+	for i, p := range positions {
+		rows[i] = []string{
+			p.Symbol,
+			p.PositionSide,
+			p.PositionAmt,
+			p.AvgPrice,
+			p.MarkPrice,
+			p.UnrealizedProfit,
+			strconv.Itoa(p.Leverage),
+			strconv.FormatFloat(p.LiquidationPrice, 'f', -1, 64),
+		}
+	}
+	return c.printTable([]string{"SYMBOL", "SIDE", "AMOUNT", "ENTRY PRICE", "MARK PRICE", "UNREALIZED PNL", "LEVERAGE", "LIQUIDATION PRICE"}, rows)
+}
+
+func (c *cli) orders(ctx context.Context, args []string) error {
+	sub, args, err := subcommand("orders", args, "list", "get", "create", "cancel", "cancel-all")
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "list":
+		return c.listOrders(ctx, args)
+	case "get":
+		return c.getOrder(ctx, args)
+	case "create":
+		return c.createOrder(ctx, args)
+	case "cancel":
+		return c.cancelOrder(ctx, args)
+	default:
+		return c.cancelAllOrders(ctx, args)
+	}
+}
+
+func (c *cli) listOrders(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("orders list")
+	symbol := flags.String("symbol", "", "symbol, e.g. BTC-USDT, all symbols when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	orders, err := c.client.GetOpenOrders(ctx, *symbol)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(orders)
+	}
+
+	rows := make([][]string, len(orders))
+	for i, o := range orders {
+		rows[i] = orderRow(o)
+	}
+	return c.printTable(orderHeader, rows)
+}
+
+// orderFlags Define flags selecting one order
+type orderFlags struct {
+	symbol        *string
+	orderId       *int64
+	clientOrderID *string
+}
+
+func newOrderFlags(c *cli, name string) (*orderFlags, func([]string) error) {
+	flags := c.newFlagSet(name)
+	f := &orderFlags{
+		symbol:        flags.String("symbol", "", "symbol, e.g. BTC-USDT"),
+		orderId:       flags.Int64("id", 0, "order id"),
+		clientOrderID: flags.String("client-id", "", "client order id"),
+	}
+	return f, func(args []string) error {
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *f.symbol == "" {
+			return fmt.Errorf("%s: -symbol is required", name)
+		}
+		if *f.orderId == 0 && *f.clientOrderID == "" {
+			return fmt.Errorf("%s: -id or -client-id is required", name)
+		}
+		return nil
+	}
+}
+
+// String Describe selected order
+func (f *orderFlags) String() string {
+	if *f.orderId != 0 {
+		return fmt.Sprintf("order %d of %s", *f.orderId, *f.symbol)
+	}
+	return fmt.Sprintf("order %q of %s", *f.clientOrderID, *f.symbol)
+}
+
+func (c *cli) getOrder(ctx context.Context, args []string) error {
+	f, parse := newOrderFlags(c, "orders get")
+	if err := parse(args); err != nil {
+		return err
+	}
+
+	order, err := c.client.GetOrder(ctx, *f.symbol, *f.orderId, *f.clientOrderID)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(order)
+	}
+	return c.printTable(orderHeader, [][]string{orderRow(order)})
+}
+
+func (c *cli) createOrder(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("orders create")
+	symbol := flags.String("symbol", "", "symbol, e.g. BTC-USDT")
+	side := flags.String("side", "", "BUY or SELL")
+	orderType := flags.String("type", string(bingx.LimitOrderType), "LIMIT or MARKET")
+	positionSide := flags.String("position-side", "", "BOTH, LONG or SHORT, default is BOTH")
+	quantity := flags.Float64("quantity", 0, "quantity")
+	price := flags.Float64("price", 0, "price of limit order")
+	clientOrderID := flags.String("client-id", "", "client order id")
+	timeInForce := flags.String("time-in-force", "", "GTC, IOC, FOK or PostOnly")
+	reduceOnly := flags.Bool("reduce-only", false, "only reduce position")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	order := &bingx.OrderRequest{
+		Symbol:        *symbol,
+		Type:          bingx.OrderType(strings.ToUpper(*orderType)),
+		Side:          bingx.SideType(strings.ToUpper(*side)),
+		PositionSide:  bingx.PositionSideType(strings.ToUpper(*positionSide)),
+		ClientOrderID: *clientOrderID,
+		ReduceOnly:    *reduceOnly,
+		TimeInForce:   bingx.TimeInForceType(*timeInForce),
+		Price:         *price,
+		Quantity:      *quantity,
+	}
+	switch {
+	case order.Symbol == "":
+		return errors.New("orders create: -symbol is required")
+	case order.Side != bingx.BuySideType && order.Side != bingx.SellSideType:
+		return fmt.Errorf("orders create: invalid -side %q, expected BUY or SELL", *side)
+	case order.Type != bingx.LimitOrderType && order.Type != bingx.MarketOrderType:
+		return fmt.Errorf("orders create: invalid -type %q, expected LIMIT or MARKET", *orderType)
+	case order.Quantity <= 0:
+		return errors.New("orders create: -quantity must be positive")
+	case order.Type == bingx.LimitOrderType && order.Price <= 0:
+		return errors.New("orders create: -price is required by limit order")
+	}
+	if err := c.confirm("Create %s?", describeOrder(order)); err != nil {
+		return err
+	}
+
+	res, err := c.client.CreateOrder(ctx, order)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return fmt.Errorf("orders create: empty response")
+	}
+	if c.json {
+		return c.printJSON(res)
+	}
+	_, err = fmt.Fprintf(c.stdout, "order %d created\n", res.OrderId)
+	return err
+}
+
+// describeOrder Summarize order request for confirmation
+func describeOrder(o *bingx.OrderRequest) string {
+	s := fmt.Sprintf("%s %s order of %s %s", o.Type, o.Side, strconv.FormatFloat(o.Quantity, 'f', -1, 64), o.Symbol)
+	if o.Type == bingx.LimitOrderType {
+		s += " at " + strconv.FormatFloat(o.Price, 'f', -1, 64)
+	}
+	if o.PositionSide != "" {
+		s += ", position " + string(o.PositionSide)
+	}
+	if o.TimeInForce != "" {
+		s += ", " + string(o.TimeInForce)
+	}
+	if o.ReduceOnly {
+		s += ", reduce only"
+	}
+	return s
+}
+
+func cancelRow(o *bingx.CancelOrderResponse) []string {
+	return []string{
+		strconv.FormatInt(o.OrderId, 10),
+		o.ClientOrderID,
+		o.Symbol,
+		string(o.Side),
+		string(o.PositionSide),
+		string(o.OrderType),
+		o.Price,
+		o.OrigQty,
+		o.ExecutedQty,
+		o.AvgPrice,
+		string(o.Status),
+		formatMillis(int64(o.Time)),
+	}
+}
+
+func (c *cli) cancelOrder(ctx context.Context, args []string) error {
+	f, parse := newOrderFlags(c, "orders cancel")
+	if err := parse(args); err != nil {
+		return err
+	}
+	if err := c.confirm("Cancel %s?", f); err != nil {
+		return err
+	}
+
+	order, err := c.client.CancelOrder(ctx, *f.symbol, *f.orderId, *f.clientOrderID)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		return c.printJSON(order)
+	}
+	return c.printTable(orderHeader, [][]string{cancelRow(order)})
+}
+
+func (c *cli) cancelAllOrders(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("orders cancel-all")
+	symbol := flags.String("symbol", "", "symbol, e.g. BTC-USDT, all symbols when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	target := *symbol
+	if target == "" {
+		target = "all symbols"
+	}
+	if err := c.confirm("Cancel all open orders of %s?", target); err != nil {
+		return err
+	}
+
+	res, err := c.client.CancelAllOrders(ctx, *symbol)
+	if err != nil {
+		return err
+	}
+	if c.json {
+		err = c.printJSON(res)
+	} else {
+		rows := make([][]string, len(res.Success))
+		for i := range res.Success {
+			rows[i] = cancelRow(&res.Success[i])
+		}
+		err = c.printTable(orderHeader, rows)
+	}
+	if err != nil {
+		return err
+	}
+	if len(res.Failed) > 0 {
+		return fmt.Errorf("orders cancel-all: %d orders are not canceled", len(res.Failed))
+	}
+	return nil
+}
+
+func (c *cli) klines(ctx context.Context, args []string) error {
+	flags := c.newFlagSet("klines")
+	symbol := flags.String("symbol", "", "symbol, e.g. BTC-USDT")
+	interval := flags.String("interval", string(bingx.Interval60), "interval, e.g. 1m, 1h, 1d")
+	start := flags.String("start", "", "start time, unix milliseconds, RFC 3339 or 2006-01-02 15:04 in UTC")
+	end := flags.String("end", "", "end time, default is now")
+	limit := flags.Int64("limit", defaultKlinesLimit, "number of the latest klines when -start is not set")
+	format := flags.String("format", textFormat, "output format: text, csv or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *symbol == "" {
+		return errors.New("klines: -symbol is required")
+	}
+	if c.json {
+		*format = jsonFormat
+	}
+	if *format != textFormat && *format != csvFormat && *format != jsonFormat {
+		return fmt.Errorf("klines: invalid -format %q, expected text, csv or json", *format)
+	}
+	startTime, err := parseTime(*start)
+	if err != nil {
+		return err
+	}
+	endTime, err := parseTime(*end)
+	if err != nil {
+		return err
+	}
+
+	var klines []*bingx.Kline
+	if startTime != 0 {
+		// long ranges are downloaded page by page
+		download, err := c.client.NewKlineDownloader().
+			Symbol(*symbol).
+			Interval(bingx.Interval(*interval)).
+			StartTime(startTime).
+			EndTime(endTime).
+			Do(ctx)
+		if err != nil {
+			return err
+		}
+		klines = download.Klines
+	} else {
+		klines, err = c.client.GetKlines(ctx, *symbol, bingx.Interval(*interval), 0, endTime, *limit)
+		if err != nil {
+			return err
+		}
+	}
+	sort.Slice(klines, func(i, j int) bool {
+		return klines[i].Time < klines[j].Time
+	})
+
+	switch *format {
+	case jsonFormat:
+		return c.printJSON(klines)
+	case csvFormat:
+		return c.printKlinesCSV(klines)
+	}
+	rows := make([][]string, len(klines))
+	for i, k := range klines {
+		rows[i] = []string{formatMillis(k.Time), k.Open, k.High, k.Low, k.Close, k.Volume}
+	}
+	return c.printTable([]string{"TIME", "OPEN", "HIGH", "LOW", "CLOSE", "VOLUME"}, rows)
+}
+
+func (c *cli) stream(ctx context.Context, args []string) error {
+	sub, args, err := subcommand("stream", args, "klines", "orders")
+	if err != nil {
+		return err
+	}
+
+	errHandler := func(err error) {
+		fmt.Fprintln(c.stderr, "bingx:", err)
+	}
+
+	var stream *bingx.Stream
+	switch sub {
+	case "klines":
+		flags := c.newFlagSet("stream klines")
+		symbol := flags.String("symbol", "", "symbol, e.g. BTC-USDT")
+		interval := flags.String("interval", string(bingx.Interval1), "interval, e.g. 1m, 1h, 1d")
+		closed := flags.Bool("closed", false, "print closed klines only")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *symbol == "" {
+			return errors.New("stream klines: -symbol is required")
+		}
+
+		stream, err = c.client.WsKlineServe(ctx, *symbol, bingx.Interval(*interval), func(event *bingx.WsKlineEvent) {
+			if *closed && !event.Completed {
+				return
+			}
+			c.printKline(*symbol, event)
+		}, errHandler, c.wsOptions("")...)
+	default:
+		if err := c.newFlagSet("stream orders").Parse(args); err != nil {
+			return err
+		}
+		var listenKey string
+		if listenKey, err = c.client.GetListenKey(ctx); err != nil {
+			return err
+		}
+
+		stream, err = c.client.WsOrderUpdateServe(ctx, listenKey, c.printOrderUpdate, errHandler, c.wsOptions("?listenKey="+listenKey)...)
+	}
+	if err != nil {
+		return err
+	}
+
+	<-stream.Done()
+	if ctx.Err() != nil {
+		return nil
+	}
+	return stream.Err()
+}
+
+// printKline Print kline update, symbol is taken from subscription as updates may omit it
+func (c *cli) printKline(symbol string, event *bingx.WsKlineEvent) {
+	if c.json {
+		c.printJSONLine(event)
+		return
+	}
+	state := "open"
+	if event.Completed {
+		state = "closed"
+	}
+	fmt.Fprintf(c.stdout, "%s %s open=%v high=%v low=%v close=%v volume=%v %s\n",
+		formatMillis(int64(event.Time)), symbol, event.Open, event.High, event.Low, event.Close, event.Volume, state)
+}
+
+func (c *cli) printOrderUpdate(order *bingx.WsOrder) {
+	if c.json {
+		c.printJSONLine(order)
+		return
+	}
+	fmt.Fprintf(c.stdout, "%s %s %d %s %s %s %s status=%s price=%s quantity=%s filled=%s avgPrice=%s\n",
+		formatMillis(int64(order.Timestamp)), order.Symbol, order.OrderId, order.Side, order.PositionSide, order.OrderType,
+		order.Spec, order.Status, order.Price, order.Quantity, order.FilledQty, order.AveragePrice)
+}
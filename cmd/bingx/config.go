@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Environment variables overriding config file
+const (
+	apiKeyEnv    = "BINGX_API_KEY"
+	secretKeyEnv = "BINGX_SECRET_KEY"
+	baseURLEnv   = "BINGX_BASE_URL"
+)
+
+// config Define credentials and endpoint of client
+type config struct {
+	APIKey    string `json:"apiKey"`
+	SecretKey string `json:"secretKey"`
+	BaseURL   string `json:"baseURL"`
+}
+
+// defaultConfigPath bingx/config.json in user config directory, empty when it is unknown
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "bingx", "config.json")
+}
+
+// loadConfig Read config file and apply environment variables, missing default file is not an error
+func loadConfig(path string) (*config, error) {
+	c := new(config)
+
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist) && !explicit:
+		case err != nil:
+			return nil, err
+		default:
+			if err := json.Unmarshal(data, c); err != nil {
+				return nil, fmt.Errorf("config %s: %w", path, err)
+			}
+		}
+	}
+
+	for env, value := range map[string]*string{
+		apiKeyEnv:    &c.APIKey,
+		secretKeyEnv: &c.SecretKey,
+		baseURLEnv:   &c.BaseURL,
+	} {
+		if v := os.Getenv(env); v != "" {
+			*value = v
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,173 @@
+// Command bingx is command-line client of BingX perpetual swap API. It shows server time, balance,
+// positions and klines, lists, creates and cancels orders and prints kline and order update streams,
+// run bingx -h for the list of commands.
+//
+// Credentials are read from BINGX_API_KEY and BINGX_SECRET_KEY environment variables or from JSON
+// config file with apiKey, secretKey and baseURL fields, by default bingx/config.json in user config
+// directory. Output is printed as text tables, or as JSON with -json. Commands creating and
+// cancelling orders ask for confirmation unless -yes is set.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+const usage = `Usage: bingx [flags] <command> [command flags]
+
+Commands:
+  time                                  server time
+  balance                               account balance
+  positions [-symbol S]                 open positions
+  orders list [-symbol S]               open orders
+  orders get -symbol S -id N            order by id or -client-id
+  orders create -symbol S -side BUY -type LIMIT -quantity Q -price P
+  orders cancel -symbol S -id N         cancel order by id or -client-id
+  orders cancel-all [-symbol S]         cancel all open orders
+  klines -symbol S -interval 1h         klines as text, csv or json
+  stream klines -symbol S -interval 1m  print kline updates until interrupted
+  stream orders                         print order updates until interrupted
+
+Run bingx <command> -h for command flags.
+
+Flags:
+`
+
+// errAborted action was not confirmed
+var errAborted = errors.New("aborted")
+
+// cli Define state shared by commands
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	client *bingx.Client
+	json   bool
+	yes    bool
+	wsURL  string
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "bingx:", err)
+		os.Exit(1)
+	}
+}
+
+// run Parse global flags and execute command
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	flags := flag.NewFlagSet("bingx", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "config file, default is bingx/config.json in user config directory")
+	baseURL := flags.String("base-url", "", "REST API URL")
+	flags.StringVar(&c.wsURL, "ws-url", "", "websocket URL")
+	flags.BoolVar(&c.json, "json", false, "print JSON")
+	flags.BoolVar(&c.yes, "yes", false, "do not ask for confirmation")
+	debug := flags.Bool("debug", false, "log requests")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *baseURL != "" {
+		config.BaseURL = *baseURL
+	}
+
+	c.client = bingx.NewClient(config.APIKey, config.SecretKey)
+	if config.BaseURL != "" {
+		c.client.BaseURL = config.BaseURL
+	}
+	c.client.Debug = *debug
+	c.client.Logger.SetOutput(stderr)
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+	switch command {
+	case "time":
+		return c.time(ctx, args)
+	case "balance":
+		return c.balance(ctx, args)
+	case "positions":
+		return c.positions(ctx, args)
+	case "orders":
+		return c.orders(ctx, args)
+	case "klines":
+		return c.klines(ctx, args)
+	case "stream":
+		return c.stream(ctx, args)
+	}
+	flags.Usage()
+	return fmt.Errorf("unknown command %q", command)
+}
+
+// subcommand Split args into subcommand and its args
+func subcommand(name string, args []string, subcommands ...string) (string, []string, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("%s: subcommand is required, one of %s", name, strings.Join(subcommands, ", "))
+	}
+	for _, sub := range subcommands {
+		if args[0] == sub {
+			return sub, args[1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("%s: unknown subcommand %q, expected one of %s", name, args[0], strings.Join(subcommands, ", "))
+}
+
+// newFlagSet Init flags of command which print errors to stderr
+func (c *cli) newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	return flags
+}
+
+// confirm Ask user to confirm action, returns errAborted unless answer is yes
+func (c *cli) confirm(format string, args ...interface{}) error {
+	if c.yes {
+		return nil
+	}
+
+	fmt.Fprintf(c.stderr, format+" [y/N] ", args...)
+	var answer string
+	fmt.Fscanln(c.stdin, &answer)
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errAborted
+}
+
+// wsOptions Options of streams pointing them to -ws-url
+func (c *cli) wsOptions(query string) []bingx.WsOption {
+	if c.wsURL == "" {
+		return nil
+	}
+	return []bingx.WsOption{bingx.WithWsEndpoint(c.wsURL + query)}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+	"github.com/magicaleks/go-bingx/bingxtest"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer Define buffer written by stream handlers and read by test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newServer(t *testing.T) *bingxtest.Server {
+	server := bingxtest.NewServer("apiKey", "secretKey")
+	t.Cleanup(server.Close)
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(apiKeyEnv, server.APIKey)
+	t.Setenv(secretKeyEnv, server.SecretKey)
+	t.Setenv(baseURLEnv, server.URL)
+	return server
+}
+
+// execute Run command with stdin and return its output
+func execute(t *testing.T, stdin string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestOrders(t *testing.T) {
+	r := require.New(t)
+	server := newServer(t)
+	server.SetPrice("BTC-USDT", 43000)
+
+	_, err := execute(t, "n\n", "orders", "create", "-symbol", "BTC-USDT", "-side", "buy", "-price", "42000", "-quantity", "0.01")
+	r.True(errors.Is(err, errAborted), err)
+	r.Empty(server.Orders())
+	r.Equal("LIMIT BUY order of 0.01 BTC-USDT at 42000, reduce only", describeOrder(&bingx.OrderRequest{
+		Symbol: "BTC-USDT", Type: bingx.LimitOrderType, Side: bingx.BuySideType, Price: 42000, Quantity: 0.01, ReduceOnly: true,
+	}))
+
+	out, err := execute(t, "y\n", "orders", "create", "-symbol", "BTC-USDT", "-side", "buy", "-price", "42000", "-quantity", "0.01", "-client-id", "cli")
+	r.NoError(err)
+	r.Regexp(`^order \d+ created\n$`, out)
+	id := strings.Fields(out)[1]
+
+	out, err = execute(t, "", "orders", "list")
+	r.NoError(err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	r.Len(lines, 2)
+	r.Contains(lines[0], "ORDER ID")
+	r.Regexp(`^`+id+`\s+cli\s+BTC-USDT\s+BUY\s+BOTH\s+LIMIT\s+42000\s+0.01\s+0\s+0\s+NEW`, lines[1])
+
+	out, err = execute(t, "", "-json", "orders", "get", "-symbol", "BTC-USDT", "-client-id", "cli")
+	r.NoError(err)
+	order := new(bingx.GetOrderResponse)
+	r.NoError(json.Unmarshal([]byte(out), order))
+	r.Equal(id, strconv.FormatInt(order.OrderId, 10))
+
+	_, err = execute(t, "n\n", "orders", "cancel", "-symbol", "BTC-USDT", "-id", id)
+	r.True(errors.Is(err, errAborted), err)
+	o, _ := server.Order(order.OrderId)
+	r.True(o.Open())
+
+	out, err = execute(t, "y\n", "orders", "cancel", "-symbol", "BTC-USDT", "-id", id)
+	r.NoError(err)
+	r.Contains(out, "CANCELED")
+
+	_, err = execute(t, "", "orders", "create", "-symbol", "BTC-USDT", "-side", "BUY", "-quantity", "0.01")
+	r.EqualError(err, "orders create: -price is required by limit order")
+	_, err = execute(t, "", "orders", "cancel", "-symbol", "BTC-USDT")
+	r.EqualError(err, "orders cancel: -id or -client-id is required")
+	_, err = execute(t, "", "orders", "move")
+	r.Error(err)
+}
+
+func TestCancelAll(t *testing.T) {
+	r := require.New(t)
+	server := newServer(t)
+	server.SetPrice("BTC-USDT", 43000)
+	for _, price := range []string{"42000", "41000"} {
+		_, err := execute(t, "", "-yes", "orders", "create", "-symbol", "BTC-USDT", "-side", "BUY", "-price", price, "-quantity", "0.01")
+		r.NoError(err)
+	}
+
+	// aborted on empty answer
+	_, err := execute(t, "", "orders", "cancel-all")
+	r.True(errors.Is(err, errAborted), err)
+
+	out, err := execute(t, "", "-yes", "-json", "orders", "cancel-all", "-symbol", "BTC-USDT")
+	r.NoError(err)
+	res := new(bingx.CancelAllOrdersResponse)
+	r.NoError(json.Unmarshal([]byte(out), res))
+	r.Len(res.Success, 2)
+	for _, o := range server.Orders() {
+		r.False(o.Open())
+	}
+}
+
+func TestAccount(t *testing.T) {
+	r := require.New(t)
+	server := newServer(t)
+	server.SetBalance(1000)
+	server.SetPosition(bingxtest.Position{Symbol: "BTC-USDT", PositionSide: "BOTH", Amount: 2, AvgPrice: 100})
+	server.SetPrice("BTC-USDT", 110)
+
+	out, err := execute(t, "", "balance")
+	r.NoError(err)
+	r.Regexp(`USDT\s+1000\s+1020`, out)
+
+	out, err = execute(t, "", "-json", "positions", "-symbol", "BTC-USDT")
+	r.NoError(err)
+	var positions []bingx.Position
+	r.NoError(json.Unmarshal([]byte(out), &positions))
+	r.Len(positions, 1)
+	r.Equal("20", positions[0].UnrealizedProfit)
+
+	out, err = execute(t, "", "time")
+	r.NoError(err)
+	_, err = time.Parse(timeLayout, strings.TrimSpace(out))
+	r.NoError(err)
+}
+
+func TestConfig(t *testing.T) {
+	r := require.New(t)
+	server := newServer(t)
+	t.Setenv(apiKeyEnv, "")
+	t.Setenv(secretKeyEnv, "")
+
+	_, err := execute(t, "", "balance")
+	r.Error(err)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	r.NoError(os.WriteFile(path, []byte(`{"apiKey": "apiKey", "secretKey": "secretKey", "baseURL": "http://localhost:1"}`), 0600))
+	_, err = execute(t, "", "-config", path, "-base-url", server.URL, "balance")
+	r.NoError(err)
+
+	_, err = execute(t, "", "-config", filepath.Join(t.TempDir(), "missing.json"), "balance")
+	r.True(errors.Is(err, os.ErrNotExist), err)
+}
+
+func TestKlines(t *testing.T) {
+	r := require.New(t)
+	server := newServer(t)
+	body := `{"code": 0, "msg": "", "data": [
+		{"open": "2", "close": "3", "high": "4", "low": "1", "volume": "10", "time": 3600000},
+		{"open": "1", "close": "2", "high": "3", "low": "1", "volume": "5", "time": 0}
+	]}`
+
+	server.Respond("GET", "/openApi/swap/v3/quote/klines", 200, body)
+	out, err := execute(t, "", "klines", "-symbol", "BTC-USDT", "-format", "csv", "-limit", "2")
+	r.NoError(err)
+	r.Equal("time,open,high,low,close,volume\n0,1,3,1,2,5\n3600000,2,4,1,3,10\n", out)
+	params := server.Requests()[0].Params
+	r.Equal("2", params.Get("limit"))
+	r.Equal("1h", params.Get("interval"))
+
+	server.Respond("GET", "/openApi/swap/v3/quote/klines", 200, body)
+	out, err = execute(t, "", "-json", "klines", "-symbol", "BTC-USDT", "-end", "1970-01-01 02:00")
+	r.NoError(err)
+	var klines []*bingx.Kline
+	r.NoError(json.Unmarshal([]byte(out), &klines))
+	r.Len(klines, 2)
+	r.Equal("7200000", server.Requests()[1].Params.Get("endTime"))
+
+	_, err = execute(t, "", "klines", "-symbol", "BTC-USDT", "-format", "xml")
+	r.Error(err)
+	_, err = execute(t, "", "klines", "-symbol", "BTC-USDT", "-start", "yesterday")
+	r.Error(err)
+}
+
+func TestStream(t *testing.T) {
+	r := require.New(t)
+	server := newServer(t)
+	server.SetPrice("BTC-USDT", 43000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stdout syncBuffer
+	errC := make(chan error, 2)
+	go func() {
+		errC <- run(ctx, []string{"-ws-url", server.WsURL, "stream", "klines", "-symbol", "BTC-USDT"}, strings.NewReader(""), &stdout, &stdout)
+	}()
+	go func() {
+		errC <- run(ctx, []string{"-ws-url", server.WsURL, "-json", "stream", "orders"}, strings.NewReader(""), &stdout, &stdout)
+	}()
+
+	r.NoError(server.WaitSubscription("BTC-USDT@kline_1m", 5*time.Second))
+	r.NoError(server.Publish("BTC-USDT@kline_1m", []map[string]interface{}{{"o": "1", "h": "3", "l": "1", "c": "2", "v": "5", "T": 60000}}))
+	r.NoError(server.WaitUserStream(5 * time.Second))
+	_, err := execute(t, "y\n", "orders", "create", "-symbol", "BTC-USDT", "-side", "SELL", "-type", "market", "-quantity", "0.01")
+	r.NoError(err)
+
+	r.Eventually(func() bool {
+		out := stdout.String()
+		return strings.Contains(out, "1970-01-01 00:01:00.000 BTC-USDT open=1 high=3 low=1 close=2 volume=5 open") &&
+			strings.Contains(out, `"X":"FILLED"`)
+	}, 5*time.Second, 10*time.Millisecond, stdout.String())
+
+	cancel()
+	for i := 0; i < 2; i++ {
+		r.NoError(<-errC)
+	}
+}
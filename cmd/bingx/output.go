@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+const timeLayout = "2006-01-02 15:04:05.000"
+
+// printJSON Print v as indented JSON
+func (c *cli) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printJSONLine Print v as one line of JSON, used by streams
+func (c *cli) printJSONLine(v interface{}) error {
+	return json.NewEncoder(c.stdout).Encode(v)
+}
+
+// printTable Print rows aligned in columns under header
+func (c *cli) printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// printKlinesCSV Print klines with the same columns as storage CSV files
+func (c *cli) printKlinesCSV(klines []*bingx.Kline) error {
+	w := csv.NewWriter(c.stdout)
+	w.Write([]string{"time", "open", "high", "low", "close", "volume"})
+	for _, k := range klines {
+		w.Write([]string{strconv.FormatInt(k.Time, 10), k.Open, k.High, k.Low, k.Close, k.Volume})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+var orderHeader = []string{"ORDER ID", "CLIENT ID", "SYMBOL", "SIDE", "POSITION", "TYPE", "PRICE", "QUANTITY", "FILLED", "AVG PRICE", "STATUS", "TIME"}
+
+func orderRow(o *bingx.GetOrderResponse) []string {
+	return []string{
+		strconv.FormatInt(o.OrderId, 10),
+		o.ClientOrderID,
+		o.Symbol,
+		string(o.Side),
+		string(o.PositionSide),
+		string(o.OrderType),
+		o.Price,
+		o.OrigQuantity,
+		o.Quantity,
+		o.AveragePrice,
+		string(o.Status),
+		formatMillis(o.Time),
+	}
+}
+
+// formatMillis Format unix milliseconds as UTC time
+func formatMillis(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format(timeLayout)
+}
+
+// parseTime Parse unix milliseconds, RFC 3339 time or UTC date with optional time, empty value is zero
+func parseTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return ms, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UnixMilli(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q, expected unix milliseconds, RFC 3339 or 2006-01-02 15:04", value)
+}
@@ -20,3 +20,8 @@ func DecodeGzip(data []byte) ([]byte, error) {
 
 	return decodedMsg, nil
 }
+
+// IsGzip reports whether data starts with gzip magic number
+func IsGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
@@ -0,0 +1,158 @@
+package bingx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultContractRegistryTTL = time.Hour
+	// maxContractRegistryMissRefresh caps the interval between refreshes caused by unknown symbols
+	maxContractRegistryMissRefresh = 5 * time.Second
+)
+
+// ContractRegistry caches the contract catalog and refreshes it in the background
+type ContractRegistry struct {
+	c         *Client
+	ttl       time.Duration
+	mu        sync.RWMutex
+	contracts map[string]*Contract
+	updatedAt time.Time
+	refreshMu sync.Mutex
+}
+
+// NewContractRegistry Init registry, zero ttl means one hour
+func (c *Client) NewContractRegistry(ttl time.Duration) *ContractRegistry {
+	if ttl <= 0 {
+		ttl = defaultContractRegistryTTL
+	}
+	return &ContractRegistry{
+		c:         c,
+		ttl:       ttl,
+		contracts: map[string]*Contract{},
+	}
+}
+
+// Refresh Load the whole catalog and replace cached contracts
+func (r *ContractRegistry) Refresh(ctx context.Context) error {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	res, err := r.c.NewGetContractsService().Do(ctx)
+	if err != nil {
+		return err
+	}
+
+	contracts := make(map[string]*Contract, len(res))
+	for _, contract := range res {
+		contracts[contract.Symbol] = contract
+	}
+
+	r.mu.Lock()
+	r.contracts = contracts
+	r.updatedAt = time.Now()
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Start Load the catalog and keep refreshing it every ttl until ctx is done
+func (r *ContractRegistry) Start(ctx context.Context, errHandler ErrHandler) error {
+	err := r.Refresh(ctx)
+	if err != nil {
+		return err
+	}
+
+	go r.run(ctx, errHandler)
+
+	return nil
+}
+
+func (r *ContractRegistry) run(ctx context.Context, errHandler ErrHandler) {
+	ticker := time.NewTicker(r.ttl)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := r.Refresh(ctx)
+			if err != nil && errHandler != nil && ctx.Err() == nil {
+				errHandler(err)
+			}
+		}
+	}
+}
+
+// Get Cached contract by symbol, never performs a request
+func (r *ContractRegistry) Get(symbol string) (*Contract, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contract, ok := r.contracts[symbol]
+	return contract, ok
+}
+
+// Lookup Cached contract by symbol, refreshes the catalog when it is stale or symbol is unknown.
+// Unknown symbol refreshes the catalog at most once per ttl/10 (5s at most), otherwise it is reported from cache.
+func (r *ContractRegistry) Lookup(ctx context.Context, symbol string) (*Contract, error) {
+	if !r.Stale() {
+		if contract, ok := r.Get(symbol); ok {
+			return contract, nil
+		}
+		if time.Since(r.UpdatedAt()) < r.missRefreshInterval() {
+			return nil, fmt.Errorf("bingx: unknown contract %s", symbol)
+		}
+	}
+
+	err := r.Refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, ok := r.Get(symbol)
+	if !ok {
+		return nil, fmt.Errorf("bingx: unknown contract %s", symbol)
+	}
+
+	return contract, nil
+}
+
+// missRefreshInterval minimal age of the catalog to be refreshed because of unknown symbol
+func (r *ContractRegistry) missRefreshInterval() time.Duration {
+	interval := r.ttl / 10
+	if interval > maxContractRegistryMissRefresh {
+		interval = maxContractRegistryMissRefresh
+	}
+	return interval
+}
+
+// Contracts Snapshot of all cached contracts
+func (r *ContractRegistry) Contracts() []*Contract {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*Contract, 0, len(r.contracts))
+	for _, contract := range r.contracts {
+		res = append(res, contract)
+	}
+
+	return res
+}
+
+// UpdatedAt Time of the last successful refresh
+func (r *ContractRegistry) UpdatedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.updatedAt
+}
+
+// Stale Catalog was never loaded or is older than ttl
+func (r *ContractRegistry) Stale() bool {
+	updatedAt := r.UpdatedAt()
+	return updatedAt.IsZero() || time.Since(updatedAt) > r.ttl
+}
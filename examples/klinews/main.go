@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
 
 	"github.com/magicaleks/go-bingx"
 )
@@ -9,7 +12,10 @@ import (
 func main() {
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 	symbol := "LINK-USDT"
-	doneC, _, err := bingx.WsKlineServe(symbol, bingx.Interval1, func(event *bingx.WsKlineEvent) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	stream, err := bingx.WsKlineServe(ctx, symbol, bingx.Interval1, func(event *bingx.WsKlineEvent) {
 		if event.Completed {
 			log.Printf("%s price update: %+v", symbol, event)
 		}
@@ -22,5 +28,6 @@ func main() {
 		return
 	}
 
-	<-doneC
+	<-stream.Done()
+	log.Printf("WsKline stopped: %v", stream.Err())
 }
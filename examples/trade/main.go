@@ -23,11 +23,16 @@ func main() {
 	}
 	log.Printf("Account subscription listen key: %s", listenKey)
 
-	doneC, _, err := bingx.WsOrderUpdateServe(listenKey, func(order *bingx.WsOrder) {
-		log.Printf("WsOrderUpdateServe update: %+v", order)
-	}, func(err error) {
-		log.Printf("WsOrderUpdateServe error: %s\n", err)
-	})
+	stream, err := bingx.NewUserDataStream(listenKey).
+		OrderUpdateHandler(func(order *bingx.WsOrder) {
+			log.Printf("Order update: %+v", order)
+		}).
+		AccountUpdateHandler(func(event *bingx.WsAccountUpdateEvent) {
+			log.Printf("Account update: %+v", event.Update)
+		}).
+		Serve(context.Background(), func(err error) {
+			log.Printf("UserDataStream error: %s\n", err)
+		})
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -58,5 +63,5 @@ func main() {
 	}
 	log.Printf("Limit order canceled: %+v", cancelResponse)
 
-	<-doneC
+	<-stream.Done()
 }
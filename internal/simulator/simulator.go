@@ -0,0 +1,656 @@
+// Package simulator matches orders of simulated BingX perpetual swap account. It keeps orders,
+// positions in cross margin mode and wallet balance, fills orders against prices set by caller
+// and liquidates positions when equity drops to maintenance margin. It is shared by
+// bingx.PaperClient and bingxtest.Server which translate requests and events of exchange API.
+package simulator
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Error codes of rejected requests, the same exchange returns
+const (
+	InvalidParamsCode      = 109400
+	OrderNotExistCode      = 80016
+	InsufficientMarginCode = 101204
+	NoPositionCode         = 101205
+	NoPriceCode            = 101400
+)
+
+// Defaults of Exchange
+const (
+	DefaultMakerFee          = 0.0002
+	DefaultTakerFee          = 0.0005
+	DefaultMaintenanceMargin = 0.004
+	Asset                    = "USDT"
+)
+
+// LiquidationClientOrderID client order id of orders closing positions on liquidation
+const LiquidationClientOrderID = "liquidation"
+
+// Error Define rejected request
+type Error struct {
+	Code    int64
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("code=%d, msg=%s", e.Code, e.Message)
+}
+
+func errorf(code int64, format string, args ...interface{}) error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Order Define simulated order
+type Order struct {
+	OrderId       int64
+	ClientOrderID string
+	Symbol        string
+	Side          string
+	PositionSide  string
+	Type          string
+	ReduceOnly    bool
+	TimeInForce   string
+	Price         float64
+	Quantity      float64
+	ExecutedQty   float64
+	AvgPrice      float64
+	// Commission paid for order, positive
+	Commission float64
+	Profit     float64
+	Status     string
+	Time       int64
+	UpdateTime int64
+}
+
+// Open reports whether order waits for price
+func (o *Order) Open() bool {
+	return o.Status == "NEW" || o.Status == "PARTIALLY_FILLED"
+}
+
+// closing reports whether order may only reduce position
+func (o *Order) closing() bool {
+	return o.ReduceOnly || o.PositionSide == "LONG" && o.Side == "SELL" || o.PositionSide == "SHORT" && o.Side == "BUY"
+}
+
+// Position Define simulated position, amount is negative for short positions
+type Position struct {
+	Symbol         string
+	PositionSide   string
+	Amount         float64
+	AvgPrice       float64
+	RealisedProfit float64
+}
+
+// Key identifies position by symbol and side
+func (p *Position) Key() string {
+	return p.Symbol + "/" + p.PositionSide
+}
+
+// OrderEvent Define change of order, Order is its state after the change
+type OrderEvent struct {
+	Order Order
+	// Spec NEW, TRADE, CANCELED or EXPIRED
+	Spec string
+	// LastQty and LastPrice of trade
+	LastQty   float64
+	LastPrice float64
+	// Fee of trade, positive when paid
+	Fee         float64
+	RealizedPnl float64
+}
+
+// AccountEvent Define change of balance and position
+type AccountEvent struct {
+	Time int64
+	// Reason ORDER, FUNDING_FEE or LIQUIDATION
+	Reason           string
+	Balance          float64
+	BalanceChange    float64
+	Position         Position
+	UnrealizedProfit float64
+}
+
+// Account Define balance summary
+type Account struct {
+	Balance          float64
+	Equity           float64
+	UnrealizedProfit float64
+	RealisedProfit   float64
+	AvailableMargin  float64
+	UsedMargin       float64
+	FrozenMargin     float64
+}
+
+// Exchange Define simulated account. It is not safe for concurrent use, events returned by
+// methods are *OrderEvent and *AccountEvent in order they happened.
+type Exchange struct {
+	Now               func() time.Time
+	Balance           float64
+	MakerFee          float64
+	TakerFee          float64
+	Slippage          float64
+	MaintenanceMargin float64
+	Leverage          map[string]int
+	// LastOrderId id of the last created order, the next one gets LastOrderId + 1
+	LastOrderId int64
+
+	prices    map[string]float64
+	orders    map[int64]*Order
+	positions map[string]*Position
+}
+
+// New Init exchange with default fees and no balance
+func New() *Exchange {
+	return &Exchange{
+		Now:               time.Now,
+		MakerFee:          DefaultMakerFee,
+		TakerFee:          DefaultTakerFee,
+		MaintenanceMargin: DefaultMaintenanceMargin,
+		Leverage:          map[string]int{},
+		prices:            map[string]float64{},
+		orders:            map[int64]*Order{},
+		positions:         map[string]*Position{},
+	}
+}
+
+// ParseOrder Parse order of create order request
+func ParseOrder(params url.Values) (*Order, error) {
+	o := &Order{
+		ClientOrderID: params.Get("clientOrderID"),
+		Symbol:        params.Get("symbol"),
+		Side:          params.Get("side"),
+		PositionSide:  params.Get("positionSide"),
+		Type:          params.Get("type"),
+		ReduceOnly:    params.Get("reduceOnly") == "true",
+		TimeInForce:   params.Get("timeInForce"),
+	}
+	var err error
+	if o.Quantity, err = parseFloat(params, "quantity"); err != nil {
+		return nil, err
+	}
+	if o.Price, err = parseFloat(params, "price"); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+func parseFloat(params url.Values, key string) (float64, error) {
+	if params.Get(key) == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(params.Get(key), 64)
+	if err != nil {
+		return 0, errorf(InvalidParamsCode, "invalid %s %q", key, params.Get(key))
+	}
+	return v, nil
+}
+
+// Price Price of symbol
+func (e *Exchange) Price(symbol string) (float64, bool) {
+	price, ok := e.prices[symbol]
+	return price, ok
+}
+
+// CreateOrder Validate and place order. Marketable orders take liquidity at current price,
+// post only orders expire instead, IOC and FOK orders expire when they are not marketable.
+func (e *Exchange) CreateOrder(o *Order) (*Order, []interface{}, error) {
+	if o.PositionSide == "" {
+		o.PositionSide = "BOTH"
+	}
+	switch {
+	case o.Symbol == "":
+		return nil, nil, errorf(InvalidParamsCode, "symbol is required")
+	case o.Side != "BUY" && o.Side != "SELL":
+		return nil, nil, errorf(InvalidParamsCode, "invalid side %q", o.Side)
+	case o.PositionSide != "BOTH" && o.PositionSide != "LONG" && o.PositionSide != "SHORT":
+		return nil, nil, errorf(InvalidParamsCode, "invalid positionSide %q", o.PositionSide)
+	case o.Type != "LIMIT" && o.Type != "MARKET":
+		return nil, nil, errorf(InvalidParamsCode, "unsupported order type %q", o.Type)
+	case o.Quantity <= 0:
+		return nil, nil, errorf(InvalidParamsCode, "quantity must be positive")
+	case o.Type == "LIMIT" && o.Price <= 0:
+		return nil, nil, errorf(InvalidParamsCode, "price must be positive")
+	}
+	if o.ClientOrderID != "" {
+		for _, other := range e.orders {
+			if other.ClientOrderID == o.ClientOrderID && other.Symbol == o.Symbol {
+				return nil, nil, errorf(InvalidParamsCode, "duplicate clientOrderID %q", o.ClientOrderID)
+			}
+		}
+	}
+
+	price, ok := e.prices[o.Symbol]
+	if !ok && o.Type == "MARKET" {
+		return nil, nil, errorf(NoPriceCode, "no price of %s", o.Symbol)
+	}
+	if o.Type == "MARKET" {
+		o.Price = 0
+	}
+
+	var reducible float64
+	if pos, ok := e.positions[positionKey(o.Symbol, o.PositionSide)]; ok && pos.Amount*delta(o.Side, 1) < 0 {
+		reducible = math.Abs(pos.Amount)
+	}
+	if o.ReduceOnly && reducible == 0 || o.PositionSide != "BOTH" && o.closing() && o.Quantity > reducible {
+		return nil, nil, errorf(NoPositionCode, "no position to reduce")
+	}
+	if opening := o.Quantity - reducible; opening > 0 && !o.closing() {
+		orderPrice := o.Price
+		if orderPrice == 0 || ok && price < orderPrice && o.Side == "BUY" {
+			orderPrice = price
+		}
+		required := opening * orderPrice * (1/float64(e.LeverageOf(o.Symbol)) + e.TakerFee)
+		if required > e.AvailableMargin() {
+			return nil, nil, errorf(InsufficientMarginCode, "Insufficient margin")
+		}
+	}
+
+	e.LastOrderId++
+	o.OrderId = e.LastOrderId
+	o.Status = "NEW"
+	o.Time = e.Now().UnixMilli()
+	o.UpdateTime = o.Time
+	e.orders[o.OrderId] = o
+	events := []interface{}{e.orderEvent(o, "NEW", 0, 0, 0, 0)}
+
+	marketable := o.Type == "MARKET" || ok && (o.Side == "BUY" && price <= o.Price || o.Side == "SELL" && price >= o.Price)
+	switch {
+	case marketable && o.TimeInForce == "PostOnly":
+		events = append(events, e.close(o, "EXPIRED"))
+	case marketable:
+		events = append(events, e.fill(o, e.takerPrice(o.Side, price, o.Price), e.TakerFee, "ORDER")...)
+		events = append(events, e.liquidate()...)
+	case o.TimeInForce == "IOC" || o.TimeInForce == "FOK":
+		events = append(events, e.close(o, "EXPIRED"))
+	}
+	return o, events, nil
+}
+
+// FindOrder Find order by orderId or clientOrderID of request, symbol is checked when it is set
+func (e *Exchange) FindOrder(params url.Values) (*Order, error) {
+	symbol := params.Get("symbol")
+	if id := params.Get("orderId"); id != "" {
+		orderId, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, errorf(InvalidParamsCode, "invalid orderId %q", id)
+		}
+		if o, ok := e.orders[orderId]; ok && (symbol == "" || o.Symbol == symbol) {
+			return o, nil
+		}
+	} else if clientOrderID := params.Get("clientOrderID"); clientOrderID != "" {
+		for _, o := range e.Orders() {
+			if o.ClientOrderID == clientOrderID && (symbol == "" || o.Symbol == symbol) {
+				return o, nil
+			}
+		}
+	}
+	return nil, errorf(OrderNotExistCode, "order not exist")
+}
+
+// Order Order by id
+func (e *Exchange) Order(orderId int64) (*Order, bool) {
+	o, ok := e.orders[orderId]
+	return o, ok
+}
+
+// CancelOrder Cancel open order found by request
+func (e *Exchange) CancelOrder(params url.Values) (*Order, []interface{}, error) {
+	o, err := e.FindOrder(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !o.Open() {
+		return nil, nil, errorf(OrderNotExistCode, "order is %s", o.Status)
+	}
+	return o, []interface{}{e.close(o, "CANCELED")}, nil
+}
+
+// CancelAllOrders Cancel open orders of symbol and type, empty values match all of them
+func (e *Exchange) CancelAllOrders(symbol, orderType string) ([]*Order, []interface{}) {
+	var canceled []*Order
+	var events []interface{}
+	for _, o := range e.Orders() {
+		if !o.Open() || symbol != "" && o.Symbol != symbol || orderType != "" && o.Type != orderType {
+			continue
+		}
+		events = append(events, e.close(o, "CANCELED"))
+		canceled = append(canceled, o)
+	}
+	return canceled, events
+}
+
+// Orders All orders sorted by id
+func (e *Exchange) Orders() []*Order {
+	orders := make([]*Order, 0, len(e.orders))
+	for _, o := range e.orders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].OrderId < orders[j].OrderId
+	})
+	return orders
+}
+
+// OpenOrders Open orders of symbol sorted by id, all symbols when it is empty
+func (e *Exchange) OpenOrders(symbol string) []*Order {
+	var orders []*Order
+	for _, o := range e.Orders() {
+		if o.Open() && (symbol == "" || o.Symbol == symbol) {
+			orders = append(orders, o)
+		}
+	}
+	return orders
+}
+
+// SetPosition Replace position of symbol and side, zero amount removes it
+func (e *Exchange) SetPosition(position Position) {
+	if position.PositionSide == "" {
+		position.PositionSide = "BOTH"
+	}
+	if position.Amount == 0 {
+		delete(e.positions, position.Key())
+		return
+	}
+	e.positions[position.Key()] = &position
+}
+
+// Positions Open positions of symbol sorted by symbol and side, all symbols when it is empty
+func (e *Exchange) Positions(symbol string) []*Position {
+	var positions []*Position
+	for _, pos := range e.positions {
+		if symbol == "" || pos.Symbol == symbol {
+			positions = append(positions, pos)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Key() < positions[j].Key()
+	})
+	return positions
+}
+
+// SetPrice Set price of symbol. Resting limit orders crossed by it are filled at their price
+// and positions are liquidated if margin is not enough.
+func (e *Exchange) SetPrice(symbol string, price float64) []interface{} {
+	var events []interface{}
+	e.prices[symbol] = price
+	for _, o := range e.Orders() {
+		if o.Symbol != symbol || !o.Open() {
+			continue
+		}
+		if o.Side == "BUY" && price <= o.Price || o.Side == "SELL" && price >= o.Price {
+			events = append(events, e.fill(o, o.Price, e.MakerFee, "ORDER")...)
+		}
+	}
+	return append(events, e.liquidate()...)
+}
+
+// ApplyFunding Charge funding of symbol positions at current price, longs pay shorts when rate is positive
+func (e *Exchange) ApplyFunding(symbol string, rate float64) []interface{} {
+	var events []interface{}
+	for _, pos := range e.Positions(symbol) {
+		payment := pos.Amount * e.MarkPrice(pos) * rate
+		e.Balance -= payment
+		events = append(events, e.accountEvent("FUNDING_FEE", pos, -payment))
+	}
+	return append(events, e.liquidate()...)
+}
+
+// Account Balance summary of account
+func (e *Exchange) Account() Account {
+	var a Account
+	for _, pos := range e.positions {
+		a.UnrealizedProfit += e.UnrealizedProfit(pos)
+		a.RealisedProfit += pos.RealisedProfit
+		a.UsedMargin += e.PositionMargin(pos)
+	}
+	a.Balance = e.Balance
+	a.FrozenMargin = e.frozenMargin()
+	a.Equity = e.Balance + a.UnrealizedProfit
+	a.AvailableMargin = a.Equity - a.UsedMargin - a.FrozenMargin
+	return a
+}
+
+// LeverageOf Leverage of symbol, default is 1
+func (e *Exchange) LeverageOf(symbol string) int {
+	if leverage := e.Leverage[symbol]; leverage > 0 {
+		return leverage
+	}
+	return 1
+}
+
+// MarkPrice Price of position symbol, entry price when it is unknown
+func (e *Exchange) MarkPrice(pos *Position) float64 {
+	if price, ok := e.prices[pos.Symbol]; ok {
+		return price
+	}
+	return pos.AvgPrice
+}
+
+// UnrealizedProfit Profit of position at mark price
+func (e *Exchange) UnrealizedProfit(pos *Position) float64 {
+	return pos.Amount * (e.MarkPrice(pos) - pos.AvgPrice)
+}
+
+// PositionMargin Initial margin of position
+func (e *Exchange) PositionMargin(pos *Position) float64 {
+	return math.Abs(pos.Amount) * pos.AvgPrice / float64(e.LeverageOf(pos.Symbol))
+}
+
+// MaintenanceMarginOf Maintenance margin of position at mark price
+func (e *Exchange) MaintenanceMarginOf(pos *Position) float64 {
+	return math.Abs(pos.Amount) * e.MarkPrice(pos) * e.MaintenanceMargin
+}
+
+// Equity Wallet balance with unrealized profit
+func (e *Exchange) Equity() float64 {
+	equity := e.Balance
+	for _, pos := range e.positions {
+		equity += e.UnrealizedProfit(pos)
+	}
+	return equity
+}
+
+// AvailableMargin Equity not used by positions and open orders
+func (e *Exchange) AvailableMargin() float64 {
+	return e.Account().AvailableMargin
+}
+
+// LiquidationPrice Price of position at which account is liquidated, other prices being equal
+func (e *Exchange) LiquidationPrice(pos *Position) float64 {
+	others := e.Balance
+	for _, other := range e.positions {
+		if other != pos {
+			others += e.UnrealizedProfit(other) - e.MaintenanceMarginOf(other)
+		}
+	}
+	// others + amount * (price - avgPrice) = |amount| * price * maintenanceMargin
+	denominator := math.Abs(pos.Amount)*e.MaintenanceMargin - pos.Amount
+	if denominator == 0 {
+		return 0
+	}
+	price := (others - pos.Amount*pos.AvgPrice) / denominator
+	if price < 0 {
+		return 0
+	}
+	return price
+}
+
+func (e *Exchange) frozenMargin() float64 {
+	var frozen float64
+	for _, o := range e.orders {
+		if o.Open() && !o.closing() {
+			frozen += (o.Quantity - o.ExecutedQty) * o.Price / float64(e.LeverageOf(o.Symbol))
+		}
+	}
+	return frozen
+}
+
+// close finishes order without fill with status CANCELED or EXPIRED
+func (e *Exchange) close(o *Order, status string) *OrderEvent {
+	o.Status = status
+	o.UpdateTime = e.Now().UnixMilli()
+	return e.orderEvent(o, status, 0, 0, 0, 0)
+}
+
+// fill executes rest of order at price, updates position and balance and returns events
+func (e *Exchange) fill(o *Order, price, feeRate float64, reason string) []interface{} {
+	key := positionKey(o.Symbol, o.PositionSide)
+	pos, ok := e.positions[key]
+	if !ok {
+		pos = &Position{Symbol: o.Symbol, PositionSide: o.PositionSide}
+	}
+
+	qty := o.Quantity - o.ExecutedQty
+	if o.closing() && qty > math.Abs(pos.Amount) {
+		qty = math.Abs(pos.Amount)
+	}
+	if qty == 0 || o.closing() && pos.Amount*delta(o.Side, 1) > 0 {
+		// position to reduce is gone
+		return []interface{}{e.close(o, "EXPIRED")}
+	}
+
+	d := delta(o.Side, qty)
+	var pnl float64
+	if pos.Amount != 0 && pos.Amount*d < 0 {
+		closed := math.Min(qty, math.Abs(pos.Amount))
+		pnl = closed * (price - pos.AvgPrice)
+		if pos.Amount < 0 {
+			pnl = -pnl
+		}
+	}
+	amount := pos.Amount + d
+	switch {
+	case amount == 0:
+		pos.AvgPrice = 0
+	case pos.Amount == 0 || amount*pos.Amount < 0:
+		pos.AvgPrice = price
+	case math.Abs(amount) > math.Abs(pos.Amount):
+		pos.AvgPrice = (math.Abs(pos.Amount)*pos.AvgPrice + qty*price) / math.Abs(amount)
+	}
+	pos.Amount = amount
+	pos.RealisedProfit += pnl
+	if amount == 0 {
+		delete(e.positions, key)
+	} else {
+		e.positions[key] = pos
+	}
+
+	fee := qty * price * feeRate
+	e.Balance += pnl - fee
+
+	clipped := qty < o.Quantity-o.ExecutedQty
+	o.AvgPrice = (o.AvgPrice*o.ExecutedQty + price*qty) / (o.ExecutedQty + qty)
+	o.ExecutedQty += qty
+	o.Commission += fee
+	o.Profit += pnl
+	o.Status = "FILLED"
+	if clipped {
+		o.Status = "PARTIALLY_FILLED"
+	}
+	o.UpdateTime = e.Now().UnixMilli()
+
+	events := []interface{}{
+		e.orderEvent(o, "TRADE", qty, price, fee, pnl),
+		e.accountEvent(reason, pos, pnl-fee),
+	}
+	if clipped {
+		// reduce only order is limited by position, the rest expires
+		events = append(events, e.close(o, "EXPIRED"))
+	}
+	return events
+}
+
+// liquidate closes all positions at current prices when equity drops to maintenance margin
+func (e *Exchange) liquidate() []interface{} {
+	if len(e.positions) == 0 {
+		return nil
+	}
+	var maintenance float64
+	for _, pos := range e.positions {
+		maintenance += e.MaintenanceMarginOf(pos)
+	}
+	if e.Equity() > maintenance {
+		return nil
+	}
+
+	var events []interface{}
+	for _, o := range e.OpenOrders("") {
+		events = append(events, e.close(o, "CANCELED"))
+	}
+	for _, pos := range e.Positions("") {
+		side := "SELL"
+		if pos.Amount < 0 {
+			side = "BUY"
+		}
+		e.LastOrderId++
+		o := &Order{
+			OrderId:       e.LastOrderId,
+			ClientOrderID: LiquidationClientOrderID,
+			Symbol:        pos.Symbol,
+			Side:          side,
+			PositionSide:  pos.PositionSide,
+			Type:          "MARKET",
+			ReduceOnly:    true,
+			Quantity:      math.Abs(pos.Amount),
+			Status:        "NEW",
+			Time:          e.Now().UnixMilli(),
+		}
+		e.orders[o.OrderId] = o
+		events = append(events, e.fill(o, e.takerPrice(side, e.MarkPrice(pos), 0), e.TakerFee, "LIQUIDATION")...)
+	}
+	// losses beyond wallet balance are not charged
+	if e.Balance < 0 {
+		e.Balance = 0
+	}
+	return events
+}
+
+// takerPrice moves price against order by slippage, not beyond limit price if it is set
+func (e *Exchange) takerPrice(side string, price, limit float64) float64 {
+	if side == "BUY" {
+		price *= 1 + e.Slippage
+		if limit > 0 && price > limit {
+			price = limit
+		}
+		return price
+	}
+	price *= 1 - e.Slippage
+	if limit > 0 && price < limit {
+		price = limit
+	}
+	return price
+}
+
+func (e *Exchange) orderEvent(o *Order, spec string, lastQty, lastPrice, fee, pnl float64) *OrderEvent {
+	return &OrderEvent{Order: *o, Spec: spec, LastQty: lastQty, LastPrice: lastPrice, Fee: fee, RealizedPnl: pnl}
+}
+
+func (e *Exchange) accountEvent(reason string, pos *Position, change float64) *AccountEvent {
+	return &AccountEvent{
+		Time:             e.Now().UnixMilli(),
+		Reason:           reason,
+		Balance:          e.Balance,
+		BalanceChange:    change,
+		Position:         *pos,
+		UnrealizedProfit: e.UnrealizedProfit(pos),
+	}
+}
+
+func positionKey(symbol, positionSide string) string {
+	return symbol + "/" + positionSide
+}
+
+// delta signed change of position amount by trade
+func delta(side string, qty float64) float64 {
+	if side == "SELL" {
+		return -qty
+	}
+	return qty
+}
@@ -0,0 +1,78 @@
+package simulator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newExchange() *Exchange {
+	e := New()
+	e.Now = func() time.Time {
+		return time.UnixMilli(1000)
+	}
+	e.Balance = 1000
+	e.MakerFee, e.TakerFee = 0, 0
+	e.Leverage["BTC-USDT"] = 10
+	return e
+}
+
+func TestOrders(t *testing.T) {
+	r := require.New(t)
+	e := newExchange()
+
+	_, _, err := e.CreateOrder(&Order{Symbol: "BTC-USDT", Side: "BUY", Type: "MARKET", Quantity: 1})
+	simErr := new(Error)
+	r.True(errors.As(err, &simErr), err)
+	r.Equal(int64(NoPriceCode), simErr.Code)
+
+	r.Empty(e.SetPrice("BTC-USDT", 100))
+	o, events, err := e.CreateOrder(&Order{Symbol: "BTC-USDT", Side: "BUY", Type: "LIMIT", Price: 95, Quantity: 2})
+	r.NoError(err)
+	r.Len(events, 1)
+	r.True(o.Open())
+	r.Equal(float64(19), e.Account().FrozenMargin)
+
+	events = e.SetPrice("BTC-USDT", 94)
+	r.Len(events, 2)
+	trade := events[0].(*OrderEvent)
+	r.Equal("TRADE", trade.Spec)
+	r.Equal(float64(95), trade.LastPrice)
+	r.Equal(float64(2), trade.Order.Quantity)
+	r.Equal("FILLED", trade.Order.Status)
+	account := events[1].(*AccountEvent)
+	r.Equal(float64(2), account.Position.Amount)
+	r.Equal(float64(-2), account.UnrealizedProfit)
+
+	// reduce only order is limited by position
+	o, events, err = e.CreateOrder(&Order{Symbol: "BTC-USDT", Side: "SELL", Type: "MARKET", ReduceOnly: true, Quantity: 5})
+	r.NoError(err)
+	r.Len(events, 4)
+	r.Equal(float64(5), o.Quantity)
+	r.Equal(float64(2), o.ExecutedQty)
+	r.Equal("EXPIRED", o.Status)
+	r.Equal(float64(998), e.Balance)
+	r.Empty(e.Positions(""))
+}
+
+func TestLiquidation(t *testing.T) {
+	r := require.New(t)
+	e := newExchange()
+	e.SetPrice("BTC-USDT", 100)
+
+	_, _, err := e.CreateOrder(&Order{Symbol: "BTC-USDT", Side: "BUY", Type: "MARKET", Quantity: 90})
+	r.NoError(err)
+	pos := e.Positions("BTC-USDT")[0]
+	liquidation := e.LiquidationPrice(pos)
+	r.InDelta(89.25, liquidation, 0.01)
+
+	r.Empty(e.SetPrice("BTC-USDT", liquidation+0.1))
+	events := e.SetPrice("BTC-USDT", liquidation-0.1)
+	r.NotEmpty(events)
+	r.Empty(e.Positions(""))
+	orders := e.Orders()
+	r.Equal(LiquidationClientOrderID, orders[len(orders)-1].ClientOrderID)
+	r.Equal("LIQUIDATION", events[len(events)-1].(*AccountEvent).Reason)
+}
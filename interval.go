@@ -0,0 +1,253 @@
+package bingx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var intervalDurations = map[Interval]time.Duration{
+	Interval1:   time.Minute,
+	Interval3:   3 * time.Minute,
+	Interval5:   5 * time.Minute,
+	Interval15:  15 * time.Minute,
+	Interval30:  30 * time.Minute,
+	Interval60:  time.Hour,
+	Interval2h:  2 * time.Hour,
+	Interval4h:  4 * time.Hour,
+	Interval6h:  6 * time.Hour,
+	Interval8h:  8 * time.Hour,
+	Interval12h: 12 * time.Hour,
+	Interval1d:  24 * time.Hour,
+	Interval3d:  3 * 24 * time.Hour,
+	Interval1w:  7 * 24 * time.Hour,
+	Interval1M:  31 * 24 * time.Hour,
+}
+
+// Weekly candles open on Monday, 1970-01-05 is the first Monday after epoch
+var weekOrigin = time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// Duration Length of one candle, the longest month for 1M, zero for unknown interval
+func (i Interval) Duration() time.Duration {
+	return intervalDurations[i]
+}
+
+// Valid Interval is supported by the API
+func (i Interval) Valid() bool {
+	return i.Duration() != 0
+}
+
+// Calendar Candle length depends on calendar, only 1M for now
+func (i Interval) Calendar() bool {
+	return i == Interval1M
+}
+
+// Truncate Open time of the candle containing t, candles are aligned in UTC
+func (i Interval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case Interval1M:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case Interval1w:
+		weeks := t.Sub(weekOrigin) / i.Duration()
+		if t.Before(weekOrigin.Add(weeks * i.Duration())) {
+			weeks--
+		}
+		return weekOrigin.Add(weeks * i.Duration())
+	}
+
+	d := i.Duration()
+	if d == 0 {
+		return t
+	}
+	ms := t.UnixMilli()
+	step := d.Milliseconds()
+	open := ms - ms%step
+	if ms%step < 0 {
+		open -= step
+	}
+	return time.UnixMilli(open).UTC()
+}
+
+// Next Open time of the candle following the one containing t
+func (i Interval) Next(t time.Time) time.Time {
+	open := i.Truncate(t)
+	if i == Interval1M {
+		return open.AddDate(0, 1, 0)
+	}
+	return open.Add(i.Duration())
+}
+
+// Prev Open time of the candle preceding the one containing t
+func (i Interval) Prev(t time.Time) time.Time {
+	return i.Truncate(i.Truncate(t).Add(-time.Millisecond))
+}
+
+// Contains Candles of i can be built from whole candles of sub
+func (i Interval) Contains(sub Interval) bool {
+	if !i.Valid() || !sub.Valid() || sub.Calendar() {
+		return false
+	}
+	if i.Calendar() || i == Interval1w {
+		day := Interval1d.Duration()
+		return sub.Duration() <= day && day%sub.Duration() == 0
+	}
+	return i.Duration()%sub.Duration() == 0
+}
+
+// ResampleKlines Build candles of interval to from ascending candles of interval from.
+// The last candle may be incomplete when source does not cover it.
+func ResampleKlines(klines []*Kline, from, to Interval) ([]*Kline, error) {
+	if !to.Contains(from) {
+		return nil, fmt.Errorf("bingx: can not resample %s klines to %s", from, to)
+	}
+
+	var res []*Kline
+	var current *Kline
+	var agg [5]float64
+	flush := func() {
+		if current != nil {
+			current.Open = formatKlineValue(agg[0])
+			current.High = formatKlineValue(agg[1])
+			current.Low = formatKlineValue(agg[2])
+			current.Close = formatKlineValue(agg[3])
+			current.Volume = formatKlineValue(agg[4])
+			res = append(res, current)
+		}
+	}
+
+	for _, kline := range klines {
+		values, err := parseKlineValues(kline)
+		if err != nil {
+			return nil, err
+		}
+
+		bucket := to.Truncate(time.UnixMilli(kline.Time)).UnixMilli()
+		if current == nil || current.Time != bucket {
+			if current != nil && bucket < current.Time {
+				return nil, fmt.Errorf("bingx: klines are not sorted at %d", kline.Time)
+			}
+			flush()
+			current = &Kline{Time: bucket}
+			agg = values
+			continue
+		}
+
+		if values[1] > agg[1] {
+			agg[1] = values[1]
+		}
+		if values[2] < agg[2] {
+			agg[2] = values[2]
+		}
+		agg[3] = values[3]
+		agg[4] += values[4]
+	}
+	flush()
+
+	return res, nil
+}
+
+// parseKlineValues returns open, high, low, close and volume
+func parseKlineValues(kline *Kline) ([5]float64, error) {
+	var values [5]float64
+	for i, s := range []string{kline.Open, kline.High, kline.Low, kline.Close, kline.Volume} {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return values, fmt.Errorf("bingx: invalid kline %d: %w", kline.Time, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func formatKlineValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// KlineResampler Aggregates kline stream of one symbol into higher interval.
+// Every source update produces an update of the aggregated candle, the aggregated
+// candle is completed once the last source candle of it is completed or the next one starts.
+type KlineResampler struct {
+	from    Interval
+	to      Interval
+	handler WsKlineHandler
+	bucket  int64
+	parts   map[int64]*WsKlineEvent
+	done    bool
+}
+
+func NewKlineResampler(from, to Interval, handler WsKlineHandler) (*KlineResampler, error) {
+	if !to.Contains(from) {
+		return nil, fmt.Errorf("bingx: can not resample %s klines to %s", from, to)
+	}
+	return &KlineResampler{
+		from:    from,
+		to:      to,
+		handler: handler,
+		parts:   map[int64]*WsKlineEvent{},
+	}, nil
+}
+
+// Handle Feed source event, suitable as WsKlineHandler
+func (r *KlineResampler) Handle(event *WsKlineEvent) {
+	t := time.UnixMilli(int64(event.Time))
+	bucket := r.to.Truncate(t).UnixMilli()
+
+	if len(r.parts) > 0 && bucket != r.bucket {
+		if bucket < r.bucket {
+			return
+		}
+		if !r.done {
+			r.handler(r.aggregate(true))
+		}
+		r.parts = map[int64]*WsKlineEvent{}
+	}
+	if len(r.parts) == 0 {
+		r.bucket = bucket
+		r.done = false
+	}
+	if r.done {
+		return
+	}
+
+	part := *event
+	r.parts[int64(event.Time)] = &part
+
+	completed := event.Completed && !r.from.Next(t).Before(r.to.Next(t))
+	r.done = completed
+	r.handler(r.aggregate(completed))
+}
+
+func (r *KlineResampler) aggregate(completed bool) *WsKlineEvent {
+	times := make([]int64, 0, len(r.parts))
+	for t := range r.parts {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i] < times[j]
+	})
+
+	first := r.parts[times[0]]
+	res := &WsKlineEvent{
+		Symbol:    first.Symbol,
+		Open:      first.Open,
+		High:      first.High,
+		Low:       first.Low,
+		Time:      float64(r.bucket),
+		Completed: completed,
+	}
+	for _, t := range times {
+		part := r.parts[t]
+		if part.High > res.High {
+			res.High = part.High
+		}
+		if part.Low < res.Low {
+			res.Low = part.Low
+		}
+		res.Close = part.Close
+		res.Volume += part.Volume
+	}
+
+	return res
+}
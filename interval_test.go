@@ -0,0 +1,106 @@
+package bingx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type intervalTestSuite struct {
+	suite.Suite
+}
+
+func TestInterval(t *testing.T) {
+	suite.Run(t, new(intervalTestSuite))
+}
+
+func date(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func (s *intervalTestSuite) TestTruncateNext() {
+	r := s.Require()
+	t := date(2024, time.February, 29, 13, 47).Add(1500 * time.Millisecond)
+
+	cases := []struct {
+		interval Interval
+		open     time.Time
+		next     time.Time
+	}{
+		{Interval1, date(2024, time.February, 29, 13, 47), date(2024, time.February, 29, 13, 48)},
+		{Interval15, date(2024, time.February, 29, 13, 45), date(2024, time.February, 29, 14, 0)},
+		{Interval4h, date(2024, time.February, 29, 12, 0), date(2024, time.February, 29, 16, 0)},
+		{Interval1d, date(2024, time.February, 29, 0, 0), date(2024, time.March, 1, 0, 0)},
+		{Interval1w, date(2024, time.February, 26, 0, 0), date(2024, time.March, 4, 0, 0)},
+		{Interval1M, date(2024, time.February, 1, 0, 0), date(2024, time.March, 1, 0, 0)},
+	}
+	for _, c := range cases {
+		r.Equal(c.open, c.interval.Truncate(t), string(c.interval))
+		r.Equal(c.next, c.interval.Next(t), string(c.interval))
+		r.Equal(c.open, c.interval.Truncate(c.open), string(c.interval))
+	}
+
+	r.Equal(date(2023, time.December, 1, 0, 0), Interval1M.Prev(date(2024, time.January, 15, 0, 0)))
+	r.Equal(date(1970, time.January, 5, 0, 0).Add(-7*24*time.Hour), Interval1w.Truncate(date(1970, time.January, 1, 0, 0)))
+	r.Equal(time.Minute, Interval1.Duration())
+	r.False(Interval("7m").Valid())
+}
+
+func (s *intervalTestSuite) TestContains() {
+	r := s.Require()
+	r.True(Interval15.Contains(Interval1))
+	r.True(Interval4h.Contains(Interval60))
+	r.True(Interval1w.Contains(Interval1d))
+	r.True(Interval1M.Contains(Interval4h))
+	r.False(Interval1w.Contains(Interval3d))
+	r.False(Interval1M.Contains(Interval1w))
+	r.False(Interval60.Contains(Interval4h))
+}
+
+func (s *intervalTestSuite) TestResampleKlines() {
+	r := s.Require()
+	base := date(2024, time.January, 1, 0, 0).UnixMilli()
+	klines := []*Kline{
+		{Time: base, Open: "10", High: "12", Low: "9", Close: "11", Volume: "1"},
+		{Time: base + 3600000, Open: "11", High: "15", Low: "10", Close: "14", Volume: "2.5"},
+		{Time: base + 3*3600000, Open: "14", High: "14", Low: "7", Close: "8", Volume: "0.5"},
+		{Time: base + 4*3600000, Open: "8", High: "9", Low: "8", Close: "9", Volume: "3"},
+	}
+
+	res, err := ResampleKlines(klines, Interval60, Interval4h)
+	r.NoError(err)
+	r.Equal([]*Kline{
+		{Time: base, Open: "10", High: "15", Low: "7", Close: "8", Volume: "4"},
+		{Time: base + 4*3600000, Open: "8", High: "9", Low: "8", Close: "9", Volume: "3"},
+	}, res)
+
+	_, err = ResampleKlines(klines, Interval4h, Interval60)
+	r.Error(err)
+}
+
+func (s *intervalTestSuite) TestKlineResampler() {
+	r := s.Require()
+	var events []WsKlineEvent
+	resampler, err := NewKlineResampler(Interval1, Interval3, func(event *WsKlineEvent) {
+		events = append(events, *event)
+	})
+	r.NoError(err)
+
+	base := float64(date(2024, time.January, 1, 0, 0).UnixMilli())
+	resampler.Handle(&WsKlineEvent{Symbol: "BTC-USDT", Time: base, Open: 10, High: 11, Low: 9, Close: 10, Volume: 1})
+	resampler.Handle(&WsKlineEvent{Symbol: "BTC-USDT", Time: base, Open: 10, High: 12, Low: 9, Close: 11, Volume: 2, Completed: true})
+	resampler.Handle(&WsKlineEvent{Symbol: "BTC-USDT", Time: base + 60000, Open: 11, High: 13, Low: 8, Close: 12, Volume: 3})
+	resampler.Handle(&WsKlineEvent{Symbol: "BTC-USDT", Time: base + 180000, Open: 12, High: 12, Low: 12, Close: 12, Volume: 1})
+
+	r.Len(events, 5)
+	r.False(events[1].Completed)
+	r.Equal(WsKlineEvent{Symbol: "BTC-USDT", Time: base, Open: 10, High: 13, Low: 8, Close: 12, Volume: 5}, events[2])
+	r.Equal(WsKlineEvent{Symbol: "BTC-USDT", Time: base, Open: 10, High: 13, Low: 8, Close: 12, Volume: 5, Completed: true}, events[3])
+	r.Equal(base+180000, events[4].Time)
+
+	resampler.Handle(&WsKlineEvent{Symbol: "BTC-USDT", Time: base + 300000, Open: 12, High: 12, Low: 12, Close: 13, Volume: 1, Completed: true})
+	r.True(events[5].Completed)
+	resampler.Handle(&WsKlineEvent{Symbol: "BTC-USDT", Time: base + 300000, Open: 12, High: 12, Low: 12, Close: 14, Volume: 1, Completed: true})
+	r.Len(events, 6)
+}
@@ -0,0 +1,348 @@
+package bingx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultKlinePageSize       = 1000
+	defaultKlineRequestsPeriod = 100 * time.Millisecond
+)
+
+// KlineGap Define range of missing candles, both bounds are open times of missing candles
+type KlineGap struct {
+	StartTime int64
+	EndTime   int64
+}
+
+// KlineDownload Define result of downloaded range
+type KlineDownload struct {
+	Klines []*Kline
+	Gaps   []KlineGap
+}
+
+// KlineDownloader Downloads arbitrary long kline ranges page by page
+type KlineDownloader struct {
+	c              *Client
+	symbol         string
+	interval       Interval
+	startTime      int64
+	endTime        int64
+	pageSize       int64
+	concurrency    int
+	requestsPeriod time.Duration
+	gapHandler     func(KlineGap)
+}
+
+func (c *Client) NewKlineDownloader() *KlineDownloader {
+	return &KlineDownloader{
+		c:              c,
+		pageSize:       defaultKlinePageSize,
+		concurrency:    1,
+		requestsPeriod: defaultKlineRequestsPeriod,
+	}
+}
+
+func (d *KlineDownloader) Symbol(symbol string) *KlineDownloader {
+	d.symbol = symbol
+	return d
+}
+
+func (d *KlineDownloader) Interval(interval Interval) *KlineDownloader {
+	d.interval = interval
+	return d
+}
+
+// StartTime Range start in milliseconds, required
+func (d *KlineDownloader) StartTime(startTime int64) *KlineDownloader {
+	d.startTime = startTime
+	return d
+}
+
+// EndTime Range end in milliseconds, current time when omitted
+func (d *KlineDownloader) EndTime(endTime int64) *KlineDownloader {
+	d.endTime = endTime
+	return d
+}
+
+// PageSize Candles per request, max 1440
+func (d *KlineDownloader) PageSize(pageSize int64) *KlineDownloader {
+	d.pageSize = pageSize
+	return d
+}
+
+// Concurrency Max number of requests in flight
+func (d *KlineDownloader) Concurrency(concurrency int) *KlineDownloader {
+	d.concurrency = concurrency
+	return d
+}
+
+// RateLimit Max number of requests per second, zero disables limiting
+func (d *KlineDownloader) RateLimit(requestsPerSecond int) *KlineDownloader {
+	if requestsPerSecond <= 0 {
+		d.requestsPeriod = 0
+	} else {
+		d.requestsPeriod = time.Second / time.Duration(requestsPerSecond)
+	}
+	return d
+}
+
+// GapHandler Called for every gap before the first candle after it is streamed
+func (d *KlineDownloader) GapHandler(handler func(KlineGap)) *KlineDownloader {
+	d.gapHandler = handler
+	return d
+}
+
+type klinePage struct {
+	startTime int64
+	endTime   int64
+	result    chan klinePageResult
+}
+
+type klinePageResult struct {
+	klines []*Kline
+	err    error
+}
+
+func (d *KlineDownloader) validate(endTime int64) error {
+	if d.symbol == "" {
+		return fmt.Errorf("bingx: kline downloader symbol is required")
+	}
+	if d.interval.Duration() == 0 {
+		return fmt.Errorf("bingx: unsupported kline interval %q", d.interval)
+	}
+	if d.pageSize <= 0 {
+		return fmt.Errorf("bingx: invalid kline page size %d", d.pageSize)
+	}
+	if d.concurrency <= 0 {
+		return fmt.Errorf("bingx: invalid kline download concurrency %d", d.concurrency)
+	}
+	// range from 1970 would take tens of thousands of requests
+	if d.startTime <= 0 {
+		return fmt.Errorf("bingx: kline downloader start time is required")
+	}
+	if d.startTime > endTime {
+		return fmt.Errorf("bingx: kline downloader start time %d is after end time %d", d.startTime, endTime)
+	}
+	return nil
+}
+
+func (d *KlineDownloader) pages(endTime int64) []*klinePage {
+	span := d.pageSize * d.interval.Duration().Milliseconds()
+
+	var pages []*klinePage
+	for start := d.startTime; start <= endTime; start += span {
+		end := start + span - 1
+		if end > endTime {
+			end = endTime
+		}
+		pages = append(pages, &klinePage{
+			startTime: start,
+			endTime:   end,
+			result:    make(chan klinePageResult, 1),
+		})
+	}
+
+	return pages
+}
+
+// Stream Fetch range and stream deduplicated candles in ascending order.
+// Klines channel is closed when download is finished, error channel receives at most one error.
+func (d *KlineDownloader) Stream(ctx context.Context) (<-chan *Kline, <-chan error) {
+	klinesC := make(chan *Kline)
+	errC := make(chan error, 1)
+
+	go func() {
+		defer close(errC)
+		defer close(klinesC)
+
+		err := d.stream(ctx, klinesC, d.gapHandler)
+		if err != nil {
+			errC <- err
+		}
+	}()
+
+	return klinesC, errC
+}
+
+func (d *KlineDownloader) stream(ctx context.Context, klinesC chan<- *Kline, gapHandler func(KlineGap)) error {
+	endTime := d.endTime
+	now := time.Now().UnixMilli()
+	if endTime == 0 || endTime > now {
+		endTime = now
+	}
+
+	err := d.validate(endTime)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	// fetches still running are cancelled before waiting for them
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	limiter := newRateLimiter(d.requestsPeriod)
+	pages := d.pages(endTime)
+	launched := 0
+	launch := func(upTo int) {
+		for ; launched < len(pages) && launched < upTo; launched++ {
+			page := pages[launched]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				page.result <- d.fetch(ctx, limiter, page)
+			}()
+		}
+	}
+
+	reportGap := func(gap KlineGap) {
+		if gapHandler != nil {
+			gapHandler(gap)
+		}
+	}
+
+	// expected is open time of the next candle which should be received
+	expected := d.interval.Truncate(time.UnixMilli(d.startTime))
+	if expected.UnixMilli() < d.startTime {
+		expected = d.interval.Next(expected)
+	}
+	for i, page := range pages {
+		launch(i + d.concurrency)
+
+		var res klinePageResult
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res = <-page.result:
+		}
+		if res.err != nil {
+			return res.err
+		}
+
+		for _, kline := range res.klines {
+			t := time.UnixMilli(kline.Time)
+			if kline.Time < expected.UnixMilli() {
+				continue
+			}
+			if kline.Time > expected.UnixMilli() {
+				reportGap(KlineGap{StartTime: expected.UnixMilli(), EndTime: d.interval.Prev(t).UnixMilli()})
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case klinesC <- kline:
+			}
+			expected = d.interval.Next(t)
+		}
+	}
+
+	// candles opened before the one containing endTime+1ms are closed by endTime
+	bound := d.interval.Truncate(time.UnixMilli(endTime + 1))
+	if expected.Before(bound) {
+		reportGap(KlineGap{StartTime: expected.UnixMilli(), EndTime: d.interval.Prev(bound).UnixMilli()})
+	}
+
+	return nil
+}
+
+func (d *KlineDownloader) fetch(ctx context.Context, limiter *rateLimiter, page *klinePage) klinePageResult {
+	err := limiter.wait(ctx)
+	if err != nil {
+		return klinePageResult{err: err}
+	}
+
+	klines, err := d.c.NewGetKlinesService().
+		Symbol(d.symbol).
+		Interval(d.interval).
+		StartTime(page.startTime).
+		EndTime(page.endTime).
+		Limit(d.pageSize).
+		Do(ctx)
+	if err != nil {
+		return klinePageResult{err: err}
+	}
+
+	res := make([]*Kline, 0, len(klines))
+	for _, kline := range klines {
+		if kline.Time >= page.startTime && kline.Time <= page.endTime {
+			res = append(res, kline)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time < res[j].Time
+	})
+
+	return klinePageResult{klines: res}
+}
+
+// Do Fetch the whole range into memory
+func (d *KlineDownloader) Do(ctx context.Context) (res *KlineDownload, err error) {
+	res = &KlineDownload{}
+	klinesC := make(chan *Kline)
+	errC := make(chan error, 1)
+
+	go func() {
+		defer close(klinesC)
+		errC <- d.stream(ctx, klinesC, func(gap KlineGap) {
+			res.Gaps = append(res.Gaps, gap)
+			if d.gapHandler != nil {
+				d.gapHandler(gap)
+			}
+		})
+	}()
+
+	for kline := range klinesC {
+		res.Klines = append(res.Klines, kline)
+	}
+
+	err = <-errC
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// rateLimiter spaces calls by fixed period
+type rateLimiter struct {
+	period time.Duration
+	mu     sync.Mutex
+	next   time.Time
+}
+
+func newRateLimiter(period time.Duration) *rateLimiter {
+	return &rateLimiter{period: period}
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.period <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.period)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
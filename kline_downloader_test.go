@@ -0,0 +1,157 @@
+package bingx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type klineDownloaderTestSuite struct {
+	baseTestSuite
+	mu       sync.Mutex
+	requests int
+}
+
+func TestKlineDownloader(t *testing.T) {
+	suite.Run(t, new(klineDownloaderTestSuite))
+}
+
+// mockKlines serves 1m candles in descending order with duplicates, skipping missing open times
+func (s *klineDownloaderTestSuite) mockKlines(missing ...int64) {
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		s.mu.Lock()
+		s.requests++
+		s.mu.Unlock()
+
+		query := req.URL.Query()
+		start, _ := strconv.ParseInt(query.Get("startTime"), 10, 64)
+		end, _ := strconv.ParseInt(query.Get("endTime"), 10, 64)
+
+		var klines []*Kline
+		for t := end - end%60000; t >= start; t -= 60000 {
+			skip := false
+			for _, m := range missing {
+				skip = skip || m == t
+			}
+			if !skip {
+				klines = append(klines, &Kline{Open: "1", Time: t}, &Kline{Open: "1", Time: t})
+			}
+		}
+
+		data, _ := json.Marshal(map[string]interface{}{"code": 0, "data": klines})
+		return newHTTPResponse(data, http.StatusOK), nil
+	}
+}
+
+func (s *klineDownloaderTestSuite) TestDo() {
+	s.mockKlines(4*60000, 5*60000)
+
+	var gaps []KlineGap
+	res, err := s.client.NewKlineDownloader().
+		Symbol("BTC-USDT").
+		Interval(Interval1).
+		StartTime(60000).
+		EndTime(10*60000 - 1).
+		PageSize(3).
+		Concurrency(3).
+		RateLimit(0).
+		GapHandler(func(gap KlineGap) {
+			gaps = append(gaps, gap)
+		}).
+		Do(newContext())
+
+	r := s.r()
+	r.NoError(err)
+	r.Equal(3, s.requests)
+	r.Len(res.Klines, 7)
+	for i := 1; i < len(res.Klines); i++ {
+		r.Less(res.Klines[i-1].Time, res.Klines[i].Time)
+	}
+	r.Equal([]KlineGap{{StartTime: 4 * 60000, EndTime: 5 * 60000}}, res.Gaps)
+	r.Equal(res.Gaps, gaps)
+}
+
+func (s *klineDownloaderTestSuite) TestStreamTrailingGap() {
+	s.mockKlines(7*60000, 8*60000, 9*60000)
+
+	klinesC, errC := s.client.NewKlineDownloader().
+		Symbol("BTC-USDT").
+		Interval(Interval1).
+		StartTime(60000).
+		EndTime(10*60000 - 1).
+		PageSize(4).
+		GapHandler(func(gap KlineGap) {
+			s.r().Equal(KlineGap{StartTime: 7 * 60000, EndTime: 9 * 60000}, gap)
+		}).
+		Stream(newContext())
+
+	count := 0
+	for range klinesC {
+		count++
+	}
+
+	r := s.r()
+	r.NoError(<-errC)
+	r.Equal(6, count)
+}
+
+func (s *klineDownloaderTestSuite) TestDoFailedPage() {
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("endTime") == "239999" {
+			return nil, errors.New("connection reset")
+		}
+		// other pages hang until download is cancelled
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+
+	errC := make(chan error, 1)
+	go func() {
+		_, err := s.client.NewKlineDownloader().
+			Symbol("BTC-USDT").
+			Interval(Interval1).
+			StartTime(60000).
+			EndTime(10*60000 - 1).
+			PageSize(3).
+			Concurrency(3).
+			RateLimit(0).
+			Do(newContext())
+		errC <- err
+	}()
+
+	select {
+	case err := <-errC:
+		s.r().Error(err)
+	case <-time.After(5 * time.Second):
+		s.r().Fail("download did not cancel pending fetches")
+	}
+}
+
+func (s *klineDownloaderTestSuite) TestValidate() {
+	r := s.r()
+	cases := []struct {
+		interval  Interval
+		startTime int64
+		endTime   int64
+		err       string
+	}{
+		{"7m", 60000, 120000, `bingx: unsupported kline interval "7m"`},
+		{Interval1, 0, 120000, "bingx: kline downloader start time is required"},
+		{Interval1, 180000, 120000, "bingx: kline downloader start time 180000 is after end time 120000"},
+	}
+	for _, c := range cases {
+		_, err := s.client.NewKlineDownloader().
+			Symbol("BTC-USDT").
+			Interval(c.interval).
+			StartTime(c.startTime).
+			EndTime(c.endTime).
+			Do(newContext())
+		r.EqualError(err, c.err, c.err)
+	}
+}
@@ -19,7 +19,7 @@ type GetKlinesService struct {
 type Kline struct {
 	Open   string `json:"open"`
 	Close  string `json:"close"`
-	High   string `json:"hign"`
+	High   string `json:"high"`
 	Low    string `json:"low"`
 	Volume string `json:"volume"`
 	Time   int64  `json:"time"`
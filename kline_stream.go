@@ -0,0 +1,325 @@
+package bingx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultKlineBackfillBackoff = time.Second
+	maxKlineBackfillBackoff     = time.Minute
+)
+
+// KlineStream Emits every kline of symbol exactly once when it is closed.
+// Kline is closed by the first update of the next one or by timer at interval boundary,
+// whichever comes first. Klines missed while connection was down are loaded with REST API,
+// failed loads are retried and klines closed meanwhile are held until the gap is filled.
+type KlineStream struct {
+	c               *Client
+	symbol          string
+	interval        Interval
+	closeDelay      time.Duration
+	backfill        bool
+	backfillBackoff time.Duration
+	now             func() time.Time
+
+	mu         sync.Mutex
+	handler    WsKlineHandler
+	errHandler ErrHandler
+	// current is the latest update of kline not closed yet
+	current *WsKlineEvent
+	// lastClosed open time of the last emitted kline
+	lastClosed float64
+	// closed klines are held in pending while backfills are running
+	backfilling int
+	pending     []*WsKlineEvent
+	// out klines and errors waiting for handlers, delivered by one goroutine at a time
+	out        []klineOutput
+	delivering bool
+}
+
+type klineOutput struct {
+	event *WsKlineEvent
+	err   error
+}
+
+func (c *Client) NewKlineStream(symbol string, interval Interval) *KlineStream {
+	return &KlineStream{
+		c:               c,
+		symbol:          symbol,
+		interval:        interval,
+		backfill:        true,
+		backfillBackoff: defaultKlineBackfillBackoff,
+		now:             time.Now,
+	}
+}
+
+// CloseDelay Wait after interval boundary before closing kline by timer, gives late trades time to arrive
+func (k *KlineStream) CloseDelay(delay time.Duration) *KlineStream {
+	k.closeDelay = delay
+	return k
+}
+
+// Backfill Load klines missed while connection was down, enabled by default
+func (k *KlineStream) Backfill(backfill bool) *KlineStream {
+	k.backfill = backfill
+	return k
+}
+
+// Serve Subscribe to klines, handler gets copies of closed klines in order of open time
+func (k *KlineStream) Serve(ctx context.Context, handler WsKlineHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	if !k.interval.Valid() {
+		return nil, fmt.Errorf("bingx: unsupported kline interval %q", k.interval)
+	}
+
+	k.handler = handler
+	k.errHandler = errHandler
+
+	config := newWsConfig("", opts...)
+	onDisconnected, onGap := config.OnDisconnected, config.OnGap
+	opts = append(opts,
+		WithWsOnDisconnected(func(err error) {
+			k.disconnected()
+			if onDisconnected != nil {
+				onDisconnected(err)
+			}
+		}),
+		WithWsGapHandler(func(gap WsGap) {
+			if k.backfill {
+				k.startBackfill()
+				go k.fill(ctx, gap)
+			}
+			if onGap != nil {
+				onGap(gap)
+			}
+		}),
+	)
+
+	dataType := klineDataType(k.symbol, k.interval)
+	stream, err := wsServeDataType(ctx, dataType, func(data []byte) {
+		events, _, err := parseWsKlines(data, dataType)
+		for _, event := range events {
+			k.update(event)
+		}
+		if err != nil {
+			errHandler(err)
+		}
+	}, errHandler, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	go k.tick(stream.Done())
+
+	return stream, nil
+}
+
+// tick closes current kline at every interval boundary
+func (k *KlineStream) tick(doneC <-chan struct{}) {
+	for {
+		now := k.now()
+		boundary := k.interval.Next(now)
+		timer := time.NewTimer(boundary.Add(k.closeDelay).Sub(now))
+
+		select {
+		case <-doneC:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		k.closeBefore(boundary)
+	}
+}
+
+func (k *KlineStream) update(event *WsKlineEvent) {
+	k.mu.Lock()
+	defer k.unlock()
+
+	if event.Time <= k.lastClosed {
+		return
+	}
+	if k.current != nil {
+		if event.Time < k.current.Time {
+			return
+		}
+		if event.Time > k.current.Time {
+			k.close(k.current)
+		}
+	}
+
+	current := *event
+	current.Symbol = k.symbol
+	current.Completed = false
+	k.current = &current
+}
+
+// closeBefore closes current kline if it ends not later than boundary
+func (k *KlineStream) closeBefore(boundary time.Time) {
+	k.mu.Lock()
+	defer k.unlock()
+
+	if k.current == nil {
+		return
+	}
+	if k.interval.Next(time.UnixMilli(int64(k.current.Time))).After(boundary) {
+		return
+	}
+	k.close(k.current)
+	k.current = nil
+}
+
+// disconnected drops current kline, its updates of the gap are lost so it is loaded by backfill
+func (k *KlineStream) disconnected() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.current = nil
+}
+
+func (k *KlineStream) close(event *WsKlineEvent) {
+	if k.backfilling > 0 {
+		k.pending = append(k.pending, event)
+		return
+	}
+	k.emit(event)
+}
+
+func (k *KlineStream) emit(event *WsKlineEvent) {
+	if event.Time <= k.lastClosed {
+		return
+	}
+	k.lastClosed = event.Time
+
+	closed := *event
+	closed.Completed = true
+	k.out = append(k.out, klineOutput{event: &closed})
+}
+
+// unlock releases mu and passes queued klines and errors to handlers outside of it. Goroutine which
+// is already delivering takes klines queued meanwhile, so handlers are called in order klines were closed.
+func (k *KlineStream) unlock() {
+	if k.delivering {
+		k.mu.Unlock()
+		return
+	}
+	k.delivering = true
+	for len(k.out) > 0 {
+		out := k.out
+		k.out = nil
+		k.mu.Unlock()
+
+		for _, o := range out {
+			if o.err != nil {
+				k.errHandler(o.err)
+			} else {
+				k.handler(o.event)
+			}
+		}
+
+		k.mu.Lock()
+	}
+	k.delivering = false
+	k.mu.Unlock()
+}
+
+func (k *KlineStream) startBackfill() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.backfilling++
+}
+
+// fill emits closed klines of the gap and klines closed while it was loading them.
+// Failed load is retried with backoff until it succeeds or ctx is done.
+func (k *KlineStream) fill(ctx context.Context, gap WsGap) {
+	backoff := k.backfillBackoff
+	for {
+		events, err := k.load(ctx, gap)
+		if err == nil {
+			k.mu.Lock()
+			for _, event := range events {
+				k.emit(event)
+			}
+			k.finishBackfill()
+			k.unlock()
+			return
+		}
+
+		k.mu.Lock()
+		k.out = append(k.out, klineOutput{err: fmt.Errorf("bingx: kline backfill of %s: %w", k.symbol, err)})
+		k.unlock()
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			// stream is closed, klines after the gap are dropped
+			k.mu.Lock()
+			k.backfilling--
+			k.pending = nil
+			k.mu.Unlock()
+			return
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxKlineBackfillBackoff {
+			backoff = maxKlineBackfillBackoff
+		}
+	}
+}
+
+// load downloads klines closed since the last emitted one or the start of the gap
+func (k *KlineStream) load(ctx context.Context, gap WsGap) ([]*WsKlineEvent, error) {
+	k.mu.Lock()
+	startTime := k.interval.Truncate(gap.DisconnectedAt).UnixMilli()
+	if k.lastClosed > 0 {
+		startTime = k.interval.Next(time.UnixMilli(int64(k.lastClosed))).UnixMilli()
+	}
+	k.mu.Unlock()
+
+	now := k.now()
+	res, err := k.c.NewKlineDownloader().
+		Symbol(k.symbol).
+		Interval(k.interval).
+		StartTime(startTime).
+		EndTime(now.UnixMilli()).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*WsKlineEvent
+	for _, kline := range res.Klines {
+		if k.interval.Next(time.UnixMilli(kline.Time)).After(now) {
+			break
+		}
+		values, err := parseKlineValues(kline)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &WsKlineEvent{
+			Symbol: k.symbol,
+			Open:   values[0],
+			High:   values[1],
+			Low:    values[2],
+			Close:  values[3],
+			Volume: values[4],
+			Time:   float64(kline.Time),
+		})
+	}
+	return events, nil
+}
+
+// finishBackfill emits klines held during backfills once the last of them completes
+func (k *KlineStream) finishBackfill() {
+	k.backfilling--
+	if k.backfilling > 0 {
+		return
+	}
+	for _, event := range k.pending {
+		k.emit(event)
+	}
+	k.pending = nil
+}
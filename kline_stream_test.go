@@ -0,0 +1,166 @@
+package bingx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func (s *websocketServiceTestSuite) newKlineStream(events *[]*WsKlineEvent) *KlineStream {
+	k := s.client.NewKlineStream("BTC-USDT", Interval1)
+	k.handler = func(event *WsKlineEvent) {
+		*events = append(*events, event)
+	}
+	k.errHandler = func(err error) {
+		s.r().NoError(err)
+	}
+	return k
+}
+
+func (s *websocketServiceTestSuite) TestKlineStreamServe() {
+	data := [][]byte{
+		[]byte(`{"code":0,"dataType":"BTC-USDT@kline_1m","data":[{"o":"1","h":"3","l":"1","c":"2","v":"5","T":60000}]}`),
+		[]byte(`{"code":0,"dataType":"BTC-USDT@kline_1m","data":[{"o":"1","h":"4","l":"1","c":"4","v":"6","T":60000}]}`),
+		[]byte(`{"code":0,"dataType":"BTC-USDT@kline_1m","data":[{"o":"4","h":"4","l":"4","c":"4","v":"1","T":120000}]}`),
+	}
+	s.mockWsServe(data, nil)
+	defer s.assertWsServe()
+
+	var events []*WsKlineEvent
+	stream, err := s.client.NewKlineStream("BTC-USDT", Interval1).Serve(newContext(), func(event *WsKlineEvent) {
+		events = append(events, event)
+	}, func(err error) {
+		s.r().NoError(err)
+	})
+	r := s.r()
+	r.NoError(err)
+	r.NoError(stream.Close())
+
+	r.Equal([]*WsKlineEvent{{
+		Symbol: "BTC-USDT", Open: 1, High: 4, Low: 1, Close: 4, Volume: 6, Time: 60000, Completed: true,
+	}}, events)
+
+	_, err = s.client.NewKlineStream("BTC-USDT", "7m").Serve(newContext(), nil, nil)
+	r.EqualError(err, `bingx: unsupported kline interval "7m"`)
+}
+
+func (s *websocketServiceTestSuite) TestKlineStreamCloseOnTimer() {
+	var events []*WsKlineEvent
+	k := s.newKlineStream(&events)
+
+	k.update(&WsKlineEvent{Close: 1, Time: 60000})
+	k.closeBefore(time.UnixMilli(60000))
+	k.closeBefore(time.UnixMilli(120000))
+	k.closeBefore(time.UnixMilli(180000))
+	// late update of closed kline
+	k.update(&WsKlineEvent{Close: 2, Time: 60000})
+	k.update(&WsKlineEvent{Close: 3, Time: 120000})
+	k.update(&WsKlineEvent{Close: 4, Time: 180000})
+
+	r := s.r()
+	r.Len(events, 2)
+	r.Equal(WsKlineEvent{Symbol: "BTC-USDT", Close: 1, Time: 60000, Completed: true}, *events[0])
+	r.Equal(WsKlineEvent{Symbol: "BTC-USDT", Close: 3, Time: 120000, Completed: true}, *events[1])
+	r.Equal(float64(180000), k.current.Time)
+	r.False(k.current.Completed)
+}
+
+func (s *websocketServiceTestSuite) TestKlineStreamBackfill() {
+	var startTime string
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		startTime = req.URL.Query().Get("startTime")
+		var klines []*Kline
+		for t := int64(300000); t >= 120000; t -= 60000 {
+			klines = append(klines, &Kline{Open: "1", High: "1", Low: "1", Close: "2", Volume: "1", Time: t})
+		}
+		data, _ := json.Marshal(map[string]interface{}{"code": 0, "data": klines})
+		return newHTTPResponse(data, http.StatusOK), nil
+	}
+
+	var events []*WsKlineEvent
+	k := s.newKlineStream(&events)
+	k.now = func() time.Time {
+		return time.UnixMilli(370000)
+	}
+
+	k.update(&WsKlineEvent{Close: 1, Time: 60000})
+	k.update(&WsKlineEvent{Close: 1, Time: 120000})
+	k.disconnected()
+	k.startBackfill()
+	// messages after reconnect arrive before backfill completes
+	k.update(&WsKlineEvent{Close: 3, Time: 300000})
+	k.update(&WsKlineEvent{Close: 3, Time: 360000})
+	k.fill(newContext(), WsGap{DisconnectedAt: time.UnixMilli(130000), ReconnectedAt: time.UnixMilli(310000)})
+
+	r := s.r()
+	r.Equal("120000", startTime)
+	var times []float64
+	for _, event := range events {
+		r.True(event.Completed)
+		times = append(times, event.Time)
+	}
+	r.Equal([]float64{60000, 120000, 180000, 240000, 300000}, times)
+	r.Equal(float64(2), events[4].Close)
+	r.Empty(k.pending)
+}
+
+func (s *websocketServiceTestSuite) TestKlineStreamBackfillRetry() {
+	var requests int
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		requests++
+		if requests == 1 {
+			return nil, errors.New("connection reset")
+		}
+		klines := []*Kline{{Open: "1", High: "1", Low: "1", Close: "2", Volume: "1", Time: 120000}}
+		data, _ := json.Marshal(map[string]interface{}{"code": 0, "data": klines})
+		return newHTTPResponse(data, http.StatusOK), nil
+	}
+
+	var log []string
+	k := s.client.NewKlineStream("BTC-USDT", Interval1)
+	k.backfillBackoff = time.Millisecond
+	k.now = func() time.Time {
+		return time.UnixMilli(250000)
+	}
+	k.handler = func(event *WsKlineEvent) {
+		log = append(log, fmt.Sprint(event.Time))
+	}
+	k.errHandler = func(err error) {
+		log = append(log, "error")
+	}
+
+	k.update(&WsKlineEvent{Close: 1, Time: 60000})
+	k.disconnected()
+	k.startBackfill()
+	k.update(&WsKlineEvent{Close: 3, Time: 180000})
+	k.update(&WsKlineEvent{Close: 3, Time: 240000})
+	k.fill(newContext(), WsGap{DisconnectedAt: time.UnixMilli(100000), ReconnectedAt: time.UnixMilli(190000)})
+
+	// kline closed during the gap is not emitted before the gap is filled
+	r := s.r()
+	r.Equal(2, requests)
+	r.Equal([]string{"error", "120000", "180000"}, log)
+}
+
+func (s *websocketServiceTestSuite) TestKlineStreamReentrantHandler() {
+	var events []*WsKlineEvent
+	k := s.newKlineStream(&events)
+	handler := k.handler
+	k.handler = func(event *WsKlineEvent) {
+		handler(event)
+		// handler may feed the stream, its klines are delivered after it returns
+		if event.Time == 60000 {
+			k.update(&WsKlineEvent{Close: 3, Time: 180000})
+		}
+	}
+
+	k.update(&WsKlineEvent{Close: 1, Time: 60000})
+	k.update(&WsKlineEvent{Close: 2, Time: 120000})
+
+	r := s.r()
+	r.Len(events, 2)
+	r.Equal(float64(60000), events[0].Time)
+	r.Equal(float64(120000), events[1].Time)
+}
@@ -0,0 +1,366 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type marketSubscription struct {
+	handler WsHandler
+	// active is set once server acknowledged subscription on current connection
+	active bool
+}
+
+type pendingRequest struct {
+	reqType  WsRequestType
+	dataType string
+	// resultC is nil for requests nobody waits for, e.g. replayed subscriptions
+	resultC chan error
+}
+
+// MarketStream Multiplexes many market subscriptions over one connection.
+// Subscriptions may be added and removed at any time and are replayed after reconnect.
+type MarketStream struct {
+	config     *WsConfig
+	errHandler ErrHandler
+	conn       *wsConn
+
+	mu            sync.RWMutex
+	subscriptions map[string]*marketSubscription
+	pending       map[string]*pendingRequest
+	doneC         chan struct{}
+	started       bool
+	// replay serves recorded messages when WithWsReplay is set
+	replay *Stream
+}
+
+func NewMarketStream(errHandler ErrHandler, opts ...WsOption) *MarketStream {
+	s := &MarketStream{
+		config:        newWsConfig(getWsEndpoint(), opts...),
+		errHandler:    errHandler,
+		subscriptions: map[string]*marketSubscription{},
+		pending:       map[string]*pendingRequest{},
+		doneC:         make(chan struct{}),
+	}
+	s.conn = newWsConn(s.config, s.handle, errHandler, s.subscribeMessages)
+	return s
+}
+
+// Start Dial connection and subscribe to everything added before.
+// Rejected subscriptions are removed and reported to error handler.
+func (s *MarketStream) Start() error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return fmt.Errorf("bingx: market stream is already started")
+	}
+	s.started = true
+	if s.config.Replay != nil {
+		s.replay = s.config.Replay.serve(context.Background(), s.config, s.handle)
+		s.mu.Unlock()
+		go func() {
+			<-s.replay.Done()
+			close(s.doneC)
+		}()
+		return nil
+	}
+	s.mu.Unlock()
+
+	err := s.conn.dial()
+	if err != nil {
+		close(s.doneC)
+		return err
+	}
+
+	go s.conn.run(s.doneC)
+
+	return nil
+}
+
+// Stop Close connection, safe to call many times
+func (s *MarketStream) Stop() {
+	s.mu.RLock()
+	replay := s.replay
+	s.mu.RUnlock()
+	if replay != nil {
+		replay.close(nil)
+		return
+	}
+	s.conn.stop()
+}
+
+// Done Closed when stream is stopped or connection is lost for good
+func (s *MarketStream) Done() <-chan struct{} {
+	return s.doneC
+}
+
+// Subscribe Route messages of dataType e.g. "BTC-USDT@kline_1m" to handler.
+// Once started it blocks until server acknowledges subscription and returns
+// *WsRequestError if it is rejected or ErrWsRequestTimeout if there is no reply.
+// It must not be called from a handler of the same stream.
+func (s *MarketStream) Subscribe(dataType string, handler WsHandler) error {
+	sub := &marketSubscription{handler: handler}
+
+	s.mu.Lock()
+	existing := s.subscriptions[dataType]
+	if existing != nil {
+		sub.active = existing.active
+	}
+	s.subscriptions[dataType] = sub
+	started, active := s.started, sub.active
+	s.mu.Unlock()
+
+	if !started || active {
+		return nil
+	}
+
+	err := s.request(SubscribeRequestType, dataType)
+	if err != nil {
+		s.mu.Lock()
+		if s.subscriptions[dataType] == sub {
+			delete(s.subscriptions, dataType)
+		}
+		s.mu.Unlock()
+	}
+
+	return err
+}
+
+// Unsubscribe Stop receiving messages of dataType, blocks until server acknowledges it
+func (s *MarketStream) Unsubscribe(dataType string) error {
+	s.mu.Lock()
+	sub := s.subscriptions[dataType]
+	delete(s.subscriptions, dataType)
+	started := s.started
+	s.mu.Unlock()
+
+	if !started || sub == nil {
+		return nil
+	}
+
+	return s.request(UnubscribeRequestType, dataType)
+}
+
+// SubscribeKline Subscribe to kline events of symbol
+func (s *MarketStream) SubscribeKline(symbol string, interval Interval, handler WsKlineHandler) error {
+	dataType := klineDataType(symbol, interval)
+	return s.Subscribe(dataType, newWsKlineHandler(dataType, handler, s.reportErr))
+}
+
+// SubscribeDepth Subscribe to depth events of symbol
+func (s *MarketStream) SubscribeDepth(symbol string, level int, handler WsDepthHandler) error {
+	dataType := depthDataType(symbol, level)
+	return s.Subscribe(dataType, newWsDepthHandler(symbol, dataType, handler, s.reportErr))
+}
+
+// SubscribeTrade Subscribe to public trades of symbol
+func (s *MarketStream) SubscribeTrade(symbol string, handler WsTradeHandler) error {
+	dataType := tradeDataType(symbol)
+	return s.Subscribe(dataType, newWsTradeHandler(dataType, handler, s.reportErr))
+}
+
+// SubscribeTicker Subscribe to 24h ticker of symbol
+func (s *MarketStream) SubscribeTicker(symbol string, handler WsTickerHandler) error {
+	dataType := tickerDataType(symbol)
+	return s.Subscribe(dataType, newWsTickerHandler(dataType, handler, s.reportErr))
+}
+
+// SubscribeMarkPrice Subscribe to mark price of symbol
+func (s *MarketStream) SubscribeMarkPrice(symbol string, handler WsMarkPriceHandler) error {
+	dataType := markPriceDataType(symbol)
+	return s.Subscribe(dataType, newWsMarkPriceHandler(dataType, handler, s.reportErr))
+}
+
+// SubscribeBookTicker Subscribe to best bid and ask of symbol
+func (s *MarketStream) SubscribeBookTicker(symbol string, handler WsBookTickerHandler) error {
+	dataType := bookTickerDataType(symbol)
+	return s.Subscribe(dataType, newWsBookTickerHandler(dataType, handler, s.reportErr))
+}
+
+// SubscribeLastPrice Subscribe to last traded price of symbol
+func (s *MarketStream) SubscribeLastPrice(symbol string, handler WsLastPriceHandler) error {
+	dataType := lastPriceDataType(symbol)
+	return s.Subscribe(dataType, newWsLastPriceHandler(dataType, handler, s.reportErr))
+}
+
+// Subscriptions Data types acknowledged by server on current connection
+func (s *MarketStream) Subscriptions() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]string, 0, len(s.subscriptions))
+	for dataType, sub := range s.subscriptions {
+		if sub.active {
+			res = append(res, dataType)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+func (s *MarketStream) newRequest(reqType WsRequestType, dataType string, resultC chan error) ([]byte, string, error) {
+	id := uuid.New()
+	msg, err := json.Marshal(RequestEvent{
+		Id:       id,
+		ReqType:  reqType,
+		DataType: dataType,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.pending[id.String()] = &pendingRequest{
+		reqType:  reqType,
+		dataType: dataType,
+		resultC:  resultC,
+	}
+
+	return msg, id.String(), nil
+}
+
+// request sends request and waits for reply
+func (s *MarketStream) request(reqType WsRequestType, dataType string) error {
+	resultC := make(chan error, 1)
+
+	s.mu.Lock()
+	msg, id, err := s.newRequest(reqType, dataType, resultC)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = s.conn.send(msg)
+	if err != nil {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+
+		if err == errWsNotConnected {
+			// subscriptions are replayed once connected
+			return nil
+		}
+		return err
+	}
+
+	timer := time.NewTimer(s.config.AckTimeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-resultC:
+		return err
+	case <-timer.C:
+	case <-s.doneC:
+	}
+
+	s.mu.Lock()
+	delete(s.pending, id)
+	s.mu.Unlock()
+
+	return fmt.Errorf("%w: %s %s", ErrWsRequestTimeout, reqType, dataType)
+}
+
+// subscribeMessages is called after every dial, subscriptions become active once acknowledged again
+func (s *MarketStream) subscribeMessages() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, req := range s.pending {
+		if req.resultC == nil {
+			delete(s.pending, id)
+		}
+	}
+
+	dataTypes := make([]string, 0, len(s.subscriptions))
+	for dataType, sub := range s.subscriptions {
+		sub.active = false
+		dataTypes = append(dataTypes, dataType)
+	}
+	sort.Strings(dataTypes)
+
+	var messages [][]byte
+	for _, dataType := range dataTypes {
+		msg, _, err := s.newRequest(SubscribeRequestType, dataType, nil)
+		if err != nil {
+			s.reportErr(err)
+			continue
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func (s *MarketStream) reportErr(err error) {
+	if s.errHandler != nil {
+		s.errHandler(err)
+	}
+}
+
+func (s *MarketStream) handle(data []byte) {
+	if res, ok := parseResponseEvent(data); ok {
+		s.handleResponse(res)
+		return
+	}
+
+	ev := new(struct {
+		DataType string `json:"dataType"`
+	})
+	err := json.Unmarshal(data, ev)
+	if err != nil {
+		s.reportErr(err)
+		return
+	}
+
+	s.mu.RLock()
+	sub := s.subscriptions[ev.DataType]
+	s.mu.RUnlock()
+
+	if sub != nil {
+		sub.handler(data)
+	}
+}
+
+func (s *MarketStream) handleResponse(res *ResponseEvent) {
+	s.mu.Lock()
+	req := s.pending[res.Id]
+	delete(s.pending, res.Id)
+
+	var err error
+	if req != nil {
+		if res.Code != 0 {
+			err = &WsRequestError{
+				Id:       res.Id,
+				ReqType:  req.reqType,
+				DataType: req.dataType,
+				Code:     res.Code,
+				Msg:      res.Msg,
+			}
+		}
+
+		sub := s.subscriptions[req.dataType]
+		if req.reqType == SubscribeRequestType && sub != nil {
+			if err == nil {
+				sub.active = true
+			} else if req.resultC == nil {
+				delete(s.subscriptions, req.dataType)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if req == nil {
+		return
+	}
+
+	if req.resultC != nil {
+		req.resultC <- err
+	} else if err != nil {
+		s.reportErr(err)
+	}
+}
@@ -0,0 +1,157 @@
+package bingx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+type marketStreamTestSuite struct {
+	wsServerTestSuite
+}
+
+func TestMarketStream(t *testing.T) {
+	suite.Run(t, new(marketStreamTestSuite))
+}
+
+func (s *marketStreamTestSuite) readRequest(conn *websocket.Conn) RequestEvent {
+	var req RequestEvent
+	s.Require().NoError(json.Unmarshal([]byte(s.readText(conn)), &req))
+	return req
+}
+
+func (s *marketStreamTestSuite) reply(conn *websocket.Conn, req RequestEvent, code int, msg string) {
+	data := fmt.Sprintf(`{"id":"%s","code":%d,"msg":"%s","dataType":"","data":null}`, req.Id, code, msg)
+	s.Require().NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage(data)))
+}
+
+// ack reads next request and acknowledges it
+func (s *marketStreamTestSuite) ack(conn *websocket.Conn, reqType WsRequestType, dataType string) {
+	req := s.readRequest(conn)
+	s.Require().Equal(reqType, req.ReqType)
+	s.Require().Equal(dataType, req.DataType)
+	s.reply(conn, req, 0, "")
+}
+
+func (s *marketStreamTestSuite) async(f func() error) chan error {
+	errC := make(chan error, 1)
+	go func() {
+		errC <- f()
+	}()
+	return errC
+}
+
+func (s *marketStreamTestSuite) TestRouting() {
+	r := s.Require()
+	stream := NewMarketStream(func(err error) {}, WithWsEndpoint(s.endpoint()), WithWsBackoff(time.Millisecond, time.Millisecond))
+
+	klineC := make(chan *WsKlineEvent, 10)
+	rawC := make(chan string, 10)
+	r.NoError(stream.SubscribeKline("BTC-USDT", Interval1, func(event *WsKlineEvent) {
+		klineC <- event
+	}))
+	r.Empty(stream.Subscriptions())
+	r.NoError(stream.Start())
+	defer stream.Stop()
+
+	conn := s.accept()
+	s.ack(conn, SubscribeRequestType, "BTC-USDT@kline_1m")
+
+	errC := s.async(func() error {
+		return stream.Subscribe("ETH-USDT@trade", func(data []byte) {
+			rawC <- string(data)
+		})
+	})
+	s.ack(conn, SubscribeRequestType, "ETH-USDT@trade")
+	r.NoError(<-errC)
+	r.Equal([]string{"BTC-USDT@kline_1m", "ETH-USDT@trade"}, stream.Subscriptions())
+
+	trade := `{"code":0,"dataType":"ETH-USDT@trade","data":[]}`
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage(`{"code":0,"dataType":"XRP-USDT@trade","data":[]}`)))
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage(trade)))
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage(`{"code":0,"dataType":"BTC-USDT@kline_1m","s":"BTC-USDT","data":[{"c":"2","o":"1","h":"3","l":"0.5","v":"10","T":1700000000000}]}`)))
+	r.Equal(trade, <-rawC)
+	event := <-klineC
+	r.Equal("BTC-USDT", event.Symbol)
+	r.Equal(3.0, event.High)
+
+	errC = s.async(func() error {
+		return stream.Unsubscribe("ETH-USDT@trade")
+	})
+	s.ack(conn, UnubscribeRequestType, "ETH-USDT@trade")
+	r.NoError(<-errC)
+	r.Equal([]string{"BTC-USDT@kline_1m"}, stream.Subscriptions())
+
+	conn.Close()
+	conn = s.accept()
+	req := s.readRequest(conn)
+	r.Equal(SubscribeRequestType, req.ReqType)
+	r.Equal("BTC-USDT@kline_1m", req.DataType)
+	r.Empty(stream.Subscriptions())
+	s.reply(conn, req, 0, "")
+
+	s.Eventually(func() bool {
+		return len(stream.Subscriptions()) == 1
+	}, time.Second, time.Millisecond)
+
+	stream.Stop()
+	<-stream.Done()
+}
+
+func (s *marketStreamTestSuite) TestRejectedSubscription() {
+	r := s.Require()
+	stream := NewMarketStream(func(err error) {}, WithWsEndpoint(s.endpoint()))
+	r.NoError(stream.Start())
+	defer stream.Stop()
+	conn := s.accept()
+
+	errC := s.async(func() error {
+		return stream.Subscribe("FOO-USDT@trade", func(data []byte) {})
+	})
+	req := s.readRequest(conn)
+	s.reply(conn, req, 80015, "dataType not support")
+
+	err := <-errC
+	var reqErr *WsRequestError
+	r.True(errors.As(err, &reqErr))
+	r.Equal(80015, reqErr.Code)
+	r.Equal("FOO-USDT@trade", reqErr.DataType)
+	r.Equal(SubscribeRequestType, reqErr.ReqType)
+	r.Empty(stream.Subscriptions())
+}
+
+func (s *marketStreamTestSuite) TestRequestTimeout() {
+	r := s.Require()
+	stream := NewMarketStream(func(err error) {}, WithWsEndpoint(s.endpoint()), WithWsAckTimeout(20*time.Millisecond))
+	r.NoError(stream.Start())
+	defer stream.Stop()
+	s.accept()
+
+	err := stream.Subscribe("BTC-USDT@trade", func(data []byte) {})
+	r.True(errors.Is(err, ErrWsRequestTimeout))
+	r.Empty(stream.Subscriptions())
+}
+
+func (s *marketStreamTestSuite) TestRejectedReplay() {
+	r := s.Require()
+	errC := make(chan error, 1)
+	stream := NewMarketStream(func(err error) {
+		errC <- err
+	}, WithWsEndpoint(s.endpoint()))
+	r.NoError(stream.Subscribe("FOO-USDT@trade", func(data []byte) {}))
+	r.NoError(stream.Start())
+	defer stream.Stop()
+
+	conn := s.accept()
+	req := s.readRequest(conn)
+	s.reply(conn, req, 80015, "dataType not support")
+
+	var reqErr *WsRequestError
+	r.True(errors.As(<-errC, &reqErr))
+	r.Equal("FOO-USDT@trade", reqErr.DataType)
+}
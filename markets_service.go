@@ -1,8 +1,11 @@
 package bingx
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
 )
 
@@ -50,3 +53,491 @@ func (s *GetSymbolDataService) Do(ctx context.Context, opts ...RequestOption) (r
 
 	return res, nil
 }
+
+type GetContractsService struct {
+	c      *Client
+	symbol string
+}
+
+// Symbol All contracts are returned when symbol is empty
+func (s *GetContractsService) Symbol(symbol string) *GetContractsService {
+	s.symbol = symbol
+	return s
+}
+
+// Contract Define perpetual contract metadata
+type Contract struct {
+	ContractId        string  `json:"contractId"`
+	Symbol            string  `json:"symbol"`
+	Asset             string  `json:"asset"`
+	Currency          string  `json:"currency"`
+	Size              string  `json:"size"`
+	QuantityPrecision int     `json:"quantityPrecision"`
+	PricePrecision    int     `json:"pricePrecision"`
+	FeeRate           float64 `json:"feeRate"`
+	MakerFeeRate      float64 `json:"makerFeeRate"`
+	TakerFeeRate      float64 `json:"takerFeeRate"`
+	TradeMinLimit     float64 `json:"tradeMinLimit"`
+	TradeMinQuantity  float64 `json:"tradeMinQuantity"`
+	TradeMinUSDT      float64 `json:"tradeMinUSDT"`
+	MaxLongLeverage   int     `json:"maxLongLeverage"`
+	MaxShortLeverage  int     `json:"maxShortLeverage"`
+	Status            int     `json:"status"`
+	ApiStateOpen      string  `json:"apiStateOpen"`
+	ApiStateClose     string  `json:"apiStateClose"`
+	EnsureTrigger     bool    `json:"ensureTrigger"`
+	TriggerFeeRate    string  `json:"triggerFeeRate"`
+	BrokerState       bool    `json:"brokerState"`
+	LaunchTime        int64   `json:"launchTime"`
+	MaintainTime      int64   `json:"maintainTime"`
+	OffTime           int64   `json:"offTime"`
+}
+
+// TradingEnabled Contract is online and accepts new orders through API
+func (c *Contract) TradingEnabled() bool {
+	return c.Status == 1 && c.ApiStateOpen == "true"
+}
+
+// PriceStep Minimal price increment
+func (c *Contract) PriceStep() float64 {
+	return math.Pow10(-c.PricePrecision)
+}
+
+// QuantityStep Minimal quantity increment
+func (c *Contract) QuantityStep() float64 {
+	return math.Pow10(-c.QuantityPrecision)
+}
+
+func (s *GetContractsService) Do(ctx context.Context, opts ...RequestOption) (res []*Contract, err error) {
+	r := &request{method: http.MethodGet, endpoint: "/openApi/swap/v2/quote/contracts"}
+
+	if s.symbol != "" {
+		r.addParam("symbol", s.symbol)
+	}
+
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(struct {
+		Code int         `json:"code"`
+		Msg  string      `json:"msg"`
+		Data []*Contract `json:"data"`
+	})
+
+	err = json.Unmarshal(data, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res = resp.Data
+
+	return res, nil
+}
+
+// PriceLevel Define a single order book level
+type PriceLevel struct {
+	Price    string
+	Quantity string
+}
+
+func (p *PriceLevel) UnmarshalJSON(data []byte) error {
+	var level []string
+	err := json.Unmarshal(data, &level)
+	if err != nil {
+		return err
+	}
+
+	if len(level) < 2 {
+		return fmt.Errorf("bingx: invalid price level %s", data)
+	}
+
+	p.Price = level[0]
+	p.Quantity = level[1]
+
+	return nil
+}
+
+func (p PriceLevel) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string{p.Price, p.Quantity})
+}
+
+type GetDepthService struct {
+	c      *Client
+	symbol string
+	limit  int
+}
+
+func (s *GetDepthService) Symbol(symbol string) *GetDepthService {
+	s.symbol = symbol
+	return s
+}
+
+// Limit Number of levels, one of 5, 10, 20, 50, 100, 500, 1000
+func (s *GetDepthService) Limit(limit int) *GetDepthService {
+	s.limit = limit
+	return s
+}
+
+// Depth Define order book snapshot
+type Depth struct {
+	Time int64        `json:"T"`
+	Bids []PriceLevel `json:"bids"`
+	Asks []PriceLevel `json:"asks"`
+}
+
+func (s *GetDepthService) Do(ctx context.Context, opts ...RequestOption) (res *Depth, err error) {
+	r := &request{method: http.MethodGet, endpoint: "/openApi/swap/v2/quote/depth"}
+
+	if s.symbol != "" {
+		r.addParam("symbol", s.symbol)
+	}
+
+	if s.limit != 0 {
+		r.addParam("limit", s.limit)
+	}
+
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+		Data *Depth `json:"data"`
+	})
+
+	err = json.Unmarshal(data, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res = resp.Data
+
+	return res, nil
+}
+
+type GetTradesService struct {
+	c      *Client
+	symbol string
+	limit  int
+}
+
+func (s *GetTradesService) Symbol(symbol string) *GetTradesService {
+	s.symbol = symbol
+	return s
+}
+
+// Limit Number of trades, max 1000
+func (s *GetTradesService) Limit(limit int) *GetTradesService {
+	s.limit = limit
+	return s
+}
+
+// Trade Define public trade
+type Trade struct {
+	Time         int64  `json:"time"`
+	IsBuyerMaker bool   `json:"isBuyerMaker"`
+	Price        string `json:"price"`
+	Quantity     string `json:"qty"`
+	QuoteQty     string `json:"quoteQty"`
+}
+
+func (s *GetTradesService) Do(ctx context.Context, opts ...RequestOption) (res []*Trade, err error) {
+	r := &request{method: http.MethodGet, endpoint: "/openApi/swap/v2/quote/trades"}
+
+	if s.symbol != "" {
+		r.addParam("symbol", s.symbol)
+	}
+
+	if s.limit != 0 {
+		r.addParam("limit", s.limit)
+	}
+
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(struct {
+		Code int      `json:"code"`
+		Msg  string   `json:"msg"`
+		Data []*Trade `json:"data"`
+	})
+
+	err = json.Unmarshal(data, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res = resp.Data
+
+	return res, nil
+}
+
+type GetTickerService struct {
+	c      *Client
+	symbol string
+}
+
+// Symbol Tickers of all contracts are returned when symbol is empty
+func (s *GetTickerService) Symbol(symbol string) *GetTickerService {
+	s.symbol = symbol
+	return s
+}
+
+// Ticker Define 24h price change statistics
+type Ticker struct {
+	Symbol             string `json:"symbol"`
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	LastPrice          string `json:"lastPrice"`
+	LastQty            string `json:"lastQty"`
+	HighPrice          string `json:"highPrice"`
+	LowPrice           string `json:"lowPrice"`
+	Volume             string `json:"volume"`
+	QuoteVolume        string `json:"quoteVolume"`
+	OpenPrice          string `json:"openPrice"`
+	OpenTime           int64  `json:"openTime"`
+	CloseTime          int64  `json:"closeTime"`
+	BidPrice           string `json:"bidPrice"`
+	BidQty             string `json:"bidQty"`
+	AskPrice           string `json:"askPrice"`
+	AskQty             string `json:"askQty"`
+}
+
+func (s *GetTickerService) Do(ctx context.Context, opts ...RequestOption) (res []*Ticker, err error) {
+	r := &request{method: http.MethodGet, endpoint: "/openApi/swap/v2/quote/ticker"}
+
+	if s.symbol != "" {
+		r.addParam("symbol", s.symbol)
+	}
+
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	})
+
+	err = json.Unmarshal(data, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = unmarshalOneOrMany(resp.Data, &res)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+type GetPremiumIndexService struct {
+	c      *Client
+	symbol string
+}
+
+// Symbol Premium index of all contracts is returned when symbol is empty
+func (s *GetPremiumIndexService) Symbol(symbol string) *GetPremiumIndexService {
+	s.symbol = symbol
+	return s
+}
+
+// PremiumIndex Define mark price, index price and funding rate of contract
+type PremiumIndex struct {
+	Symbol          string `json:"symbol"`
+	MarkPrice       string `json:"markPrice"`
+	IndexPrice      string `json:"indexPrice"`
+	LastFundingRate string `json:"lastFundingRate"`
+	NextFundingTime int64  `json:"nextFundingTime"`
+}
+
+func (s *GetPremiumIndexService) Do(ctx context.Context, opts ...RequestOption) (res []*PremiumIndex, err error) {
+	r := &request{method: http.MethodGet, endpoint: "/openApi/swap/v2/quote/premiumIndex"}
+
+	if s.symbol != "" {
+		r.addParam("symbol", s.symbol)
+	}
+
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(struct {
+		Code int             `json:"code"`
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	})
+
+	err = json.Unmarshal(data, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = unmarshalOneOrMany(resp.Data, &res)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+type GetFundingRateService struct {
+	c         *Client
+	symbol    string
+	startTime int64
+	endTime   int64
+	limit     int
+}
+
+func (s *GetFundingRateService) Symbol(symbol string) *GetFundingRateService {
+	s.symbol = symbol
+	return s
+}
+
+func (s *GetFundingRateService) StartTime(startTime int64) *GetFundingRateService {
+	s.startTime = startTime
+	return s
+}
+
+func (s *GetFundingRateService) EndTime(endTime int64) *GetFundingRateService {
+	s.endTime = endTime
+	return s
+}
+
+// Limit Number of records, max 1000
+func (s *GetFundingRateService) Limit(limit int) *GetFundingRateService {
+	s.limit = limit
+	return s
+}
+
+// FundingRate Define historical funding rate
+type FundingRate struct {
+	Symbol      string `json:"symbol"`
+	FundingRate string `json:"fundingRate"`
+	FundingTime int64  `json:"fundingTime"`
+}
+
+func (s *GetFundingRateService) Do(ctx context.Context, opts ...RequestOption) (res []*FundingRate, err error) {
+	r := &request{method: http.MethodGet, endpoint: "/openApi/swap/v2/quote/fundingRate"}
+
+	if s.symbol != "" {
+		r.addParam("symbol", s.symbol)
+	}
+
+	if s.startTime != 0 {
+		r.addParam("startTime", s.startTime)
+	}
+
+	if s.endTime != 0 {
+		r.addParam("endTime", s.endTime)
+	}
+
+	if s.limit != 0 {
+		r.addParam("limit", s.limit)
+	}
+
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(struct {
+		Code int            `json:"code"`
+		Msg  string         `json:"msg"`
+		Data []*FundingRate `json:"data"`
+	})
+
+	err = json.Unmarshal(data, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res = resp.Data
+
+	return res, nil
+}
+
+type GetOpenInterestService struct {
+	c      *Client
+	symbol string
+}
+
+func (s *GetOpenInterestService) Symbol(symbol string) *GetOpenInterestService {
+	s.symbol = symbol
+	return s
+}
+
+// OpenInterest Define open interest of contract
+type OpenInterest struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"`
+	Time         int64  `json:"time"`
+}
+
+func (s *GetOpenInterestService) Do(ctx context.Context, opts ...RequestOption) (res *OpenInterest, err error) {
+	r := &request{method: http.MethodGet, endpoint: "/openApi/swap/v2/quote/openInterest"}
+
+	if s.symbol != "" {
+		r.addParam("symbol", s.symbol)
+	}
+
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(struct {
+		Code int           `json:"code"`
+		Msg  string        `json:"msg"`
+		Data *OpenInterest `json:"data"`
+	})
+
+	err = json.Unmarshal(data, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	res = resp.Data
+
+	return res, nil
+}
+
+// unmarshalOneOrMany decodes data which is an object for a single symbol and an array otherwise
+func unmarshalOneOrMany[T any](data json.RawMessage, res *[]*T) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if data[0] == '[' {
+		return json.Unmarshal(data, res)
+	}
+
+	item := new(T)
+	err := json.Unmarshal(data, item)
+	if err != nil {
+		return err
+	}
+
+	*res = []*T{item}
+
+	return nil
+}
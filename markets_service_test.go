@@ -0,0 +1,281 @@
+package bingx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type marketsServiceTestSuite struct {
+	baseTestSuite
+}
+
+func TestMarketsService(t *testing.T) {
+	suite.Run(t, new(marketsServiceTestSuite))
+}
+
+func (s *marketsServiceTestSuite) TestGetDepth() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": {
+			"T": 1702718831034,
+			"bids": [["43062.3", "10.2900"], ["43062.2", "0.0100"]],
+			"asks": [["43062.6", "4.1200"]]
+		}
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setParams(params{
+			"symbol":     "BTC-USDT",
+			"limit":      5,
+			"recvWindow": 10000,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewGetDepthService().Symbol("BTC-USDT").Limit(5).Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal(int64(1702718831034), res.Time)
+	r.Equal([]PriceLevel{{Price: "43062.3", Quantity: "10.2900"}, {Price: "43062.2", Quantity: "0.0100"}}, res.Bids)
+	r.Equal([]PriceLevel{{Price: "43062.6", Quantity: "4.1200"}}, res.Asks)
+}
+
+func (s *marketsServiceTestSuite) TestGetTrades() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": [
+			{"time": 1702719166000, "isBuyerMaker": true, "price": "43044.5", "qty": "0.0100", "quoteQty": "430.44"}
+		]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	res, err := s.client.NewGetTradesService().Symbol("BTC-USDT").Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Len(res, 1)
+	r.Equal(&Trade{
+		Time:         1702719166000,
+		IsBuyerMaker: true,
+		Price:        "43044.5",
+		Quantity:     "0.0100",
+		QuoteQty:     "430.44",
+	}, res[0])
+}
+
+func (s *marketsServiceTestSuite) TestGetKlines() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": [
+			{"open": "43190.0", "close": "43206.4", "high": "43215.2", "low": "43180.5", "volume": "120.3400", "time": 1702717200000}
+		]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	res, err := s.client.NewGetKlinesService().Symbol("BTC-USDT").Interval("1h").Limit(1).Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal([]*Kline{{
+		Open:   "43190.0",
+		Close:  "43206.4",
+		High:   "43215.2",
+		Low:    "43180.5",
+		Volume: "120.3400",
+		Time:   1702717200000,
+	}}, res)
+}
+
+func (s *marketsServiceTestSuite) TestGetTickerSingle() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": {"symbol": "BTC-USDT", "lastPrice": "43044.5", "openTime": 1702632000000}
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	res, err := s.client.NewGetTickerService().Symbol("BTC-USDT").Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Len(res, 1)
+	r.Equal("BTC-USDT", res[0].Symbol)
+	r.Equal("43044.5", res[0].LastPrice)
+	r.Equal(int64(1702632000000), res[0].OpenTime)
+}
+
+func (s *marketsServiceTestSuite) TestGetTickerAll() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": [{"symbol": "BTC-USDT"}, {"symbol": "ETH-USDT"}]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	res, err := s.client.NewGetTickerService().Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Len(res, 2)
+	r.Equal("ETH-USDT", res[1].Symbol)
+}
+
+func (s *marketsServiceTestSuite) TestGetPremiumIndex() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": {
+			"symbol": "BTC-USDT",
+			"markPrice": "43048.1",
+			"indexPrice": "43050.2",
+			"lastFundingRate": "0.0001",
+			"nextFundingTime": 1702742400000
+		}
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	res, err := s.client.NewGetPremiumIndexService().Symbol("BTC-USDT").Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal([]*PremiumIndex{{
+		Symbol:          "BTC-USDT",
+		MarkPrice:       "43048.1",
+		IndexPrice:      "43050.2",
+		LastFundingRate: "0.0001",
+		NextFundingTime: 1702742400000,
+	}}, res)
+}
+
+func (s *marketsServiceTestSuite) TestGetFundingRate() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": [{"symbol": "BTC-USDT", "fundingRate": "0.0003", "fundingTime": 1702713600000}]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setParams(params{
+			"symbol":     "BTC-USDT",
+			"startTime":  1702000000000,
+			"endTime":    1702800000000,
+			"limit":      100,
+			"recvWindow": 10000,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewGetFundingRateService().
+		Symbol("BTC-USDT").
+		StartTime(1702000000000).
+		EndTime(1702800000000).
+		Limit(100).
+		Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal([]*FundingRate{{Symbol: "BTC-USDT", FundingRate: "0.0003", FundingTime: 1702713600000}}, res)
+}
+
+func (s *marketsServiceTestSuite) TestGetOpenInterest() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": {"openInterest": "3289641547.10", "symbol": "BTC-USDT", "time": 1702719692042}
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	res, err := s.client.NewGetOpenInterestService().Symbol("BTC-USDT").Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal(&OpenInterest{Symbol: "BTC-USDT", OpenInterest: "3289641547.10", Time: 1702719692042}, res)
+}
+
+func (s *marketsServiceTestSuite) TestGetContracts() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": [{
+			"contractId": "100",
+			"symbol": "BTC-USDT",
+			"size": "0.0001",
+			"quantityPrecision": 4,
+			"pricePrecision": 1,
+			"feeRate": 0.0005,
+			"makerFeeRate": 0.0002,
+			"takerFeeRate": 0.0005,
+			"tradeMinQuantity": 0.0001,
+			"tradeMinUSDT": 2,
+			"maxLongLeverage": 125,
+			"maxShortLeverage": 100,
+			"currency": "USDT",
+			"asset": "BTC",
+			"status": 1,
+			"apiStateOpen": "true",
+			"apiStateClose": "true"
+		}]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	res, err := s.client.NewGetContractsService().Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Len(res, 1)
+	r.Equal("BTC-USDT", res[0].Symbol)
+	r.Equal(0.0002, res[0].MakerFeeRate)
+	r.Equal(100, res[0].MaxShortLeverage)
+	r.True(res[0].TradingEnabled())
+	r.InDelta(0.1, res[0].PriceStep(), 1e-12)
+	r.InDelta(0.0001, res[0].QuantityStep(), 1e-12)
+}
+
+func (s *marketsServiceTestSuite) TestContractRegistry() {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": [{"symbol": "BTC-USDT", "status": 1}, {"symbol": "ETH-USDT", "status": 0}]
+	}`)
+	calls := 0
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newHTTPResponse(data, http.StatusOK), nil
+	}
+
+	registry := s.client.NewContractRegistry(0)
+	r := s.r()
+	r.True(registry.Stale())
+
+	contract, err := registry.Lookup(newContext(), "ETH-USDT")
+	r.NoError(err)
+	r.Equal(0, contract.Status)
+	r.False(registry.Stale())
+	r.Len(registry.Contracts(), 2)
+
+	_, ok := registry.Get("BTC-USDT")
+	r.True(ok)
+	r.Equal(1, calls)
+
+	// unknown symbol right after refresh is answered from cache
+	_, err = registry.Lookup(newContext(), "XRP-USDT")
+	r.EqualError(err, "bingx: unknown contract XRP-USDT")
+	r.Equal(1, calls)
+
+	registry.mu.Lock()
+	registry.updatedAt = time.Now().Add(-maxContractRegistryMissRefresh)
+	registry.mu.Unlock()
+
+	_, err = registry.Lookup(newContext(), "XRP-USDT")
+	r.EqualError(err, "bingx: unknown contract XRP-USDT")
+	r.Equal(2, calls)
+}
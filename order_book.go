@@ -0,0 +1,429 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOrderBookStaleTimeout  = 10 * time.Second
+	defaultOrderBookResyncTimeout = 10 * time.Second
+	defaultOrderBookResyncBackoff = time.Second
+	maxOrderBookResyncBackoff     = time.Minute
+)
+
+var (
+	// ErrOrderBookOutOfSync book state is inconsistent and is being resynchronized
+	ErrOrderBookOutOfSync = errors.New("bingx: order book is out of sync")
+	// ErrOrderBookInsufficientDepth book has not enough quantity to fill order
+	ErrOrderBookInsufficientDepth = errors.New("bingx: order book depth is insufficient")
+)
+
+// OrderBook Local order book of one symbol built from REST snapshot and depth stream.
+// Depth stream pushes top levels, so levels below stream depth come from the last snapshot.
+type OrderBook struct {
+	// StaleTimeout book is resynchronized when no update arrives for this long
+	StaleTimeout time.Duration
+	// ResyncTimeout limits snapshot requests of background resynchronization, zero means no limit
+	ResyncTimeout time.Duration
+
+	c      *Client
+	symbol string
+	level  int
+
+	mu        sync.RWMutex
+	bids      []WsDepthLevel
+	asks      []WsDepthLevel
+	lastTime  int64
+	updatedAt time.Time
+	synced    bool
+	resyncing bool
+	// resyncBackoff delay before the first retry of failed background resynchronization
+	resyncBackoff time.Duration
+
+	errHandler ErrHandler
+	stream     *Stream
+	// ctx lives until book is stopped, background resynchronization is bound to it
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewOrderBook Init order book, level is one of 5, 10, 20, 50, 100
+func (c *Client) NewOrderBook(symbol string, level int) *OrderBook {
+	return &OrderBook{
+		StaleTimeout:  defaultOrderBookStaleTimeout,
+		ResyncTimeout: defaultOrderBookResyncTimeout,
+		resyncBackoff: defaultOrderBookResyncBackoff,
+		c:             c,
+		symbol:        symbol,
+		level:         level,
+	}
+}
+
+// Start Load snapshot and keep book up to date with depth stream until ctx is done or Stop is called
+func (b *OrderBook) Start(ctx context.Context, errHandler ErrHandler, opts ...WsOption) error {
+	b.errHandler = errHandler
+	b.ctx, b.cancel = context.WithCancel(ctx)
+
+	err := b.Resync(b.ctx)
+	if err != nil {
+		b.cancel()
+		return err
+	}
+
+	// resync after reconnect, updates of the gap are lost
+	onGap := newWsConfig("", opts...).OnGap
+	opts = append(opts, WithWsGapHandler(func(gap WsGap) {
+		b.invalidate()
+		if onGap != nil {
+			onGap(gap)
+		}
+	}))
+
+	stream, err := WsDepthServe(b.ctx, b.symbol, b.level, b.handle, errHandler, opts...)
+	if err != nil {
+		b.cancel()
+		return err
+	}
+
+	b.stream = stream
+	go b.watch()
+
+	return nil
+}
+
+// Stop Close depth stream and cancel background resynchronization, safe to call many times
+func (b *OrderBook) Stop() {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	if b.stream != nil {
+		b.stream.Close()
+	}
+}
+
+// Done Closed when depth stream is stopped
+func (b *OrderBook) Done() <-chan struct{} {
+	if b.stream == nil {
+		return nil
+	}
+	return b.stream.Done()
+}
+
+func (b *OrderBook) reportErr(err error) {
+	if b.errHandler != nil {
+		b.errHandler(err)
+	}
+}
+
+func (b *OrderBook) handle(event *WsDepthEvent) {
+	err := b.Apply(event)
+	if err != nil {
+		b.reportErr(err)
+		b.invalidate()
+	}
+}
+
+func (b *OrderBook) watch() {
+	period := b.StaleTimeout / 2
+	if period <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stream.Done():
+			return
+		case <-ticker.C:
+			b.mu.RLock()
+			stale := b.synced && time.Since(b.updatedAt) > b.StaleTimeout
+			b.mu.RUnlock()
+
+			if stale {
+				b.reportErr(fmt.Errorf("%w: no updates for %s", ErrOrderBookOutOfSync, b.StaleTimeout))
+				b.invalidate()
+			}
+		}
+	}
+}
+
+// invalidate marks book out of sync and resynchronizes it in background. Failed resync is retried
+// with backoff until it succeeds or book is stopped.
+func (b *OrderBook) invalidate() {
+	b.mu.Lock()
+	b.synced = false
+	if b.resyncing {
+		b.mu.Unlock()
+		return
+	}
+	b.resyncing = true
+	b.mu.Unlock()
+
+	go func() {
+		defer func() {
+			b.mu.Lock()
+			b.resyncing = false
+			b.mu.Unlock()
+		}()
+
+		lifetime := b.ctx
+		if lifetime == nil {
+			lifetime = context.Background()
+		}
+		backoff := b.resyncBackoff
+		for {
+			err := b.resyncOnce(lifetime)
+			if err == nil {
+				return
+			}
+			// failure caused by stopping the book is not reported
+			if lifetime.Err() != nil {
+				return
+			}
+			b.reportErr(err)
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-lifetime.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			if backoff *= 2; backoff > maxOrderBookResyncBackoff {
+				backoff = maxOrderBookResyncBackoff
+			}
+		}
+	}()
+}
+
+// resyncOnce loads snapshot within ResyncTimeout
+func (b *OrderBook) resyncOnce(lifetime context.Context) error {
+	ctx := lifetime
+	if b.ResyncTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(lifetime, b.ResyncTimeout)
+		defer cancel()
+	}
+	return b.Resync(ctx)
+}
+
+// Resync Replace book with REST snapshot
+func (b *OrderBook) Resync(ctx context.Context) error {
+	depth, err := b.c.NewGetDepthService().Symbol(b.symbol).Limit(b.level).Do(ctx)
+	if err != nil {
+		return err
+	}
+	if depth == nil {
+		return fmt.Errorf("bingx: empty depth snapshot of %s", b.symbol)
+	}
+
+	bids, err := parseWsDepthLevels(depth.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := parseWsDepthLevels(depth.Asks)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bids = sortDepthLevels(bids, true)
+	b.asks = sortDepthLevels(asks, false)
+	b.lastTime = depth.Time
+	b.updatedAt = time.Now()
+	b.synced = !crossed(b.bids, b.asks)
+
+	if !b.synced {
+		return fmt.Errorf("%w: crossed snapshot", ErrOrderBookOutOfSync)
+	}
+
+	return nil
+}
+
+// Apply Merge depth event into book, out of order events are ignored.
+// Returns ErrOrderBookOutOfSync when resulting book is crossed.
+func (b *OrderBook) Apply(event *WsDepthEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if event.Time != 0 && event.Time < b.lastTime {
+		return nil
+	}
+
+	full := len(event.Bids) >= b.level && len(event.Asks) >= b.level
+	b.bids = mergeDepthLevels(b.bids, event.Bids, true, full)
+	b.asks = mergeDepthLevels(b.asks, event.Asks, false, full)
+	if event.Time != 0 {
+		b.lastTime = event.Time
+	}
+	b.updatedAt = time.Now()
+
+	if crossed(b.bids, b.asks) {
+		b.synced = false
+		return fmt.Errorf("%w: crossed book", ErrOrderBookOutOfSync)
+	}
+
+	return nil
+}
+
+func sortDepthLevels(levels []WsDepthLevel, desc bool) []WsDepthLevel {
+	res := make([]WsDepthLevel, 0, len(levels))
+	for _, level := range levels {
+		if level.Quantity > 0 {
+			res = append(res, level)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if desc {
+			return res[i].Price > res[j].Price
+		}
+		return res[i].Price < res[j].Price
+	})
+	return res
+}
+
+// mergeDepthLevels replaces book levels covered by update, deeper levels are kept when update is full
+func mergeDepthLevels(book, update []WsDepthLevel, desc, full bool) []WsDepthLevel {
+	res := sortDepthLevels(update, desc)
+	if !full || len(res) == 0 {
+		return res
+	}
+
+	worst := res[len(res)-1].Price
+	for _, level := range book {
+		if (desc && level.Price < worst) || (!desc && level.Price > worst) {
+			res = append(res, level)
+		}
+	}
+	return res
+}
+
+func crossed(bids, asks []WsDepthLevel) bool {
+	return len(bids) > 0 && len(asks) > 0 && bids[0].Price >= asks[0].Price
+}
+
+// Synced Book is consistent with exchange as far as we know
+func (b *OrderBook) Synced() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.synced
+}
+
+// UpdatedAt Time of the last applied update
+func (b *OrderBook) UpdatedAt() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.updatedAt
+}
+
+// Snapshot Copy of bids in descending and asks in ascending order
+func (b *OrderBook) Snapshot() (bids, asks []WsDepthLevel) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	bids = append([]WsDepthLevel(nil), b.bids...)
+	asks = append([]WsDepthLevel(nil), b.asks...)
+	return bids, asks
+}
+
+func (b *OrderBook) BestBid() (WsDepthLevel, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.bids) == 0 {
+		return WsDepthLevel{}, false
+	}
+	return b.bids[0], true
+}
+
+func (b *OrderBook) BestAsk() (WsDepthLevel, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.asks) == 0 {
+		return WsDepthLevel{}, false
+	}
+	return b.asks[0], true
+}
+
+// Spread Difference between best ask and best bid
+func (b *OrderBook) Spread() (float64, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.bids) == 0 || len(b.asks) == 0 {
+		return 0, false
+	}
+	return b.asks[0].Price - b.bids[0].Price, true
+}
+
+// Mid Average of best ask and best bid
+func (b *OrderBook) Mid() (float64, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.bids) == 0 || len(b.asks) == 0 {
+		return 0, false
+	}
+	return (b.asks[0].Price + b.bids[0].Price) / 2, true
+}
+
+// DepthAt Total quantity of bids at or above price for SELL side
+// or asks at or below price for BUY side, i.e. quantity an order of side may take up to price
+func (b *OrderBook) DepthAt(side SideType, price float64) float64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var quantity float64
+	for _, level := range b.levels(side) {
+		if (side == BuySideType && level.Price > price) || (side == SellSideType && level.Price < price) {
+			break
+		}
+		quantity += level.Quantity
+	}
+	return quantity
+}
+
+// VWAP Average price to fill quantity by market order of side
+func (b *OrderBook) VWAP(side SideType, quantity float64) (float64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if quantity <= 0 {
+		return 0, fmt.Errorf("bingx: invalid quantity %v", quantity)
+	}
+
+	var filled, cost float64
+	for _, level := range b.levels(side) {
+		take := level.Quantity
+		if filled+take > quantity {
+			take = quantity - filled
+		}
+		filled += take
+		cost += take * level.Price
+		if filled >= quantity {
+			return cost / filled, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %v of %v available", ErrOrderBookInsufficientDepth, filled, quantity)
+}
+
+// levels returns side of book an order of side takes liquidity from
+func (b *OrderBook) levels(side SideType) []WsDepthLevel {
+	if side == BuySideType {
+		return b.asks
+	}
+	return b.bids
+}
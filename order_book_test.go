@@ -0,0 +1,190 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type orderBookTestSuite struct {
+	baseTestSuite
+}
+
+func TestOrderBook(t *testing.T) {
+	suite.Run(t, new(orderBookTestSuite))
+}
+
+func (s *orderBookTestSuite) newSyncedBook() *OrderBook {
+	data := []byte(`{
+		"code": 0,
+		"msg": "",
+		"data": {
+			"T": 1000,
+			"bids": [["99", "2"], ["100", "1"], ["98", "5"]],
+			"asks": [["102", "3"], ["101", "1"], ["103", "4"]]
+		}
+	}`)
+	s.mockDo(data, nil)
+
+	book := s.client.NewOrderBook("BTC-USDT", 3)
+	s.r().NoError(book.Resync(newContext()))
+	return book
+}
+
+func (s *orderBookTestSuite) TestQueries() {
+	r := s.r()
+	book := s.newSyncedBook()
+	r.True(book.Synced())
+
+	bid, ok := book.BestBid()
+	r.True(ok)
+	r.Equal(WsDepthLevel{Price: 100, Quantity: 1}, bid)
+	ask, ok := book.BestAsk()
+	r.True(ok)
+	r.Equal(WsDepthLevel{Price: 101, Quantity: 1}, ask)
+
+	spread, _ := book.Spread()
+	r.Equal(1.0, spread)
+	mid, _ := book.Mid()
+	r.Equal(100.5, mid)
+
+	r.Equal(4.0, book.DepthAt(BuySideType, 102))
+	r.Equal(3.0, book.DepthAt(SellSideType, 99))
+	r.Equal(0.0, book.DepthAt(BuySideType, 100))
+
+	vwap, err := book.VWAP(BuySideType, 3)
+	r.NoError(err)
+	r.InDelta((101+2*102)/3.0, vwap, 1e-9)
+
+	_, err = book.VWAP(SellSideType, 9)
+	r.True(errors.Is(err, ErrOrderBookInsufficientDepth))
+}
+
+func (s *orderBookTestSuite) TestApply() {
+	r := s.r()
+	book := s.newSyncedBook()
+
+	r.NoError(book.Apply(&WsDepthEvent{
+		Time: 2000,
+		Bids: []WsDepthLevel{{Price: 100.5, Quantity: 1}, {Price: 100, Quantity: 2}, {Price: 99.5, Quantity: 1}},
+		Asks: []WsDepthLevel{{Price: 101, Quantity: 2}, {Price: 101.5, Quantity: 1}, {Price: 102, Quantity: 1}},
+	}))
+
+	bids, asks := book.Snapshot()
+	r.Equal([]WsDepthLevel{{100.5, 1}, {100, 2}, {99.5, 1}, {99, 2}, {98, 5}}, bids)
+	r.Equal([]WsDepthLevel{{101, 2}, {101.5, 1}, {102, 1}, {103, 4}}, asks)
+
+	// out of order events are ignored
+	r.NoError(book.Apply(&WsDepthEvent{Time: 1500, Bids: []WsDepthLevel{{Price: 1, Quantity: 1}}}))
+	bid, _ := book.BestBid()
+	r.Equal(100.5, bid.Price)
+
+	// partial update means thin book, it replaces whole side
+	r.NoError(book.Apply(&WsDepthEvent{Time: 3000, Bids: []WsDepthLevel{{Price: 100, Quantity: 1}}, Asks: asks[:1]}))
+	bids, _ = book.Snapshot()
+	r.Equal([]WsDepthLevel{{100, 1}}, bids)
+	r.True(book.Synced())
+}
+
+func (s *orderBookTestSuite) TestCrossedBook() {
+	r := s.r()
+	book := s.newSyncedBook()
+
+	err := book.Apply(&WsDepthEvent{
+		Time: 2000,
+		Bids: []WsDepthLevel{{Price: 101.5, Quantity: 1}},
+		Asks: []WsDepthLevel{{Price: 101, Quantity: 1}},
+	})
+	r.True(errors.Is(err, ErrOrderBookOutOfSync))
+	r.False(book.Synced())
+}
+
+func (s *orderBookTestSuite) TestResyncWithoutStaleTimeout() {
+	r := s.r()
+	book := s.newSyncedBook()
+	book.StaleTimeout = 0
+	book.errHandler = func(err error) {
+		r.NoError(err)
+	}
+	data := []byte(`{"code":0,"data":{"T":2000,"bids":[["100","1"]],"asks":[["101","1"]]}}`)
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		if err := req.Context().Err(); err != nil {
+			return nil, err
+		}
+		return newHTTPResponse(data, http.StatusOK), nil
+	}
+
+	book.invalidate()
+	r.Eventually(book.Synced, time.Second, time.Millisecond)
+}
+
+func (s *orderBookTestSuite) TestResyncRetry() {
+	r := s.r()
+	book := s.newSyncedBook()
+	book.resyncBackoff = time.Millisecond
+	var mu sync.Mutex
+	var errs []error
+	book.errHandler = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+	requests := 0
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		requests++
+		first := requests == 1
+		mu.Unlock()
+		if first {
+			return newHTTPResponse([]byte(`{"code":100410,"msg":"rate limited"}`), http.StatusTooManyRequests), nil
+		}
+		return newHTTPResponse([]byte(`{"code":0,"data":{"T":2000,"bids":[["100","1"]],"asks":[["101","1"]]}}`), http.StatusOK), nil
+	}
+
+	// snapshot after reconnect gap fails, book is synchronized by the retry
+	book.invalidate()
+	r.Eventually(book.Synced, time.Second, time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	r.Equal(2, requests)
+	r.Len(errs, 1)
+}
+
+func (s *orderBookTestSuite) TestStopCancelsResync() {
+	r := s.r()
+	book := s.newSyncedBook()
+	book.ctx, book.cancel = context.WithCancel(newContext())
+	var mu sync.Mutex
+	var errs []error
+	book.errHandler = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+	started := make(chan struct{})
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+
+	book.invalidate()
+	<-started
+	book.Stop()
+	r.Eventually(func() bool {
+		book.mu.RLock()
+		defer book.mu.RUnlock()
+		return !book.resyncing
+	}, time.Second, time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	r.Empty(errs)
+	r.False(book.Synced())
+}
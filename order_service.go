@@ -15,6 +15,7 @@ type CreateOrderService struct {
 	positionSide  PositionSideType
 	clientOrderID string
 	reduceOnly    string
+	timeInForce   TimeInForceType
 	price         float64
 	quantity      float64
 }
@@ -49,6 +50,11 @@ func (s *CreateOrderService) ReduceOnly() *CreateOrderService {
 	return s
 }
 
+func (s *CreateOrderService) TimeInForce(timeInForce TimeInForceType) *CreateOrderService {
+	s.timeInForce = timeInForce
+	return s
+}
+
 func (s *CreateOrderService) Price(price float64) *CreateOrderService {
 	s.price = price
 	return s
@@ -92,6 +98,10 @@ func (s *CreateOrderService) Do(ctx context.Context, opts ...RequestOption) (res
 		r.addParam("reduceOnly", s.reduceOnly)
 	}
 
+	if s.timeInForce != "" {
+		r.addParam("timeInForce", s.timeInForce)
+	}
+
 	if s.price != 0 {
 		r.addParam("price", s.price)
 	}
@@ -100,6 +110,23 @@ func (s *CreateOrderService) Do(ctx context.Context, opts ...RequestOption) (res
 		r.addParam("quantity", s.quantity)
 	}
 
+	if s.c.RiskGuard != nil {
+		err = s.c.RiskGuard.Check(ctx, s.c, &OrderRequest{
+			Symbol:        s.symbol,
+			Type:          s.orderType,
+			Side:          s.side,
+			PositionSide:  s.positionSide,
+			ClientOrderID: s.clientOrderID,
+			ReduceOnly:    s.reduceOnly != "",
+			TimeInForce:   s.timeInForce,
+			Price:         s.price,
+			Quantity:      s.quantity,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	data, err := s.c.callAPI(ctx, r, opts...)
 	if err != nil {
 		return nil, err
@@ -1 +1,232 @@
 package bingx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/magicaleks/go-bingx/bingxtest"
+	"github.com/stretchr/testify/suite"
+)
+
+type orderServiceTestSuite struct {
+	serverTestSuite
+}
+
+func TestOrderService(t *testing.T) {
+	suite.Run(t, new(orderServiceTestSuite))
+}
+
+func (s *orderServiceTestSuite) createOrder(orderType OrderType, side SideType, price, quantity float64) (*CreateOrderResponse, error) {
+	return s.client.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(orderType).
+		Side(side).
+		Price(price).
+		Quantity(quantity).
+		Do(newContext())
+}
+
+func (s *orderServiceTestSuite) TestLimitOrder() {
+	r := s.r()
+	s.server.SetPrice("BTC-USDT", 43000)
+
+	created, err := s.client.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(LimitOrderType).
+		Side(BuySideType).
+		ClientOrderID("my-order").
+		Price(42000).
+		Quantity(0.01).
+		Do(newContext())
+	r.NoError(err)
+	r.NotZero(created.OrderId)
+
+	open, err := s.client.NewGetOpenOrdersService().Symbol("BTC-USDT").Do(newContext())
+	r.NoError(err)
+	r.Len(open.Orders, 1)
+	r.Equal(created.OrderId, open.Orders[0].OrderId)
+	r.Equal("my-order", open.Orders[0].ClientOrderID)
+	r.Equal(NewOrderStatus, open.Orders[0].Status)
+	r.Equal(BothPositionSideType, open.Orders[0].PositionSide)
+	r.Equal("42000", open.Orders[0].Price)
+
+	s.server.SetPrice("BTC-USDT", 41900)
+	order, err := s.client.NewGetOrderService().Symbol("BTC-USDT").ClientOrderId("my-order").Do(newContext())
+	r.NoError(err)
+	r.Equal(FilledOrderStatus, order.Status)
+	r.Equal("42000", order.AveragePrice)
+	r.Equal("0.01", order.Quantity)
+	r.Equal("-0.084", order.Fee)
+
+	_, err = s.client.NewCancelOrderService().Symbol("BTC-USDT").OrderId(created.OrderId).Do(newContext())
+	r.Equal(int64(bingxtest.OrderNotExistErrorCode), s.apiErrorCode(err))
+}
+
+func (s *orderServiceTestSuite) TestTimeInForce() {
+	r := s.r()
+	s.server.SetPrice("BTC-USDT", 43000)
+
+	create := func(timeInForce TimeInForceType, price float64) OrderStatus {
+		created, err := s.client.NewCreateOrderService().
+			Symbol("BTC-USDT").
+			Type(LimitOrderType).
+			Side(BuySideType).
+			TimeInForce(timeInForce).
+			Price(price).
+			Quantity(0.01).
+			Do(newContext())
+		r.NoError(err)
+		order, ok := s.server.Order(created.OrderId)
+		r.True(ok)
+		r.Equal(string(timeInForce), order.TimeInForce)
+		return OrderStatus(order.Status)
+	}
+
+	r.Equal(ExpiredOrderStatus, create(PostOnlyTimeInForceType, 43100))
+	r.Equal(NewOrderStatus, create(PostOnlyTimeInForceType, 42900))
+	r.Equal(ExpiredOrderStatus, create(IOCTimeInForceType, 42900))
+	r.Equal(FilledOrderStatus, create(IOCTimeInForceType, 43100))
+}
+
+func (s *orderServiceTestSuite) TestMarketOrder() {
+	r := s.r()
+	_, err := s.createOrder(MarketOrderType, BuySideType, 0, 0.1)
+	r.Equal(int64(bingxtest.NoPriceErrorCode), s.apiErrorCode(err))
+
+	s.server.SetPrice("BTC-USDT", 43000)
+	created, err := s.createOrder(MarketOrderType, BuySideType, 0, 0.1)
+	r.NoError(err)
+
+	order, err := s.client.NewGetOrderService().Symbol("BTC-USDT").OrderId(created.OrderId).Do(newContext())
+	r.NoError(err)
+	r.Equal(FilledOrderStatus, order.Status)
+	r.Equal("43000", order.AveragePrice)
+
+	s.server.SetPrice("BTC-USDT", 44000)
+	created, err = s.createOrder(MarketOrderType, SellSideType, 0, 0.1)
+	r.NoError(err)
+	order, err = s.client.NewGetOrderService().Symbol("BTC-USDT").OrderId(created.OrderId).Do(newContext())
+	r.NoError(err)
+	r.Equal("100", order.Profit)
+	r.Empty(s.server.Positions())
+}
+
+func (s *orderServiceTestSuite) TestCancelOrders() {
+	r := s.r()
+	first, err := s.createOrder(LimitOrderType, BuySideType, 40000, 0.01)
+	r.NoError(err)
+	_, err = s.createOrder(LimitOrderType, SellSideType, 50000, 0.01)
+	r.NoError(err)
+	_, err = s.createOrder(LimitOrderType, SellSideType, 51000, 0.01)
+	r.NoError(err)
+
+	canceled, err := s.client.NewCancelOrderService().Symbol("BTC-USDT").OrderId(first.OrderId).Do(newContext())
+	r.NoError(err)
+	r.Equal(CanceledOrderStatus, canceled.Status)
+	r.Equal(first.OrderId, canceled.OrderId)
+
+	all, err := s.client.NewCancelAllOrdersService().Symbol("BTC-USDT").Do(newContext())
+	r.NoError(err)
+	r.Len(all.Success, 2)
+	r.Empty(all.Failed)
+
+	open, err := s.client.NewGetOpenOrdersService().Do(newContext())
+	r.NoError(err)
+	r.Empty(open.Orders)
+}
+
+func (s *orderServiceTestSuite) TestRejectedOrders() {
+	r := s.r()
+	s.server.SetBalance(100)
+	s.server.SetLeverage("BTC-USDT", 10)
+
+	_, err := s.createOrder(LimitOrderType, BuySideType, 40000, 0.03)
+	r.Equal(int64(bingxtest.InsufficientMarginErrorCode), s.apiErrorCode(err))
+	_, err = s.createOrder(LimitOrderType, BuySideType, 40000, 0.02)
+	r.NoError(err)
+
+	_, err = s.createOrder(LimitOrderType, BuySideType, 0, 0.02)
+	r.Equal(int64(bingxtest.InvalidParamsErrorCode), s.apiErrorCode(err))
+
+	_, err = s.client.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(LimitOrderType).
+		Side(SellSideType).
+		PositionSide(LongPositionSideType).
+		Price(40000).
+		Quantity(0.01).
+		Do(newContext())
+	r.Equal(int64(bingxtest.NoPositionErrorCode), s.apiErrorCode(err))
+}
+
+func (s *orderServiceTestSuite) TestOrderUpdates() {
+	r := s.r()
+	s.server.SetPrice("BTC-USDT", 43000)
+	s.server.SetFeeRate(0, 0.001)
+
+	listenKey, err := s.client.NewGetAccountListenKeyService().Do(newContext())
+	r.NoError(err)
+	r.Equal(s.server.ListenKey, listenKey)
+
+	orders, err := WsOrderUpdateChan(newContext(), listenKey, WithWsEndpoint(s.server.UserWsURL()))
+	r.NoError(err)
+	defer orders.Close()
+	r.NoError(s.server.WaitUserStream(5 * time.Second))
+
+	created, err := s.createOrder(MarketOrderType, BuySideType, 0, 0.1)
+	r.NoError(err)
+
+	var updates []*WsOrder
+	for len(updates) < 2 {
+		select {
+		case order := <-orders.C():
+			updates = append(updates, order)
+		case <-time.After(5 * time.Second):
+			s.FailNow("no order update")
+		}
+	}
+	r.Equal(created.OrderId, updates[0].OrderId)
+	r.Equal(NewOrderSpecType, updates[0].Spec)
+	r.Equal(TradeOrderSpecType, updates[1].Spec)
+	r.Equal(FilledOrderStatus, updates[1].Status)
+	r.Equal("0.1", updates[1].LastFilledQty)
+	r.Equal("-4.3", updates[1].Fee)
+}
+
+func (s *orderServiceTestSuite) TestScriptedResponses() {
+	r := s.r()
+	s.server.Fail(http.MethodPost, "/openApi/swap/v2/trade/order", 80012, "service unavailable")
+	s.server.Respond(http.MethodPost, "/openApi/swap/v2/trade/order", http.StatusOK, `{"code":0,"msg":"","data":{"order":{"orderId":7}}}`)
+
+	_, err := s.createOrder(LimitOrderType, BuySideType, 40000, 0.01)
+	r.Equal(int64(80012), s.apiErrorCode(err))
+	created, err := s.createOrder(LimitOrderType, BuySideType, 40000, 0.01)
+	r.NoError(err)
+	r.Equal(int64(7), created.OrderId)
+	r.Empty(s.server.Orders())
+
+	created, err = s.createOrder(LimitOrderType, BuySideType, 40000, 0.01)
+	r.NoError(err)
+	r.Len(s.server.Orders(), 1)
+	r.Equal(created.OrderId, s.server.Orders()[0].OrderId)
+
+	requests := s.server.Requests()
+	r.Len(requests, 3)
+	r.Equal("0.01", requests[2].Params.Get("quantity"))
+	r.Equal("BOTH", requests[2].Params.Get("positionSide"))
+}
+
+func (s *orderServiceTestSuite) TestSignature() {
+	r := s.r()
+	client := NewClient("dummyAPIKey", "wrongSecretKey")
+	client.BaseURL = s.server.URL
+	_, err := client.NewGetOpenOrdersService().Do(newContext())
+	r.Equal(int64(bingxtest.SignatureErrorCode), s.apiErrorCode(err))
+
+	client = NewClient("wrongAPIKey", "dummySecretKey")
+	client.BaseURL = s.server.URL
+	_, err = client.NewGetOpenOrdersService().Do(newContext())
+	r.Equal(int64(bingxtest.APIKeyErrorCode), s.apiErrorCode(err))
+	r.Empty(s.server.Requests())
+}
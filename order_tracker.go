@@ -0,0 +1,558 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+const defaultOrderTrackerStaleTimeout = 30 * time.Second
+
+var (
+	// ErrOrderNotTracked order is not known to tracker
+	ErrOrderNotTracked = errors.New("bingx: order is not tracked")
+	// ErrOrderNotFilled order reached terminal status without being fully filled
+	ErrOrderNotFilled = errors.New("bingx: order is not filled")
+)
+
+// OrderFill Define trade of tracked order
+type OrderFill struct {
+	Time int64
+	// Price of this trade, derived from average price of order when update does not report it
+	Price    float64
+	Quantity float64
+	// Fee negative when paid, as reported by exchange
+	Fee         float64
+	RealizedPnl float64
+}
+
+// TrackedOrder Define state of order merged from REST responses and order updates
+type TrackedOrder struct {
+	OrderId       int64
+	ClientOrderID string
+	Symbol        string
+	Side          SideType
+	PositionSide  PositionSideType
+	Type          OrderType
+	Status        OrderStatus
+	Price         float64
+	Quantity      float64
+	FilledQty     float64
+	AveragePrice  float64
+	// Fee accumulated, negative when paid
+	Fee float64
+	// Fills trades received from order updates, may miss trades which happened while stream was disconnected
+	Fills []OrderFill
+	// UpdateTime time of the last applied update in milliseconds
+	UpdateTime int64
+}
+
+// RemainingQty Quantity left to fill, zero once order is done
+func (o *TrackedOrder) RemainingQty() float64 {
+	if o.Done() || o.FilledQty >= o.Quantity {
+		return 0
+	}
+	return o.Quantity - o.FilledQty
+}
+
+// Done Order reached terminal status and will not change anymore
+func (o *TrackedOrder) Done() bool {
+	return orderStatusRank(o.Status) == 2
+}
+
+func (o *TrackedOrder) clone() *TrackedOrder {
+	res := *o
+	res.Fills = append([]OrderFill(nil), o.Fills...)
+	return &res
+}
+
+// orderStatusRank orders statuses so that state never goes back
+func orderStatusRank(status OrderStatus) int {
+	switch status {
+	case FilledOrderStatus, CanceledOrderStatus, ExpiredOrderStatus:
+		return 2
+	case PartiallyFilledOrderStatus:
+		return 1
+	}
+	return 0
+}
+
+// newer update may replace current state: filled quantity never decreases, status never goes back
+// and updates of the same progress are applied in time order
+func (o *TrackedOrder) newer(filledQty float64, status OrderStatus, updateTime int64) bool {
+	if filledQty != o.FilledQty {
+		return filledQty > o.FilledQty
+	}
+	if rank, current := orderStatusRank(status), orderStatusRank(o.Status); rank != current {
+		return rank > current
+	}
+	return updateTime >= o.UpdateTime
+}
+
+// OrderTracker Local state of account orders built from order updates of user data stream.
+// Orders placed with Create or added with Track are polled with GetOrderService after reconnect
+// and when stream is silent, updates are merged by filled quantity, status and time.
+type OrderTracker struct {
+	// StaleTimeout open orders are polled when no order update arrives for this long
+	StaleTimeout time.Duration
+
+	c *Client
+
+	mu        sync.Mutex
+	orders    map[int64]*TrackedOrder
+	clientIDs map[string]int64
+	changedC  chan struct{}
+	updatedAt time.Time
+	polling   bool
+
+	errHandler ErrHandler
+	stream     *Stream
+}
+
+func (c *Client) NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		StaleTimeout: defaultOrderTrackerStaleTimeout,
+		c:            c,
+		orders:       map[int64]*TrackedOrder{},
+		clientIDs:    map[string]int64{},
+		changedC:     make(chan struct{}),
+	}
+}
+
+// Start Apply order updates of listen key until ctx is done or Stop is called
+func (t *OrderTracker) Start(ctx context.Context, listenKey string, errHandler ErrHandler, opts ...WsOption) error {
+	t.errHandler = errHandler
+
+	// poll after reconnect, updates of the gap are lost
+	onGap := newWsConfig("", opts...).OnGap
+	opts = append(opts, WithWsGapHandler(func(gap WsGap) {
+		t.poll()
+		if onGap != nil {
+			onGap(gap)
+		}
+	}))
+
+	t.mu.Lock()
+	t.updatedAt = time.Now()
+	t.mu.Unlock()
+
+	stream, err := WsOrderUpdateServe(ctx, listenKey, t.handle, errHandler, opts...)
+	if err != nil {
+		return err
+	}
+
+	t.stream = stream
+	go t.watch()
+
+	return nil
+}
+
+// Stop Close order update stream, safe to call many times
+func (t *OrderTracker) Stop() {
+	if t.stream != nil {
+		t.stream.Close()
+	}
+}
+
+// Done Closed when order update stream is stopped
+func (t *OrderTracker) Done() <-chan struct{} {
+	if t.stream == nil {
+		return nil
+	}
+	return t.stream.Done()
+}
+
+func (t *OrderTracker) reportErr(err error) {
+	if t.errHandler != nil {
+		t.errHandler(err)
+	}
+}
+
+func (t *OrderTracker) handle(order *WsOrder) {
+	err := t.ApplyUpdate(order)
+	if err != nil {
+		t.reportErr(err)
+	}
+}
+
+func (t *OrderTracker) watch() {
+	period := t.StaleTimeout / 2
+	if period <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.stream.Done():
+			return
+		case <-ticker.C:
+			t.mu.Lock()
+			stale := time.Since(t.updatedAt) > t.StaleTimeout
+			t.mu.Unlock()
+
+			if stale {
+				t.poll()
+			}
+		}
+	}
+}
+
+// poll refreshes open orders in background
+func (t *OrderTracker) poll() {
+	t.mu.Lock()
+	if t.polling {
+		t.mu.Unlock()
+		return
+	}
+	t.polling = true
+	t.mu.Unlock()
+
+	go func() {
+		defer func() {
+			t.mu.Lock()
+			t.polling = false
+			t.mu.Unlock()
+		}()
+
+		timeout := t.StaleTimeout
+		if timeout <= 0 {
+			timeout = defaultOrderTrackerStaleTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err := t.Resync(ctx)
+		if err != nil {
+			t.reportErr(err)
+		}
+	}()
+}
+
+// Create Place order and track it. Order is tracked as new until the first update,
+// which may arrive before exchange replies.
+func (t *OrderTracker) Create(ctx context.Context, order *OrderRequest) (*TrackedOrder, error) {
+	res, err := t.c.CreateOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("bingx: empty response of order of %s", order.Symbol)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked := t.orders[res.OrderId]
+	if tracked == nil {
+		tracked = &TrackedOrder{
+			OrderId:      res.OrderId,
+			Status:       NewOrderStatus,
+			PositionSide: BothPositionSideType,
+		}
+		t.orders[res.OrderId] = tracked
+		t.changed()
+	}
+	// request fills what updates have not told yet
+	if tracked.Symbol == "" {
+		tracked.Symbol = order.Symbol
+		tracked.Side = order.Side
+		tracked.Type = order.Type
+		tracked.Price = order.Price
+		tracked.Quantity = order.Quantity
+		if order.PositionSide != "" {
+			tracked.PositionSide = order.PositionSide
+		}
+	}
+	if tracked.ClientOrderID == "" && order.ClientOrderID != "" {
+		tracked.ClientOrderID = order.ClientOrderID
+		t.clientIDs[order.ClientOrderID] = res.OrderId
+	}
+
+	return tracked.clone(), nil
+}
+
+// Track Load order placed elsewhere with GetOrderService and track it
+func (t *OrderTracker) Track(ctx context.Context, symbol string, orderId int64) (*TrackedOrder, error) {
+	res, err := t.c.GetOrder(ctx, symbol, orderId, "")
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("%w: %d", ErrOrderNotTracked, orderId)
+	}
+	if err := t.ApplySnapshot(res); err != nil {
+		return nil, err
+	}
+	return t.Order(res.OrderId)
+}
+
+// Resync Poll open orders with GetOrderService
+func (t *OrderTracker) Resync(ctx context.Context) error {
+	type key struct {
+		symbol  string
+		orderId int64
+	}
+
+	t.mu.Lock()
+	var open []key
+	for _, order := range t.orders {
+		if !order.Done() && order.Symbol != "" {
+			open = append(open, key{order.Symbol, order.OrderId})
+		}
+	}
+	t.mu.Unlock()
+
+	for _, k := range open {
+		res, err := t.c.GetOrder(ctx, k.symbol, k.orderId, "")
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			continue
+		}
+		if err := t.ApplySnapshot(res); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	t.updatedAt = time.Now()
+	t.mu.Unlock()
+
+	return nil
+}
+
+// ApplySnapshot Merge order returned by REST, snapshot older than current state is ignored
+func (t *OrderTracker) ApplySnapshot(res *GetOrderResponse) error {
+	p := &numberParser{optional: true}
+	price := p.parse("price", res.Price)
+	quantity := p.parse("quantity", res.OrigQuantity)
+	filledQty := p.parse("executed quantity", res.Quantity)
+	averagePrice := p.parse("average price", res.AveragePrice)
+	fee := p.parse("fee", res.Fee)
+	if p.err != nil {
+		return p.err
+	}
+	updateTime := res.UpdateTime
+	if updateTime == 0 {
+		updateTime = res.Time
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order := t.order(res.OrderId, res.ClientOrderID)
+	if !order.newer(filledQty, res.Status, updateTime) {
+		return nil
+	}
+	order.Symbol = res.Symbol
+	order.Side = res.Side
+	order.PositionSide = res.PositionSide
+	order.Type = res.OrderType
+	order.Status = res.Status
+	order.Price = price
+	order.Quantity = quantity
+	order.FilledQty = filledQty
+	order.AveragePrice = averagePrice
+	order.Fee = fee
+	order.UpdateTime = updateTime
+	t.changed()
+
+	return nil
+}
+
+// ApplyUpdate Merge order update of user data stream, updates older than current state are ignored
+func (t *OrderTracker) ApplyUpdate(update *WsOrder) error {
+	p := &numberParser{optional: true}
+	price := p.parse("price", update.Price)
+	quantity := p.parse("quantity", update.Quantity)
+	filledQty := p.parse("filled quantity", update.FilledQty)
+	lastFilledQty := p.parse("last filled quantity", update.LastFilledQty)
+	lastFilledPrice := p.parse("last filled price", update.LastFilledPrice)
+	averagePrice := p.parse("average price", update.AveragePrice)
+	fee := p.parse("fee", update.Fee)
+	realizedPnl := p.parse("realized pnl", update.RealizedPnl)
+	if p.err != nil {
+		return p.err
+	}
+	updateTime := int64(update.Timestamp)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.updatedAt = time.Now()
+	order := t.order(update.OrderId, update.ClientOrderID)
+
+	// fills are kept even when snapshot already reported them, fee of snapshot includes them then
+	var filledByFills, notionalByFills float64
+	for _, fill := range order.Fills {
+		filledByFills += fill.Quantity
+		notionalByFills += fill.Quantity * fill.Price
+	}
+	if update.Spec == TradeOrderSpecType && lastFilledQty > 0 && filledQty > filledByFills*(1+1e-9) {
+		if lastFilledPrice == 0 {
+			lastFilledPrice = fillPrice(averagePrice, filledQty, lastFilledQty, filledByFills, notionalByFills)
+		}
+		order.Fills = append(order.Fills, OrderFill{
+			Time:        updateTime,
+			Price:       lastFilledPrice,
+			Quantity:    lastFilledQty,
+			Fee:         fee,
+			RealizedPnl: realizedPnl,
+		})
+		if filledQty > order.FilledQty {
+			order.Fee += fee
+		}
+		t.changed()
+	}
+
+	if !order.newer(filledQty, update.Status, updateTime) {
+		return nil
+	}
+	order.Symbol = update.Symbol
+	order.Side = update.Side
+	order.PositionSide = update.PositionSide
+	order.Type = update.OrderType
+	order.Status = update.Status
+	order.Price = price
+	order.Quantity = quantity
+	order.FilledQty = filledQty
+	if averagePrice != 0 {
+		order.AveragePrice = averagePrice
+	}
+	order.UpdateTime = updateTime
+	t.changed()
+
+	return nil
+}
+
+// fillPrice derives price of trade from average prices of order before and after it. Average price is
+// the best estimate when earlier fills were missed.
+func fillPrice(averagePrice, filledQty, lastFilledQty, filledByFills, notionalByFills float64) float64 {
+	before := filledQty - lastFilledQty
+	if math.Abs(before-filledByFills) > filledQty*1e-9 {
+		return averagePrice
+	}
+	if price := (averagePrice*filledQty - notionalByFills) / lastFilledQty; price > 0 {
+		return price
+	}
+	return averagePrice
+}
+
+// order returns tracked order creating it when it is unknown
+func (t *OrderTracker) order(orderId int64, clientOrderID string) *TrackedOrder {
+	order := t.orders[orderId]
+	if order == nil {
+		order = &TrackedOrder{OrderId: orderId}
+		t.orders[orderId] = order
+	}
+	if clientOrderID != "" {
+		order.ClientOrderID = clientOrderID
+		t.clientIDs[clientOrderID] = orderId
+	}
+	return order
+}
+
+// changed wakes up waiters, must be called with lock held
+func (t *OrderTracker) changed() {
+	close(t.changedC)
+	t.changedC = make(chan struct{})
+}
+
+// Order Current state of order
+func (t *OrderTracker) Order(orderId int64) (*TrackedOrder, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order := t.orders[orderId]
+	if order == nil {
+		return nil, fmt.Errorf("%w: %d", ErrOrderNotTracked, orderId)
+	}
+	return order.clone(), nil
+}
+
+// OrderByClientID Current state of order with client order id
+func (t *OrderTracker) OrderByClientID(clientOrderID string) (*TrackedOrder, error) {
+	t.mu.Lock()
+	orderId, ok := t.clientIDs[clientOrderID]
+	t.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrOrderNotTracked, clientOrderID)
+	}
+	return t.Order(orderId)
+}
+
+// Orders Current state of all tracked orders, open only when openOnly is set
+func (t *OrderTracker) Orders(openOnly bool) []*TrackedOrder {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	res := make([]*TrackedOrder, 0, len(t.orders))
+	for _, order := range t.orders {
+		if !openOnly || !order.Done() {
+			res = append(res, order.clone())
+		}
+	}
+	return res
+}
+
+// Remove Stop tracking order, e.g. once it is done and processed
+func (t *OrderTracker) Remove(orderId int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order := t.orders[orderId]
+	if order == nil {
+		return
+	}
+	delete(t.orders, orderId)
+	if order.ClientOrderID != "" && t.clientIDs[order.ClientOrderID] == orderId {
+		delete(t.clientIDs, order.ClientOrderID)
+	}
+}
+
+// Wait Block until order reaches terminal status or ctx is done
+func (t *OrderTracker) Wait(ctx context.Context, orderId int64) (*TrackedOrder, error) {
+	for {
+		t.mu.Lock()
+		order := t.orders[orderId]
+		changedC := t.changedC
+		var res *TrackedOrder
+		if order != nil && order.Done() {
+			res = order.clone()
+		}
+		t.mu.Unlock()
+
+		if order == nil {
+			return nil, fmt.Errorf("%w: %d", ErrOrderNotTracked, orderId)
+		}
+		if res != nil {
+			return res, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changedC:
+		}
+	}
+}
+
+// WaitFilled Block until order is filled, returns ErrOrderNotFilled when it is canceled or expired
+func (t *OrderTracker) WaitFilled(ctx context.Context, orderId int64) (*TrackedOrder, error) {
+	order, err := t.Wait(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != FilledOrderStatus {
+		return order, fmt.Errorf("%w: %d is %s", ErrOrderNotFilled, orderId, order.Status)
+	}
+	return order, nil
+}
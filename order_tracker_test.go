@@ -0,0 +1,224 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type orderTrackerTestSuite struct {
+	serverTestSuite
+	tracker *OrderTracker
+}
+
+func TestOrderTracker(t *testing.T) {
+	suite.Run(t, new(orderTrackerTestSuite))
+}
+
+func (s *orderTrackerTestSuite) SetupTest() {
+	s.serverTestSuite.SetupTest()
+	s.tracker = s.client.NewOrderTracker()
+	s.server.SetPrice("BTC-USDT", 43000)
+	s.server.SetFeeRate(0.0002, 0.0005)
+}
+
+func (s *orderTrackerTestSuite) start() {
+	r := s.r()
+	r.NoError(s.tracker.Start(newContext(), s.server.ListenKey, func(err error) {
+		s.r().NoError(err)
+	}, WithWsEndpoint(s.server.UserWsURL())))
+	s.T().Cleanup(s.tracker.Stop)
+	r.NoError(s.server.WaitUserStream(5 * time.Second))
+}
+
+func (s *orderTrackerTestSuite) waitContext() context.Context {
+	ctx, cancel := context.WithTimeout(newContext(), 5*time.Second)
+	s.T().Cleanup(cancel)
+	return ctx
+}
+
+func (s *orderTrackerTestSuite) TestWaitFilled() {
+	r := s.r()
+	s.start()
+
+	order, err := s.tracker.Create(newContext(), &OrderRequest{
+		Symbol:        "BTC-USDT",
+		Type:          LimitOrderType,
+		Side:          BuySideType,
+		ClientOrderID: "entry",
+		Price:         42000,
+		Quantity:      0.1,
+	})
+	r.NoError(err)
+	r.Equal(0.1, order.RemainingQty())
+
+	s.server.SetPrice("BTC-USDT", 41900)
+	filled, err := s.tracker.WaitFilled(s.waitContext(), order.OrderId)
+	r.NoError(err)
+	r.Equal(FilledOrderStatus, filled.Status)
+	r.Equal(float64(42000), filled.AveragePrice)
+	r.Zero(filled.RemainingQty())
+	r.Len(filled.Fills, 1)
+	r.Equal(0.1, filled.Fills[0].Quantity)
+	r.InDelta(-0.84, filled.Fee, 1e-9)
+
+	byClientID, err := s.tracker.OrderByClientID("entry")
+	r.NoError(err)
+	r.Equal(order.OrderId, byClientID.OrderId)
+	r.Empty(s.tracker.Orders(true))
+}
+
+func (s *orderTrackerTestSuite) TestWaitCanceled() {
+	r := s.r()
+	s.start()
+
+	order, err := s.tracker.Create(newContext(), &OrderRequest{
+		Symbol:   "BTC-USDT",
+		Type:     LimitOrderType,
+		Side:     BuySideType,
+		Price:    42000,
+		Quantity: 0.1,
+	})
+	r.NoError(err)
+
+	_, err = s.client.CancelOrder(newContext(), "BTC-USDT", order.OrderId, "")
+	r.NoError(err)
+	canceled, err := s.tracker.WaitFilled(s.waitContext(), order.OrderId)
+	r.True(errors.Is(err, ErrOrderNotFilled), err)
+	r.Equal(CanceledOrderStatus, canceled.Status)
+
+	_, err = s.tracker.Wait(newContext(), 42)
+	r.True(errors.Is(err, ErrOrderNotTracked), err)
+}
+
+func (s *orderTrackerTestSuite) TestResync() {
+	r := s.r()
+	// no stream, state comes from polling only
+	order, err := s.tracker.Create(newContext(), &OrderRequest{
+		Symbol:   "BTC-USDT",
+		Type:     LimitOrderType,
+		Side:     SellSideType,
+		Price:    44000,
+		Quantity: 0.1,
+	})
+	r.NoError(err)
+
+	s.server.SetPrice("BTC-USDT", 44100)
+	r.NoError(s.tracker.Resync(newContext()))
+	filled, err := s.tracker.Order(order.OrderId)
+	r.NoError(err)
+	r.Equal(FilledOrderStatus, filled.Status)
+	r.Equal(0.1, filled.FilledQty)
+	r.Empty(filled.Fills)
+
+	tracked, err := s.client.NewOrderTracker().Track(newContext(), "BTC-USDT", order.OrderId)
+	r.NoError(err)
+	r.Equal(filled.Fee, tracked.Fee)
+}
+
+func (s *orderTrackerTestSuite) TestFillPrice() {
+	r := s.r()
+	update := func(orderId int64, filled, last, lastPrice, averagePrice string, time int) *WsOrder {
+		return &WsOrder{
+			Symbol:          "BTC-USDT",
+			Side:            BuySideType,
+			OrderType:       LimitOrderType,
+			Price:           "110",
+			AveragePrice:    averagePrice,
+			Quantity:        "3",
+			Status:          PartiallyFilledOrderStatus,
+			Spec:            TradeOrderSpecType,
+			Timestamp:       time,
+			OrderId:         orderId,
+			LastFilledQty:   last,
+			LastFilledPrice: lastPrice,
+			FilledQty:       filled,
+		}
+	}
+
+	// price of trade is derived from average prices when update does not report it
+	r.NoError(s.tracker.ApplyUpdate(update(1, "1", "1", "", "100", 10)))
+	r.NoError(s.tracker.ApplyUpdate(update(1, "3", "2", "", "106", 20)))
+	order, err := s.tracker.Order(1)
+	r.NoError(err)
+	r.Len(order.Fills, 2)
+	r.InDelta(100, order.Fills[0].Price, 1e-9)
+	r.InDelta(109, order.Fills[1].Price, 1e-9)
+	r.InDelta(106, order.AveragePrice, 1e-9)
+
+	r.NoError(s.tracker.ApplyUpdate(update(2, "1", "1", "100", "100", 10)))
+	r.NoError(s.tracker.ApplyUpdate(update(2, "2", "1", "104", "102", 20)))
+	order, err = s.tracker.Order(2)
+	r.NoError(err)
+	r.Equal(float64(104), order.Fills[1].Price)
+}
+
+func (s *orderTrackerTestSuite) TestMergeOrder() {
+	r := s.r()
+	update := func(spec OrderSpecType, status OrderStatus, filled, last string, time int) *WsOrder {
+		return &WsOrder{
+			Symbol:        "BTC-USDT",
+			Side:          BuySideType,
+			OrderType:     LimitOrderType,
+			Price:         "100",
+			AveragePrice:  "100",
+			Quantity:      "2",
+			Status:        status,
+			Spec:          spec,
+			Timestamp:     time,
+			OrderId:       1,
+			LastFilledQty: last,
+			FilledQty:     filled,
+			Fee:           "-0.01",
+		}
+	}
+
+	r.NoError(s.tracker.ApplyUpdate(update(TradeOrderSpecType, PartiallyFilledOrderStatus, "1", "1", 20)))
+	// late update and stale snapshot do not move state back
+	r.NoError(s.tracker.ApplyUpdate(update(NewOrderSpecType, NewOrderStatus, "0", "0", 10)))
+	r.NoError(s.tracker.ApplySnapshot(&GetOrderResponse{
+		Symbol:       "BTC-USDT",
+		Status:       NewOrderStatus,
+		Price:        "100",
+		OrigQuantity: "2",
+		Quantity:     "0",
+		OrderId:      1,
+		UpdateTime:   30,
+	}))
+	order, err := s.tracker.Order(1)
+	r.NoError(err)
+	r.Equal(PartiallyFilledOrderStatus, order.Status)
+	r.Equal(float64(1), order.RemainingQty())
+
+	// snapshot reports the second fill before its update
+	r.NoError(s.tracker.ApplySnapshot(&GetOrderResponse{
+		Symbol:       "BTC-USDT",
+		Status:       FilledOrderStatus,
+		Price:        "100",
+		AveragePrice: "100",
+		OrigQuantity: "2",
+		Quantity:     "2",
+		Fee:          "-0.02",
+		OrderId:      1,
+		UpdateTime:   40,
+	}))
+	r.NoError(s.tracker.ApplyUpdate(update(TradeOrderSpecType, FilledOrderStatus, "2", "1", 40)))
+	// duplicate is ignored
+	r.NoError(s.tracker.ApplyUpdate(update(TradeOrderSpecType, FilledOrderStatus, "2", "1", 40)))
+
+	order, err = s.tracker.Order(1)
+	r.NoError(err)
+	r.Equal(FilledOrderStatus, order.Status)
+	r.Len(order.Fills, 2)
+	r.InDelta(-0.02, order.Fee, 1e-9)
+	r.Equal(int64(40), order.UpdateTime)
+
+	s.tracker.Remove(1)
+	_, err = s.tracker.Order(1)
+	r.True(errors.Is(err, ErrOrderNotTracked), err)
+
+	r.Error(s.tracker.ApplyUpdate(&WsOrder{OrderId: 2, Price: "x"}))
+}
@@ -0,0 +1,419 @@
+package bingx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/magicaleks/go-bingx/internal/simulator"
+)
+
+// LiquidationClientOrderID client order id of simulated orders closing positions on liquidation
+const LiquidationClientOrderID = simulator.LiquidationClientOrderID
+
+// PaperClient Client whose orders, positions and balance are simulated against streamed prices.
+// Order, position and balance services of embedded Client are served in memory,
+// other requests e.g. market data are sent to exchange, so strategy code works with both:
+//
+//	client := bingx.NewClient(apiKey, secretKey)
+//	if paper {
+//		client = bingx.NewPaperClient(client).Balance(1000).Client
+//	}
+//
+// Positions are in cross margin mode, all of them are liquidated when equity
+// drops to maintenance margin.
+type PaperClient struct {
+	*Client
+	live doFunc
+
+	mu                   sync.Mutex
+	exchange             *simulator.Exchange
+	orderHandler         WsOrderUpdateHandler
+	accountUpdateHandler WsAccountUpdateHandler
+}
+
+// NewPaperClient Simulate trading of client, client itself is not changed
+func NewPaperClient(client *Client) *PaperClient {
+	c := *client
+	p := &PaperClient{
+		Client:   &c,
+		live:     client.do,
+		exchange: simulator.New(),
+	}
+	if p.live == nil {
+		p.live = func(req *http.Request) (*http.Response, error) {
+			return c.HTTPClient.Do(req)
+		}
+	}
+	c.do = p.do
+	return p
+}
+
+// Balance Set wallet balance in USDT
+func (p *PaperClient) Balance(balance float64) *PaperClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.exchange.Balance = balance
+	return p
+}
+
+// Fees Set fee rates of resting (maker) and immediately filled (taker) orders
+func (p *PaperClient) Fees(maker, taker float64) *PaperClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.exchange.MakerFee, p.exchange.TakerFee = maker, taker
+	return p
+}
+
+// Slippage Set price move against orders taking liquidity as share of price, e.g. 0.0005
+func (p *PaperClient) Slippage(rate float64) *PaperClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.exchange.Slippage = rate
+	return p
+}
+
+// Leverage Set leverage of symbol, default is 1
+func (p *PaperClient) Leverage(symbol string, leverage int) *PaperClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.exchange.Leverage[symbol] = leverage
+	return p
+}
+
+// MaintenanceMargin Set maintenance margin rate of position value, default is 0.4%
+func (p *PaperClient) MaintenanceMargin(rate float64) *PaperClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.exchange.MaintenanceMargin = rate
+	return p
+}
+
+// Clock Set source of order and event times, e.g. time of replayed data
+func (p *PaperClient) Clock(now func() time.Time) *PaperClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.exchange.Now = now
+	return p
+}
+
+// OrderUpdateHandler Handle order updates the same way as ones of user data stream.
+// Handler is called after request or price update which caused it and may call client.
+func (p *PaperClient) OrderUpdateHandler(handler WsOrderUpdateHandler) *PaperClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.orderHandler = handler
+	return p
+}
+
+// AccountUpdateHandler Handle balance and position changes caused by fills
+func (p *PaperClient) AccountUpdateHandler(handler WsAccountUpdateHandler) *PaperClient {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.accountUpdateHandler = handler
+	return p
+}
+
+// UpdatePrice Set price of symbol. Market orders are filled at it, resting limit orders
+// crossed by it are filled at their price and positions are liquidated if margin is not enough.
+func (p *PaperClient) UpdatePrice(symbol string, price float64) {
+	p.mu.Lock()
+	events := p.exchange.SetPrice(symbol, price)
+	p.mu.Unlock()
+
+	p.emit(events)
+}
+
+// ApplyFunding Charge funding of symbol positions at current price, longs pay shorts when rate is positive
+func (p *PaperClient) ApplyFunding(symbol string, rate float64) {
+	p.mu.Lock()
+	events := p.exchange.ApplyFunding(symbol, rate)
+	p.mu.Unlock()
+
+	p.emit(events)
+}
+
+// StreamPrices Update price of symbol by public trades until stream is closed
+func (p *PaperClient) StreamPrices(ctx context.Context, symbol string, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return WsTradeServe(ctx, symbol, func(event *WsTradeEvent) {
+		p.UpdatePrice(symbol, event.Price)
+	}, errHandler, opts...)
+}
+
+// do serves simulated endpoints and passes other requests to exchange
+func (p *PaperClient) do(req *http.Request) (*http.Response, error) {
+	params := req.URL.Query()
+
+	var data interface{}
+	var err error
+	var events []interface{}
+
+	p.mu.Lock()
+	switch req.Method + " " + req.URL.Path {
+	case "POST /openApi/swap/v2/trade/order":
+		data, events, err = p.createOrder(params)
+	case "DELETE /openApi/swap/v2/trade/order":
+		data, events, err = p.cancelOrder(params)
+	case "GET /openApi/swap/v2/trade/order":
+		data, err = p.getOrder(params)
+	case "DELETE /openApi/swap/v2/trade/allOpenOrders":
+		data, events = p.cancelAllOrders(params)
+	case "GET /openApi/swap/v2/trade/openOrders":
+		data = p.getOpenOrders(params)
+	case "GET /openApi/swap/v2/user/positions":
+		data = p.getPositions(params)
+	case "GET /openApi/swap/v2/user/balance":
+		data = map[string]*Balance{"balance": p.getBalance()}
+	default:
+		p.mu.Unlock()
+		return p.live(req)
+	}
+	p.mu.Unlock()
+
+	p.emit(events)
+
+	resp := map[string]interface{}{"code": 0, "msg": "", "data": data}
+	if simErr, ok := err.(*simulator.Error); ok {
+		resp = map[string]interface{}{"code": simErr.Code, "msg": simErr.Message}
+	}
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func (p *PaperClient) createOrder(params url.Values) (interface{}, []interface{}, error) {
+	o, err := simulator.ParseOrder(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	o, events, err := p.exchange.CreateOrder(o)
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]*GetOrderResponse{"order": paperOrderResponse(o)}, events, nil
+}
+
+func (p *PaperClient) cancelOrder(params url.Values) (interface{}, []interface{}, error) {
+	o, events, err := p.exchange.CancelOrder(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return map[string]*GetOrderResponse{"order": paperOrderResponse(o)}, events, nil
+}
+
+func (p *PaperClient) getOrder(params url.Values) (interface{}, error) {
+	o, err := p.exchange.FindOrder(params)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]*GetOrderResponse{"order": paperOrderResponse(o)}, nil
+}
+
+func (p *PaperClient) cancelAllOrders(params url.Values) (interface{}, []interface{}) {
+	canceled, events := p.exchange.CancelAllOrders(params.Get("symbol"), params.Get("type"))
+	res := &CancelAllOrdersResponse{Success: []CancelOrderResponse{}, Failed: []CancelOrderResponse{}}
+	for _, o := range canceled {
+		order := paperOrderResponse(o)
+		res.Success = append(res.Success, CancelOrderResponse{
+			Time:          int(order.Time),
+			Symbol:        order.Symbol,
+			Side:          order.Side,
+			OrderType:     order.OrderType,
+			PositionSide:  order.PositionSide,
+			CumQuote:      order.CumQuote,
+			Status:        order.Status,
+			StopPrice:     order.StopPrice,
+			Price:         order.Price,
+			OrigQty:       order.OrigQuantity,
+			AvgPrice:      order.AveragePrice,
+			ExecutedQty:   order.Quantity,
+			OrderId:       order.OrderId,
+			Profit:        order.Profit,
+			Commission:    order.Fee,
+			UpdateTime:    int(order.UpdateTime),
+			ClientOrderID: order.ClientOrderID,
+		})
+	}
+	return map[string]*CancelAllOrdersResponse{"order": res}, events
+}
+
+func (p *PaperClient) getOpenOrders(params url.Values) interface{} {
+	res := &GetOpenOrdersResponse{Orders: []*GetOrderResponse{}}
+	for _, o := range p.exchange.OpenOrders(params.Get("symbol")) {
+		res.Orders = append(res.Orders, paperOrderResponse(o))
+	}
+	return res
+}
+
+func (p *PaperClient) getPositions(params url.Values) interface{} {
+	res := []Position{}
+	equity := p.exchange.Equity()
+	for _, pos := range p.exchange.Positions(params.Get("symbol")) {
+		side := pos.PositionSide
+		if side == string(BothPositionSideType) {
+			side = string(LongPositionSideType)
+			if pos.Amount < 0 {
+				side = string(ShortPositionSideType)
+			}
+		}
+		markPrice := p.exchange.MarkPrice(pos)
+		margin := p.exchange.PositionMargin(pos)
+		unrealized := p.exchange.UnrealizedProfit(pos)
+		var riskRate, pnlRatio float64
+		if equity > 0 {
+			riskRate = p.exchange.MaintenanceMarginOf(pos) / equity
+		}
+		if margin > 0 {
+			pnlRatio = unrealized / margin
+		}
+		res = append(res, Position{
+			Symbol:           pos.Symbol,
+			PositionId:       pos.Key(),
+			PositionSide:     side,
+			PositionAmt:      formatFloat(math.Abs(pos.Amount)),
+			AvailableAmt:     formatFloat(math.Abs(pos.Amount)),
+			UnrealizedProfit: formatFloat(unrealized),
+			RealisedProfit:   formatFloat(pos.RealisedProfit),
+			InitialMargin:    formatFloat(margin),
+			AvgPrice:         formatFloat(pos.AvgPrice),
+			LiquidationPrice: p.exchange.LiquidationPrice(pos),
+			Leverage:         p.exchange.LeverageOf(pos.Symbol),
+			PositionValue:    formatFloat(math.Abs(pos.Amount) * markPrice),
+			MarkPrice:        formatFloat(markPrice),
+			RiskRate:         formatFloat(riskRate),
+			PnlRatio:         formatFloat(pnlRatio),
+		})
+	}
+	return &res
+}
+
+func (p *PaperClient) getBalance() *Balance {
+	account := p.exchange.Account()
+	return &Balance{
+		Asset:            simulator.Asset,
+		Balance:          formatFloat(account.Balance),
+		Equity:           formatFloat(account.Equity),
+		UnrealizedProfit: formatFloat(account.UnrealizedProfit),
+		RealisedProfit:   formatFloat(account.RealisedProfit),
+		AavailableMargin: formatFloat(account.AvailableMargin),
+		UsedMargin:       formatFloat(account.UsedMargin),
+		FreezedMargin:    formatFloat(account.FrozenMargin),
+	}
+}
+
+func paperOrderResponse(o *simulator.Order) *GetOrderResponse {
+	return &GetOrderResponse{
+		Time:          o.Time,
+		Symbol:        o.Symbol,
+		Side:          SideType(o.Side),
+		OrderType:     OrderType(o.Type),
+		PositionSide:  PositionSideType(o.PositionSide),
+		ReduceOnly:    o.ReduceOnly,
+		CumQuote:      formatFloat(o.ExecutedQty * o.AvgPrice),
+		Status:        OrderStatus(o.Status),
+		StopPrice:     "0",
+		Price:         formatFloat(o.Price),
+		OrigQuantity:  formatFloat(o.Quantity),
+		AveragePrice:  formatFloat(o.AvgPrice),
+		Quantity:      formatFloat(o.ExecutedQty),
+		OrderId:       o.OrderId,
+		Profit:        formatFloat(o.Profit),
+		Fee:           formatFloat(-o.Commission),
+		UpdateTime:    o.UpdateTime,
+		WorkingType:   ContractOrderWorkingType,
+		ClientOrderID: o.ClientOrderID,
+	}
+}
+
+func paperOrderUpdate(event *simulator.OrderEvent) *WsOrder {
+	o := &event.Order
+	return &WsOrder{
+		Symbol:          o.Symbol,
+		Side:            SideType(o.Side),
+		OrderType:       OrderType(o.Type),
+		PositionSide:    PositionSideType(o.PositionSide),
+		WorkingType:     ContractOrderWorkingType,
+		Price:           formatFloat(o.Price),
+		AveragePrice:    formatFloat(o.AvgPrice),
+		Quantity:        formatFloat(o.Quantity),
+		StopPrice:       "0",
+		Status:          OrderStatus(o.Status),
+		Spec:            OrderSpecType(event.Spec),
+		Timestamp:       int(o.UpdateTime),
+		OrderId:         o.OrderId,
+		ClientOrderID:   o.ClientOrderID,
+		LastFilledQty:   formatFloat(event.LastQty),
+		LastFilledPrice: formatFloat(event.LastPrice),
+		FilledQty:       formatFloat(o.ExecutedQty),
+		FeeAsset:        simulator.Asset,
+		Fee:             formatFloat(-event.Fee),
+		RealizedPnl:     formatFloat(event.RealizedPnl),
+	}
+}
+
+func paperAccountUpdate(event *simulator.AccountEvent) *WsAccountUpdateEvent {
+	pos := &event.Position
+	return &WsAccountUpdateEvent{
+		EventType: AccountUpdateEventType,
+		Time:      event.Time,
+		Update: &WsAccountUpdate{
+			Reason: event.Reason,
+			Balances: []*WsBalance{{
+				Asset:              simulator.Asset,
+				WalletBalance:      formatFloat(event.Balance),
+				CrossWalletBalance: formatFloat(event.Balance),
+				BalanceChange:      formatFloat(event.BalanceChange),
+			}},
+			Positions: []*WsPosition{{
+				Symbol:         pos.Symbol,
+				PositionSide:   PositionSideType(pos.PositionSide),
+				PositionAmt:    formatFloat(pos.Amount),
+				EntryPrice:     formatFloat(pos.AvgPrice),
+				UnrealizedPnl:  formatFloat(event.UnrealizedProfit),
+				MarginType:     "cross",
+				IsolatedWallet: "0",
+			}},
+		},
+	}
+}
+
+// emit passes events to handlers, called without lock so handlers may use client
+func (p *PaperClient) emit(events []interface{}) {
+	p.mu.Lock()
+	orderHandler, accountUpdateHandler := p.orderHandler, p.accountUpdateHandler
+	p.mu.Unlock()
+
+	for _, event := range events {
+		switch event := event.(type) {
+		case *simulator.OrderEvent:
+			if orderHandler != nil {
+				orderHandler(paperOrderUpdate(event))
+			}
+		case *simulator.AccountEvent:
+			if accountUpdateHandler != nil {
+				accountUpdateHandler(paperAccountUpdate(event))
+			}
+		}
+	}
+}
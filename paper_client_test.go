@@ -0,0 +1,350 @@
+package bingx
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/magicaleks/go-bingx/common"
+	"github.com/magicaleks/go-bingx/internal/simulator"
+	"github.com/stretchr/testify/suite"
+)
+
+type paperClientTestSuite struct {
+	baseTestSuite
+	paper    *PaperClient
+	orders   []*WsOrder
+	accounts []*WsAccountUpdateEvent
+}
+
+func TestPaperClient(t *testing.T) {
+	suite.Run(t, new(paperClientTestSuite))
+}
+
+func (s *paperClientTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.orders, s.accounts = nil, nil
+	s.paper = NewPaperClient(s.client.Client).
+		Balance(1000).
+		Leverage("BTC-USDT", 10).
+		OrderUpdateHandler(func(order *WsOrder) {
+			s.orders = append(s.orders, order)
+		}).
+		AccountUpdateHandler(func(event *WsAccountUpdateEvent) {
+			s.accounts = append(s.accounts, event)
+		})
+}
+
+func (s *paperClientTestSuite) createOrder(orderType OrderType, side SideType, price, quantity float64) (*CreateOrderResponse, error) {
+	return s.paper.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(orderType).
+		Side(side).
+		Price(price).
+		Quantity(quantity).
+		Do(newContext())
+}
+
+func (s *paperClientTestSuite) float(value string) float64 {
+	v, err := strconv.ParseFloat(value, 64)
+	s.r().NoError(err)
+	return v
+}
+
+func (s *paperClientTestSuite) balance() *Balance {
+	balance, err := s.paper.NewGetBalanceService().Do(newContext())
+	s.r().NoError(err)
+	return balance
+}
+
+func (s *paperClientTestSuite) positions() []Position {
+	positions, err := s.paper.NewGetOpenPositionsService().Do(newContext())
+	s.r().NoError(err)
+	return *positions
+}
+
+func (s *paperClientTestSuite) TestOrders() {
+	r := s.r()
+	_, err := s.createOrder(MarketOrderType, BuySideType, 0, 1)
+	r.Equal(int64(simulator.NoPriceCode), s.apiErrorCode(err))
+
+	s.paper.UpdatePrice("BTC-USDT", 100)
+	_, err = s.createOrder(MarketOrderType, BuySideType, 0, 10)
+	r.NoError(err)
+	r.InDelta(999.5, s.float(s.balance().Balance), 1e-9)
+
+	limit, err := s.createOrder(LimitOrderType, SellSideType, 110, 4)
+	r.NoError(err)
+	open, err := s.paper.NewGetOpenOrdersService().Symbol("BTC-USDT").Do(newContext())
+	r.NoError(err)
+	r.Len(open.Orders, 1)
+	r.Equal(limit.OrderId, open.Orders[0].OrderId)
+
+	s.paper.UpdatePrice("BTC-USDT", 109)
+	order, err := s.paper.NewGetOrderService().Symbol("BTC-USDT").OrderId(limit.OrderId).Do(newContext())
+	r.NoError(err)
+	r.Equal(NewOrderStatus, order.Status)
+
+	s.paper.UpdatePrice("BTC-USDT", 111)
+	order, err = s.paper.NewGetOrderService().Symbol("BTC-USDT").OrderId(limit.OrderId).Do(newContext())
+	r.NoError(err)
+	r.Equal(FilledOrderStatus, order.Status)
+	r.Equal("110", order.AveragePrice)
+	r.Equal("40", order.Profit)
+	r.InDelta(-0.088, s.float(order.Fee), 1e-9)
+
+	positions := s.positions()
+	r.Len(positions, 1)
+	r.Equal("LONG", positions[0].PositionSide)
+	r.Equal("6", positions[0].PositionAmt)
+	r.Equal("100", positions[0].AvgPrice)
+	r.Equal("66", positions[0].UnrealizedProfit)
+	r.Equal("60", positions[0].InitialMargin)
+	r.Equal(10, positions[0].Leverage)
+
+	balance := s.balance()
+	r.InDelta(999.5+40-0.088, s.float(balance.Balance), 1e-9)
+	r.InDelta(999.5+40-0.088+66, s.float(balance.Equity), 1e-9)
+	r.Equal("60", balance.UsedMargin)
+
+	var specs []OrderSpecType
+	for _, order := range s.orders {
+		specs = append(specs, order.Spec)
+	}
+	r.Equal([]OrderSpecType{NewOrderSpecType, TradeOrderSpecType, NewOrderSpecType, TradeOrderSpecType}, specs)
+	r.Equal("4", s.orders[3].LastFilledQty)
+	r.Equal("40", s.orders[3].RealizedPnl)
+	r.Len(s.accounts, 2)
+	r.Equal("6", s.accounts[1].Update.Positions[0].PositionAmt)
+}
+
+func (s *paperClientTestSuite) TestCancelOrders() {
+	r := s.r()
+	first, err := s.createOrder(LimitOrderType, BuySideType, 90, 1)
+	r.NoError(err)
+	_, err = s.paper.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(LimitOrderType).
+		Side(BuySideType).
+		ClientOrderID("second").
+		Price(91).
+		Quantity(1).
+		Do(newContext())
+	r.NoError(err)
+	_, err = s.createOrder(LimitOrderType, BuySideType, 92, 1)
+	r.NoError(err)
+	r.InDelta(27.3, s.float(s.balance().FreezedMargin), 1e-9)
+
+	canceled, err := s.paper.NewCancelOrderService().Symbol("BTC-USDT").OrderId(first.OrderId).Do(newContext())
+	r.NoError(err)
+	r.Equal(CanceledOrderStatus, canceled.Status)
+	_, err = s.paper.NewCancelOrderService().Symbol("BTC-USDT").OrderId(first.OrderId).Do(newContext())
+	r.Equal(int64(simulator.OrderNotExistCode), s.apiErrorCode(err))
+
+	canceled, err = s.paper.NewCancelOrderService().Symbol("BTC-USDT").ClientOrderId("second").Do(newContext())
+	r.NoError(err)
+	r.Equal("91", canceled.Price)
+
+	all, err := s.paper.NewCancelAllOrdersService().Symbol("BTC-USDT").Do(newContext())
+	r.NoError(err)
+	r.Len(all.Success, 1)
+	r.Equal("0", s.balance().FreezedMargin)
+	r.Equal(CanceledOrderSpecType, s.orders[len(s.orders)-1].Spec)
+}
+
+func (s *paperClientTestSuite) TestTimeInForce() {
+	r := s.r()
+	s.paper.UpdatePrice("BTC-USDT", 100)
+
+	create := func(timeInForce TimeInForceType, price float64) OrderStatus {
+		created, err := s.paper.CreateOrder(newContext(), &OrderRequest{
+			Symbol:      "BTC-USDT",
+			Type:        LimitOrderType,
+			Side:        SellSideType,
+			TimeInForce: timeInForce,
+			Price:       price,
+			Quantity:    1,
+		})
+		r.NoError(err)
+		order, err := s.paper.GetOrder(newContext(), "BTC-USDT", created.OrderId, "")
+		r.NoError(err)
+		return order.Status
+	}
+
+	r.Equal(ExpiredOrderStatus, create(PostOnlyTimeInForceType, 99))
+	r.Equal(ExpiredOrderSpecType, s.orders[len(s.orders)-1].Spec)
+	r.Equal(NewOrderStatus, create(PostOnlyTimeInForceType, 101))
+	r.Equal(ExpiredOrderStatus, create(FOKTimeInForceType, 101))
+	r.Equal(FilledOrderStatus, create(GTCTimeInForceType, 99))
+}
+
+func (s *paperClientTestSuite) TestRejectedOrders() {
+	r := s.r()
+	s.paper.UpdatePrice("BTC-USDT", 100)
+
+	_, err := s.createOrder(MarketOrderType, BuySideType, 0, 100)
+	r.Equal(int64(simulator.InsufficientMarginCode), s.apiErrorCode(err))
+	_, err = s.createOrder(LimitOrderType, BuySideType, 0, 1)
+	r.Equal(int64(simulator.InvalidParamsCode), s.apiErrorCode(err))
+	_, err = s.createOrder("STOP", BuySideType, 100, 1)
+	r.Equal(int64(simulator.InvalidParamsCode), s.apiErrorCode(err))
+	_, err = s.paper.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(MarketOrderType).
+		Side(SellSideType).
+		ReduceOnly().
+		Quantity(1).
+		Do(newContext())
+	r.Equal(int64(simulator.NoPositionCode), s.apiErrorCode(err))
+	r.Empty(s.orders)
+}
+
+func (s *paperClientTestSuite) TestReduceOnly() {
+	r := s.r()
+	s.paper.UpdatePrice("BTC-USDT", 100)
+	_, err := s.createOrder(MarketOrderType, BuySideType, 0, 1)
+	r.NoError(err)
+
+	// order larger than position is limited by it and keeps original quantity
+	res, err := s.paper.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(MarketOrderType).
+		Side(SellSideType).
+		ReduceOnly().
+		Quantity(3).
+		Do(newContext())
+	r.NoError(err)
+	order, err := s.paper.NewGetOrderService().Symbol("BTC-USDT").OrderId(res.OrderId).Do(newContext())
+	r.NoError(err)
+	r.Equal("3", order.OrigQuantity)
+	r.Equal("1", order.Quantity)
+	r.Equal(ExpiredOrderStatus, order.Status)
+	r.Empty(s.positions())
+
+	last := s.orders[len(s.orders)-2:]
+	r.Equal(PartiallyFilledOrderStatus, last[0].Status)
+	r.Equal("3", last[0].Quantity)
+	r.Equal(ExpiredOrderStatus, last[1].Status)
+}
+
+func (s *paperClientTestSuite) TestHedgePositions() {
+	r := s.r()
+	s.paper.UpdatePrice("BTC-USDT", 100)
+	create := func(side SideType, positionSide PositionSideType, quantity float64) error {
+		_, err := s.paper.NewCreateOrderService().
+			Symbol("BTC-USDT").
+			Type(MarketOrderType).
+			Side(side).
+			PositionSide(positionSide).
+			Quantity(quantity).
+			Do(newContext())
+		return err
+	}
+
+	r.NoError(create(BuySideType, LongPositionSideType, 2))
+	r.NoError(create(SellSideType, ShortPositionSideType, 1))
+	positions := s.positions()
+	r.Len(positions, 2)
+	r.Equal("LONG", positions[0].PositionSide)
+	r.Equal("SHORT", positions[1].PositionSide)
+
+	r.Equal(int64(simulator.NoPositionCode), s.apiErrorCode(create(SellSideType, LongPositionSideType, 3)))
+	r.NoError(create(SellSideType, LongPositionSideType, 2))
+	r.NoError(create(BuySideType, ShortPositionSideType, 1))
+	r.Empty(s.positions())
+}
+
+func (s *paperClientTestSuite) TestLiquidation() {
+	r := s.r()
+	s.paper.Balance(110)
+	s.paper.UpdatePrice("BTC-USDT", 100)
+	_, err := s.createOrder(MarketOrderType, BuySideType, 0, 10)
+	r.NoError(err)
+	_, err = s.createOrder(LimitOrderType, BuySideType, 50, 0.1)
+	r.NoError(err)
+
+	positions := s.positions()
+	r.Len(positions, 1)
+	// 109.5 + 10 * (price - 100) = 10 * price * 0.004
+	r.InDelta(890.5/9.96, positions[0].LiquidationPrice, 1e-9)
+
+	s.paper.UpdatePrice("BTC-USDT", 90)
+	r.Len(s.positions(), 1)
+
+	s.paper.UpdatePrice("BTC-USDT", 89)
+	r.Empty(s.positions())
+	r.Equal("0", s.balance().Balance)
+	open, err := s.paper.NewGetOpenOrdersService().Do(newContext())
+	r.NoError(err)
+	r.Empty(open.Orders)
+
+	last := s.orders[len(s.orders)-1]
+	r.Equal(LiquidationClientOrderID, last.ClientOrderID)
+	r.Equal(SellSideType, last.Side)
+	r.Equal(FilledOrderStatus, last.Status)
+	r.Equal("LIQUIDATION", s.accounts[len(s.accounts)-1].Update.Reason)
+}
+
+func (s *paperClientTestSuite) TestSlippageAndFunding() {
+	r := s.r()
+	s.paper.Slippage(0.01).Fees(0, 0)
+	s.paper.UpdatePrice("BTC-USDT", 100)
+	_, err := s.createOrder(MarketOrderType, SellSideType, 0, 2)
+	r.NoError(err)
+	r.Equal("99", s.positions()[0].AvgPrice)
+
+	// limit price bounds slippage
+	_, err = s.createOrder(LimitOrderType, SellSideType, 99.5, 1)
+	r.NoError(err)
+	r.Equal("99.5", s.orders[len(s.orders)-1].AveragePrice)
+
+	s.paper.ApplyFunding("BTC-USDT", 0.001)
+	funding := s.accounts[len(s.accounts)-1]
+	r.Equal("FUNDING_FEE", funding.Update.Reason)
+	r.Equal("0.3", funding.Update.Balances[0].BalanceChange)
+	r.Equal("1000.3", s.balance().Balance)
+}
+
+func (s *paperClientTestSuite) TestPassThrough() {
+	r := s.r()
+	s.mockDo([]byte(`{"code": 0, "msg": "", "data": {"serverTime": 1702719166000}}`), nil)
+	s.paper = NewPaperClient(s.client.Client)
+
+	serverTime, err := s.paper.NewGetServerTimeService().Do(newContext())
+	r.NoError(err)
+	r.Equal(int64(1702719166000), serverTime)
+	s.assertDo()
+}
+
+func (s *paperClientTestSuite) apiErrorCode(err error) int64 {
+	r := s.r()
+	r.Error(err)
+	apiErr, ok := err.(*common.APIError)
+	r.True(ok, err.Error())
+	return apiErr.Code
+}
+
+func (s *websocketServiceTestSuite) TestPaperClientStreamPrices() {
+	data := [][]byte{
+		[]byte(`{"code":0,"dataType":"BTC-USDT@trade","data":[{"p":"101","q":"1","T":1,"m":true}]}`),
+		[]byte(`{"code":0,"dataType":"BTC-USDT@trade","data":[{"p":"99","q":"1","T":2,"m":false}]}`),
+	}
+
+	paper := NewPaperClient(s.client.Client).Balance(1000)
+	paper.UpdatePrice("BTC-USDT", 105)
+	order, err := paper.NewCreateOrderService().Symbol("BTC-USDT").Type(LimitOrderType).Side(BuySideType).Price(100).Quantity(1).Do(newContext())
+	r := s.r()
+	r.NoError(err)
+
+	s.mockWsServe(data, nil)
+	defer s.assertWsServe()
+	stream, err := paper.StreamPrices(newContext(), "BTC-USDT", func(err error) {
+		s.r().NoError(err)
+	})
+	r.NoError(err)
+	r.NoError(stream.Close())
+
+	res, err := paper.NewGetOrderService().OrderId(order.OrderId).Do(newContext())
+	r.NoError(err)
+	r.Equal(FilledOrderStatus, res.Status)
+	r.Equal("100", res.AveragePrice)
+}
@@ -0,0 +1,604 @@
+package bingx
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPortfolioResyncInterval = 5 * time.Minute
+	defaultPortfolioAsset          = "USDT"
+)
+
+// PortfolioPosition Define position held in portfolio
+type PortfolioPosition struct {
+	Symbol       string
+	PositionSide PositionSideType
+	// Amount signed quantity, negative for short
+	Amount     float64
+	EntryPrice float64
+	// MarkPrice zero until mark price of symbol is known
+	MarkPrice     float64
+	UnrealizedPnl float64
+	Leverage      int
+	// Margin initial margin of position at entry price
+	Margin float64
+}
+
+// Notional Signed value of position at mark price, at entry price when mark price is unknown
+func (p *PortfolioPosition) Notional() float64 {
+	if p.MarkPrice > 0 {
+		return p.Amount * p.MarkPrice
+	}
+	return p.Amount * p.EntryPrice
+}
+
+// PortfolioState Define consistent view of account
+type PortfolioState struct {
+	// Balance wallet balance, realized profit and fees included
+	Balance       float64
+	UnrealizedPnl float64
+	Equity        float64
+	// UsedMargin initial margin of positions
+	UsedMargin float64
+	// FreezedMargin margin of open orders as of the last snapshot
+	FreezedMargin float64
+	Positions     []PortfolioPosition
+	// SnapshotTime time of the last REST snapshot
+	SnapshotTime time.Time
+	// UpdateTime time of the last applied change
+	UpdateTime time.Time
+}
+
+// MarginUsage Used margin as share of equity
+func (s *PortfolioState) MarginUsage() float64 {
+	if s.Equity <= 0 {
+		if s.UsedMargin > 0 {
+			return math.Inf(1)
+		}
+		return 0
+	}
+	return s.UsedMargin / s.Equity
+}
+
+// Exposure Signed notional of symbol summed over position sides
+func (s *PortfolioState) Exposure(symbol string) float64 {
+	var res float64
+	for i := range s.Positions {
+		if s.Positions[i].Symbol == symbol {
+			res += s.Positions[i].Notional()
+		}
+	}
+	return res
+}
+
+type portfolioPositionKey struct {
+	symbol       string
+	positionSide PositionSideType
+}
+
+// portfolioUpdate parsed account update, balance is NaN when update does not report it
+type portfolioUpdate struct {
+	balance   float64
+	positions []portfolioPositionUpdate
+}
+
+type portfolioPositionUpdate struct {
+	pos        *WsPosition
+	amount     float64
+	entryPrice float64
+	unrealized float64
+}
+
+// Portfolio Local balance and positions of account loaded once with REST and kept up to date with
+// account updates of user data stream and mark prices. It is loaded again every ResyncInterval
+// and after reconnect to correct drift.
+type Portfolio struct {
+	// ResyncInterval period of REST snapshots, zero disables them
+	ResyncInterval time.Duration
+	// Asset balance of which is tracked
+	Asset string
+
+	c *Client
+
+	mu            sync.RWMutex
+	balance       float64
+	freezedMargin float64
+	positions     map[portfolioPositionKey]*PortfolioPosition
+	markPrices    map[string]float64
+	leverages     map[string]int
+	snapshotTime  time.Time
+	updateTime    time.Time
+	resyncing     bool
+	// account updates received while snapshots are loading, they are applied again over snapshot
+	snapshots int
+	received  []*portfolioUpdate
+
+	errHandler ErrHandler
+	stream     *Stream
+	stopC      chan struct{}
+	stopOnce   sync.Once
+	market     *MarketStream
+	subscribed map[string]bool
+}
+
+func (c *Client) NewPortfolio() *Portfolio {
+	return &Portfolio{
+		ResyncInterval: defaultPortfolioResyncInterval,
+		Asset:          defaultPortfolioAsset,
+		c:              c,
+		positions:      map[portfolioPositionKey]*PortfolioPosition{},
+		markPrices:     map[string]float64{},
+		leverages:      map[string]int{},
+		stopC:          make(chan struct{}),
+		subscribed:     map[string]bool{},
+	}
+}
+
+// Start Load snapshot and apply user data events of listen key until ctx is done or Stop is called
+func (p *Portfolio) Start(ctx context.Context, listenKey string, errHandler ErrHandler, opts ...WsOption) error {
+	p.errHandler = errHandler
+
+	err := p.Resync(ctx)
+	if err != nil {
+		return err
+	}
+
+	// resync after reconnect, updates of the gap are lost
+	onGap := newWsConfig("", opts...).OnGap
+	opts = append(opts, WithWsGapHandler(func(gap WsGap) {
+		p.resyncInBackground()
+		if onGap != nil {
+			onGap(gap)
+		}
+	}))
+
+	stream, err := NewUserDataStream(listenKey).
+		AccountUpdateHandler(p.handleAccountUpdate).
+		AccountConfigUpdateHandler(p.ApplyAccountConfigUpdate).
+		Serve(ctx, errHandler, opts...)
+	if err != nil {
+		return err
+	}
+
+	p.stream = stream
+	go p.watch()
+
+	return nil
+}
+
+// StreamMarkPrices Recompute unrealized profit from mark price streams of symbols with positions.
+// Symbols are subscribed as positions appear, call it after Start.
+func (p *Portfolio) StreamMarkPrices(errHandler ErrHandler, opts ...WsOption) error {
+	market := NewMarketStream(errHandler, opts...)
+
+	p.mu.Lock()
+	p.market = market
+	symbols := p.unsubscribedSymbols()
+	p.mu.Unlock()
+
+	for _, symbol := range symbols {
+		err := market.SubscribeMarkPrice(symbol, p.handleMarkPrice)
+		if err != nil {
+			return err
+		}
+	}
+	return market.Start()
+}
+
+// Stop Close streams, safe to call many times
+func (p *Portfolio) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stopC)
+	})
+	if p.stream != nil {
+		p.stream.Close()
+	}
+
+	p.mu.RLock()
+	market := p.market
+	p.mu.RUnlock()
+	if market != nil {
+		market.Stop()
+	}
+}
+
+// Done Closed when user data stream is stopped
+func (p *Portfolio) Done() <-chan struct{} {
+	if p.stream == nil {
+		return nil
+	}
+	return p.stream.Done()
+}
+
+func (p *Portfolio) reportErr(err error) {
+	if p.errHandler != nil {
+		p.errHandler(err)
+	}
+}
+
+func (p *Portfolio) watch() {
+	if p.ResyncInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.ResyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stream.Done():
+			return
+		case <-p.stopC:
+			return
+		case <-ticker.C:
+			p.resyncInBackground()
+		}
+	}
+}
+
+func (p *Portfolio) resyncInBackground() {
+	p.mu.Lock()
+	if p.resyncing {
+		p.mu.Unlock()
+		return
+	}
+	p.resyncing = true
+	p.mu.Unlock()
+
+	go func() {
+		defer func() {
+			p.mu.Lock()
+			p.resyncing = false
+			p.mu.Unlock()
+		}()
+
+		timeout := p.ResyncInterval
+		if timeout <= 0 {
+			timeout = defaultPortfolioResyncInterval
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err := p.Resync(ctx)
+		if err != nil {
+			p.reportErr(err)
+		}
+	}()
+}
+
+// Resync Replace balance and positions with REST snapshot. Account updates received while
+// snapshot is loading are applied again over it, so snapshot never overwrites newer changes.
+func (p *Portfolio) Resync(ctx context.Context) error {
+	p.mu.Lock()
+	p.snapshots++
+	received := len(p.received)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.snapshots--
+		if p.snapshots == 0 {
+			p.received = nil
+		}
+		p.mu.Unlock()
+	}()
+
+	balance, err := p.c.GetBalance(ctx)
+	if err != nil {
+		return err
+	}
+	positions, err := p.c.GetOpenPositions(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	parser := &numberParser{optional: true}
+	walletBalance := 0.0
+	freezedMargin := 0.0
+	if balance != nil {
+		walletBalance = parser.parse("balance", balance.Balance)
+		freezedMargin = parser.parse("freezed margin", balance.FreezedMargin)
+	}
+	res := map[portfolioPositionKey]*PortfolioPosition{}
+	markPrices := map[string]float64{}
+	leverages := map[string]int{}
+	for _, position := range positions {
+		side := PositionSideType(position.PositionSide)
+		amount := math.Abs(parser.parse("position amount", position.PositionAmt))
+		if side == ShortPositionSideType {
+			amount = -amount
+		}
+		pos := &PortfolioPosition{
+			Symbol:        position.Symbol,
+			PositionSide:  side,
+			Amount:        amount,
+			EntryPrice:    parser.parse("average price", position.AvgPrice),
+			UnrealizedPnl: parser.parse("unrealized profit", position.UnrealizedProfit),
+			Leverage:      position.Leverage,
+		}
+		if mark := parser.parse("mark price", position.MarkPrice); mark > 0 {
+			markPrices[position.Symbol] = mark
+		}
+		if position.Leverage > 0 {
+			leverages[position.Symbol] = position.Leverage
+		}
+		if amount != 0 {
+			res[portfolioPositionKey{pos.Symbol, pos.PositionSide}] = pos
+		}
+	}
+	if parser.err != nil {
+		return parser.err
+	}
+
+	p.mu.Lock()
+	p.balance = walletBalance
+	p.freezedMargin = freezedMargin
+	p.positions = res
+	for symbol, mark := range markPrices {
+		p.markPrices[symbol] = mark
+	}
+	for symbol, leverage := range leverages {
+		p.leverages[symbol] = leverage
+	}
+	for _, pos := range p.positions {
+		p.revalue(pos)
+	}
+	for _, update := range p.received[received:] {
+		p.applyAccountUpdate(update)
+	}
+	p.snapshotTime = time.Now()
+	p.updateTime = p.snapshotTime
+	symbols := p.unsubscribedSymbols()
+	p.mu.Unlock()
+
+	p.subscribe(symbols)
+
+	return nil
+}
+
+func (p *Portfolio) handleAccountUpdate(event *WsAccountUpdateEvent) {
+	err := p.ApplyAccountUpdate(event)
+	if err != nil {
+		p.reportErr(err)
+	}
+}
+
+// ApplyAccountUpdate Merge balance and positions of account update
+func (p *Portfolio) ApplyAccountUpdate(event *WsAccountUpdateEvent) error {
+	if event == nil || event.Update == nil {
+		return nil
+	}
+
+	parser := &numberParser{optional: true}
+	update := &portfolioUpdate{balance: math.NaN()}
+	for _, b := range event.Update.Balances {
+		if strings.EqualFold(b.Asset, p.Asset) {
+			update.balance = parser.parse("wallet balance", b.WalletBalance)
+		}
+	}
+	update.positions = make([]portfolioPositionUpdate, 0, len(event.Update.Positions))
+	for _, pos := range event.Update.Positions {
+		positionUpdate := portfolioPositionUpdate{
+			pos:        pos,
+			amount:     parser.parse("position amount", pos.PositionAmt),
+			entryPrice: parser.parse("entry price", pos.EntryPrice),
+			unrealized: parser.parse("unrealized profit", pos.UnrealizedPnl),
+		}
+		if pos.PositionSide == ShortPositionSideType && positionUpdate.amount > 0 {
+			positionUpdate.amount = -positionUpdate.amount
+		}
+		update.positions = append(update.positions, positionUpdate)
+	}
+	if parser.err != nil {
+		return parser.err
+	}
+
+	p.mu.Lock()
+	resync := p.applyAccountUpdate(update)
+	if p.snapshots > 0 {
+		p.received = append(p.received, update)
+	}
+	p.updateTime = time.Now()
+	symbols := p.unsubscribedSymbols()
+	p.mu.Unlock()
+
+	p.subscribe(symbols)
+	if resync {
+		p.resyncInBackground()
+	}
+
+	return nil
+}
+
+// applyAccountUpdate merges update into state and reports whether leverage of any its position
+// is unknown, must be called with lock held
+func (p *Portfolio) applyAccountUpdate(update *portfolioUpdate) bool {
+	if !math.IsNaN(update.balance) {
+		p.balance = update.balance
+	}
+	// leverage is known from snapshots only
+	resync := false
+	for _, update := range update.positions {
+		side := update.pos.PositionSide
+		if side == "" {
+			side = BothPositionSideType
+		}
+		if side == BothPositionSideType {
+			// one way position is reported by REST with side of its direction
+			delete(p.positions, portfolioPositionKey{update.pos.Symbol, LongPositionSideType})
+			delete(p.positions, portfolioPositionKey{update.pos.Symbol, ShortPositionSideType})
+		} else {
+			delete(p.positions, portfolioPositionKey{update.pos.Symbol, BothPositionSideType})
+		}
+		key := portfolioPositionKey{update.pos.Symbol, side}
+		if update.amount == 0 {
+			delete(p.positions, key)
+			continue
+		}
+		pos := &PortfolioPosition{
+			Symbol:        update.pos.Symbol,
+			PositionSide:  side,
+			Amount:        update.amount,
+			EntryPrice:    update.entryPrice,
+			UnrealizedPnl: update.unrealized,
+		}
+		p.revalue(pos)
+		p.positions[key] = pos
+		if p.leverages[pos.Symbol] == 0 {
+			resync = true
+		}
+	}
+	return resync
+}
+
+// ApplyAccountConfigUpdate Update leverage of symbol
+func (p *Portfolio) ApplyAccountConfigUpdate(event *WsAccountConfigUpdateEvent) {
+	if event == nil || event.Config == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	leverage := event.Config.LongLeverage
+	if event.Config.ShortLeverage > leverage {
+		leverage = event.Config.ShortLeverage
+	}
+	if leverage <= 0 {
+		return
+	}
+	p.leverages[event.Config.Symbol] = leverage
+	for _, pos := range p.positions {
+		if pos.Symbol == event.Config.Symbol {
+			p.revalue(pos)
+		}
+	}
+	p.updateTime = time.Now()
+}
+
+func (p *Portfolio) handleMarkPrice(event *WsMarkPriceEvent) {
+	p.UpdateMarkPrice(event.Symbol, event.MarkPrice)
+}
+
+// UpdateMarkPrice Recompute unrealized profit of symbol positions
+func (p *Portfolio) UpdateMarkPrice(symbol string, price float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.markPrices[symbol] = price
+	for _, pos := range p.positions {
+		if pos.Symbol == symbol {
+			p.revalue(pos)
+		}
+	}
+	p.updateTime = time.Now()
+}
+
+// revalue recomputes mark price dependent fields, must be called with lock held
+func (p *Portfolio) revalue(pos *PortfolioPosition) {
+	if leverage := p.leverages[pos.Symbol]; leverage > 0 {
+		pos.Leverage = leverage
+	}
+	if pos.Leverage <= 0 {
+		pos.Leverage = 1
+	}
+	pos.Margin = math.Abs(pos.Amount) * pos.EntryPrice / float64(pos.Leverage)
+	if mark, ok := p.markPrices[pos.Symbol]; ok {
+		pos.MarkPrice = mark
+		pos.UnrealizedPnl = (mark - pos.EntryPrice) * pos.Amount
+	}
+}
+
+// unsubscribedSymbols symbols with positions mark price of which is not streamed yet,
+// they are marked subscribed, must be called with lock held
+func (p *Portfolio) unsubscribedSymbols() []string {
+	if p.market == nil {
+		return nil
+	}
+	var res []string
+	for _, pos := range p.positions {
+		if !p.subscribed[pos.Symbol] {
+			p.subscribed[pos.Symbol] = true
+			res = append(res, pos.Symbol)
+		}
+	}
+	return res
+}
+
+// subscribe adds mark price subscriptions without blocking caller
+func (p *Portfolio) subscribe(symbols []string) {
+	if len(symbols) == 0 {
+		return
+	}
+
+	p.mu.RLock()
+	market := p.market
+	p.mu.RUnlock()
+
+	go func() {
+		for _, symbol := range symbols {
+			err := market.SubscribeMarkPrice(symbol, p.handleMarkPrice)
+			if err != nil {
+				p.reportErr(err)
+				p.mu.Lock()
+				delete(p.subscribed, symbol)
+				p.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// State Consistent copy of balance and positions, positions are sorted by symbol and side
+func (p *Portfolio) State() *PortfolioState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	res := &PortfolioState{
+		Balance:       p.balance,
+		FreezedMargin: p.freezedMargin,
+		Positions:     make([]PortfolioPosition, 0, len(p.positions)),
+		SnapshotTime:  p.snapshotTime,
+		UpdateTime:    p.updateTime,
+	}
+	for _, pos := range p.positions {
+		res.UnrealizedPnl += pos.UnrealizedPnl
+		res.UsedMargin += pos.Margin
+		res.Positions = append(res.Positions, *pos)
+	}
+	res.Equity = res.Balance + res.UnrealizedPnl
+	sort.Slice(res.Positions, func(i, j int) bool {
+		if res.Positions[i].Symbol != res.Positions[j].Symbol {
+			return res.Positions[i].Symbol < res.Positions[j].Symbol
+		}
+		return res.Positions[i].PositionSide < res.Positions[j].PositionSide
+	})
+	return res
+}
+
+// MarkPrice Last mark price of symbol from stream or snapshot
+func (p *Portfolio) MarkPrice(symbol string) (float64, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	price, ok := p.markPrices[symbol]
+	return price, ok
+}
+
+// Equity Balance with unrealized profit
+func (p *Portfolio) Equity() float64 {
+	return p.State().Equity
+}
+
+// MarginUsage Used margin as share of equity
+func (p *Portfolio) MarginUsage() float64 {
+	return p.State().MarginUsage()
+}
+
+// Exposure Signed notional of symbol summed over position sides
+func (p *Portfolio) Exposure(symbol string) float64 {
+	return p.State().Exposure(symbol)
+}
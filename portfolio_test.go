@@ -0,0 +1,182 @@
+package bingx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/magicaleks/go-bingx/bingxtest"
+	"github.com/stretchr/testify/suite"
+)
+
+type portfolioTestSuite struct {
+	serverTestSuite
+	portfolio *Portfolio
+}
+
+func TestPortfolio(t *testing.T) {
+	suite.Run(t, new(portfolioTestSuite))
+}
+
+func (s *portfolioTestSuite) SetupTest() {
+	s.serverTestSuite.SetupTest()
+	s.portfolio = s.client.NewPortfolio()
+	s.server.SetBalance(1000)
+	s.server.SetLeverage("BTC-USDT", 10)
+	s.server.SetFeeRate(0, 0)
+}
+
+// eventually waits until state of portfolio satisfies condition
+func (s *portfolioTestSuite) eventually(portfolio *Portfolio, condition func(state *PortfolioState) bool) *PortfolioState {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		state := portfolio.State()
+		if condition(state) {
+			return state
+		}
+		if time.Now().After(deadline) {
+			s.FailNow("portfolio is not updated", "%+v", state)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (s *portfolioTestSuite) TestSnapshot() {
+	r := s.r()
+	s.server.SetPrice("BTC-USDT", 110)
+	s.server.SetPosition(bingxtest.Position{Symbol: "BTC-USDT", PositionSide: "BOTH", Amount: -2, AvgPrice: 100})
+
+	r.NoError(s.portfolio.Resync(newContext()))
+	state := s.portfolio.State()
+	r.Equal(float64(1000), state.Balance)
+	r.Len(state.Positions, 1)
+	r.Equal(ShortPositionSideType, state.Positions[0].PositionSide)
+	r.Equal(float64(-2), state.Positions[0].Amount)
+	r.Equal(float64(110), state.Positions[0].MarkPrice)
+	r.Equal(float64(-20), state.UnrealizedPnl)
+	r.Equal(float64(980), state.Equity)
+	r.Equal(float64(20), state.UsedMargin)
+	r.InDelta(20.0/980, state.MarginUsage(), 1e-9)
+	r.Equal(float64(-220), state.Exposure("BTC-USDT"))
+
+	s.portfolio.UpdateMarkPrice("BTC-USDT", 90)
+	r.Equal(float64(1020), s.portfolio.Equity())
+	r.Equal(float64(-180), s.portfolio.Exposure("BTC-USDT"))
+	r.Zero(s.portfolio.Exposure("ETH-USDT"))
+}
+
+func (s *portfolioTestSuite) TestResyncKeepsNewerUpdates() {
+	r := s.r()
+	s.server.SetPrice("BTC-USDT", 100)
+	s.server.SetPosition(bingxtest.Position{Symbol: "BTC-USDT", PositionSide: "BOTH", Amount: 2, AvgPrice: 100})
+	r.NoError(s.portfolio.Resync(newContext()))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	s.client.do = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/openApi/swap/v2/user/balance" {
+			close(started)
+			<-release
+		}
+		return http.DefaultClient.Do(req)
+	}
+
+	errC := make(chan error)
+	go func() {
+		errC <- s.portfolio.Resync(newContext())
+	}()
+	<-started
+	// update arrives while snapshot taken before it is loading
+	r.NoError(s.portfolio.ApplyAccountUpdate(&WsAccountUpdateEvent{Update: &WsAccountUpdate{
+		Balances:  []*WsBalance{{Asset: "USDT", WalletBalance: "900"}},
+		Positions: []*WsPosition{{Symbol: "BTC-USDT", PositionSide: BothPositionSideType, PositionAmt: "5", EntryPrice: "100"}},
+	}}))
+	close(release)
+	r.NoError(<-errC)
+
+	state := s.portfolio.State()
+	r.Equal(float64(900), state.Balance)
+	r.Len(state.Positions, 1)
+	r.Equal(float64(5), state.Positions[0].Amount)
+	r.Equal(10, state.Positions[0].Leverage)
+	r.Empty(s.portfolio.received)
+
+	// updates are not kept once no snapshot is loading
+	s.client.do = nil
+	r.NoError(s.portfolio.Resync(newContext()))
+	r.Equal(float64(2), s.portfolio.State().Positions[0].Amount)
+}
+
+func (s *portfolioTestSuite) TestStreams() {
+	r := s.r()
+	s.server.SetPrice("BTC-USDT", 100)
+	errHandler := func(err error) {
+		s.r().NoError(err)
+	}
+
+	r.NoError(s.portfolio.Start(newContext(), s.server.ListenKey, errHandler, WithWsEndpoint(s.server.UserWsURL())))
+	defer s.portfolio.Stop()
+	r.NoError(s.portfolio.StreamMarkPrices(errHandler, WithWsEndpoint(s.server.WsURL)))
+	r.NoError(s.server.WaitUserStream(5 * time.Second))
+	r.Empty(s.portfolio.State().Positions)
+
+	_, err := s.client.CreateOrder(newContext(), &OrderRequest{Symbol: "BTC-USDT", Type: MarketOrderType, Side: BuySideType, Quantity: 5})
+	r.NoError(err)
+	state := s.eventually(s.portfolio, func(state *PortfolioState) bool {
+		return len(state.Positions) == 1
+	})
+	r.Equal(float64(5), state.Positions[0].Amount)
+	r.Equal(BothPositionSideType, state.Positions[0].PositionSide)
+	// leverage of new position is loaded with snapshot
+	s.eventually(s.portfolio, func(state *PortfolioState) bool {
+		return state.UsedMargin == 50
+	})
+
+	// mark price stream is subscribed once position appears
+	r.NoError(s.server.WaitSubscription("BTC-USDT@markPrice", 5*time.Second))
+	r.NoError(s.server.Publish("BTC-USDT@markPrice", map[string]interface{}{"e": "markPriceUpdate", "E": 1, "s": "BTC-USDT", "p": "104"}))
+	state = s.eventually(s.portfolio, func(state *PortfolioState) bool {
+		return state.UnrealizedPnl == 20
+	})
+	r.Equal(float64(1020), state.Equity)
+
+	_, err = s.client.CreateOrder(newContext(), &OrderRequest{Symbol: "BTC-USDT", Type: MarketOrderType, Side: SellSideType, Quantity: 5})
+	r.NoError(err)
+	s.eventually(s.portfolio, func(state *PortfolioState) bool {
+		return len(state.Positions) == 0
+	})
+
+	// snapshot corrects drift
+	s.server.SetBalance(2000)
+	r.NoError(s.portfolio.Resync(newContext()))
+	r.Equal(float64(2000), s.portfolio.Equity())
+}
+
+func (s *portfolioTestSuite) TestPaperClient() {
+	r := s.r()
+	paper := NewPaperClient(s.client).Balance(1000).Leverage("BTC-USDT", 10).Fees(0, 0)
+	portfolio := paper.NewPortfolio()
+	paper.AccountUpdateHandler(func(event *WsAccountUpdateEvent) {
+		s.r().NoError(portfolio.ApplyAccountUpdate(event))
+	})
+	r.NoError(portfolio.Resync(newContext()))
+
+	paper.UpdatePrice("BTC-USDT", 100)
+	_, err := paper.CreateOrder(newContext(), &OrderRequest{Symbol: "BTC-USDT", Type: MarketOrderType, Side: SellSideType, Quantity: 3})
+	r.NoError(err)
+	paper.UpdatePrice("BTC-USDT", 95)
+	portfolio.UpdateMarkPrice("BTC-USDT", 95)
+
+	s.eventually(portfolio, func(state *PortfolioState) bool {
+		return state.Positions[0].Leverage == 10
+	})
+	balance, err := paper.GetBalance(newContext())
+	r.NoError(err)
+	r.Equal(balance.Equity, formatFloat(portfolio.Equity()))
+	r.Equal(balance.UsedMargin, formatFloat(portfolio.State().UsedMargin))
+	r.Equal(float64(-285), portfolio.Exposure("BTC-USDT"))
+
+	portfolio.ApplyAccountConfigUpdate(&WsAccountConfigUpdateEvent{Config: &WsAccountConfig{Symbol: "BTC-USDT", LongLeverage: 20, ShortLeverage: 20}})
+	r.Equal(float64(15), portfolio.State().UsedMargin)
+	r.Error(portfolio.ApplyAccountUpdate(&WsAccountUpdateEvent{Update: &WsAccountUpdate{Positions: []*WsPosition{{Symbol: "BTC-USDT", PositionAmt: "x"}}}}))
+	r.Empty(s.server.Requests())
+}
@@ -1 +1,54 @@
 package bingx
+
+import (
+	"testing"
+
+	"github.com/magicaleks/go-bingx/bingxtest"
+	"github.com/stretchr/testify/suite"
+)
+
+type positionsServiceTestSuite struct {
+	serverTestSuite
+}
+
+func TestPositionsService(t *testing.T) {
+	suite.Run(t, new(positionsServiceTestSuite))
+}
+
+func (s *positionsServiceTestSuite) TestGetOpenPositions() {
+	r := s.r()
+	s.server.SetBalance(20000)
+	s.server.SetLeverage("BTC-USDT", 5)
+	s.server.SetPrice("BTC-USDT", 40000)
+	s.server.SetPosition(bingxtest.Position{Symbol: "ETH-USDT", PositionSide: "BOTH", Amount: -2, AvgPrice: 2000})
+
+	_, err := s.client.NewCreateOrderService().Symbol("BTC-USDT").Type(MarketOrderType).Side(BuySideType).Quantity(0.5).Do(newContext())
+	r.NoError(err)
+	_, err = s.client.NewCreateOrderService().Symbol("BTC-USDT").Type(MarketOrderType).Side(BuySideType).Quantity(0.5).Do(newContext())
+	r.NoError(err)
+	s.server.SetPrice("BTC-USDT", 39000)
+
+	positions, err := s.client.NewGetOpenPositionsService().Do(newContext())
+	r.NoError(err)
+	r.Len(*positions, 2)
+	btc := (*positions)[0]
+	r.Equal("BTC-USDT", btc.Symbol)
+	r.Equal("LONG", btc.PositionSide)
+	r.Equal("1", btc.PositionAmt)
+	r.Equal("40000", btc.AvgPrice)
+	r.Equal("-1000", btc.UnrealizedProfit)
+	r.Equal("8000", btc.InitialMargin)
+	r.Equal(5, btc.Leverage)
+	r.Equal("SHORT", (*positions)[1].PositionSide)
+	r.Equal("2", (*positions)[1].PositionAmt)
+
+	positions, err = s.client.NewGetOpenPositionsService().Symbol("ETH-USDT").Do(newContext())
+	r.NoError(err)
+	r.Len(*positions, 1)
+
+	_, err = s.client.NewCreateOrderService().Symbol("BTC-USDT").Type(MarketOrderType).Side(SellSideType).ReduceOnly().Quantity(2).Do(newContext())
+	r.NoError(err)
+	positions, err = s.client.NewGetOpenPositionsService().Symbol("BTC-USDT").Do(newContext())
+	r.NoError(err)
+	r.Empty(*positions)
+}
@@ -0,0 +1,291 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordKind Define kind of recorded traffic
+type RecordKind string
+
+const (
+	RestRecordKind RecordKind = "rest"
+	WsRecordKind   RecordKind = "ws"
+)
+
+// ErrNoRecord request has no matching response in recording
+var ErrNoRecord = errors.New("bingx: no recorded response for request")
+
+// ErrReplayFinished all recorded messages of stream are replayed
+var ErrReplayFinished = errors.New("bingx: replay finished")
+
+// Record Define request with its response or websocket message
+type Record struct {
+	Time time.Time  `json:"time"`
+	Kind RecordKind `json:"kind"`
+	// Method of REST request
+	Method string `json:"method,omitempty"`
+	// URL of REST request or websocket endpoint, signature and timestamp are removed and listen key is redacted
+	URL string `json:"url,omitempty"`
+	// Request body of REST request
+	Request string `json:"request,omitempty"`
+	// Status code of REST response
+	Status int `json:"status,omitempty"`
+	// Body of REST response or websocket message, decoded from gzip
+	Body string `json:"body,omitempty"`
+	// Error request failed with before response is received
+	Error string `json:"error,omitempty"`
+	// Sent websocket message is sent by client
+	Sent bool `json:"sent,omitempty"`
+}
+
+// Recorder Define writer of traffic as JSON lines, set it as Client.Recorder and pass with WithWsRecorder.
+// Keys and signatures are not recorded.
+type Recorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+	now func() time.Time
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		enc: json.NewEncoder(w),
+		now: time.Now,
+	}
+}
+
+// Err First write error, nothing is recorded after it
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(rec *Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+	rec.Time = r.now()
+	r.err = r.enc.Encode(rec)
+}
+
+func (r *Recorder) recordRest(req *http.Request, body string, res *http.Response, data []byte, err error) {
+	rec := &Record{
+		Kind:    RestRecordKind,
+		Method:  req.Method,
+		URL:     recordURL(req.URL.String()),
+		Request: recordBody(body),
+		Body:    string(data),
+	}
+	if res != nil {
+		rec.Status = res.StatusCode
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	r.record(rec)
+}
+
+func (r *Recorder) recordWs(endpoint string, message []byte, sent bool) {
+	r.record(&Record{
+		Kind: WsRecordKind,
+		URL:  recordURL(endpoint),
+		Body: string(message),
+		Sent: sent,
+	})
+}
+
+// recordURL strips values which change between runs or must not be stored
+func recordURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.RawQuery = stripParams(u.Query()).Encode()
+	return u.String()
+}
+
+// recordBody strips the same values from form body
+func recordBody(body string) string {
+	form, err := url.ParseQuery(body)
+	if err != nil {
+		return body
+	}
+	return stripParams(form).Encode()
+}
+
+func stripParams(params url.Values) url.Values {
+	params.Del(signatureKey)
+	params.Del(timestampKey)
+	params.Del(recvWindowKey)
+	if params.Has("listenKey") {
+		params.Set("listenKey", "")
+	}
+	return params
+}
+
+// ReadRecords Read recording written by Recorder, records are not limited in size
+func ReadRecords(r io.Reader) ([]*Record, error) {
+	var records []*Record
+	decoder := json.NewDecoder(r)
+	for {
+		rec := new(Record)
+		err := decoder.Decode(rec)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bingx: invalid record %d: %w", len(records)+1, err)
+		}
+		records = append(records, rec)
+	}
+}
+
+// Replayer Define source of recorded traffic. It serves REST responses as http.RoundTripper
+// of Client.HTTPClient and websocket messages to streams created with WithWsReplay.
+type Replayer struct {
+	mu       sync.Mutex
+	records  []*Record
+	consumed []bool
+	speed    float64
+}
+
+func NewReplayer(records []*Record) *Replayer {
+	return &Replayer{
+		records:  records,
+		consumed: make([]bool, len(records)),
+	}
+}
+
+// Speed Set time scale of websocket messages, 1 keeps recorded delays and 2 halves them.
+// Zero, the default, replays messages without delays.
+func (p *Replayer) Speed(scale float64) *Replayer {
+	p.speed = scale
+	return p
+}
+
+// RoundTrip Return response of the first not yet replayed request with the same method, path and parameters
+func (p *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	target := recordURL(req.URL.String())
+
+	p.mu.Lock()
+	var rec *Record
+	for i, r := range p.records {
+		if p.consumed[i] || r.Kind != RestRecordKind || r.Method != req.Method {
+			continue
+		}
+		if sameURL(r.URL, target) && r.Request == recordBody(string(body)) {
+			p.consumed[i] = true
+			rec = r
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	if rec == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoRecord, req.Method, req.URL.Path)
+	}
+	if rec.Error != "" {
+		return nil, errors.New(rec.Error)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// sameURL compares path and parameters ignoring host and order of parameters
+func sameURL(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return a == b
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return a == b
+	}
+	return ua.Path == ub.Path && ua.Query().Encode() == ub.Query().Encode()
+}
+
+// serve calls handler with recorded messages received from endpoint of config
+func (p *Replayer) serve(ctx context.Context, config *WsConfig, handler WsHandler) *Stream {
+	endpoint := recordURL(config.Endpoint)
+	var messages []*Record
+	for _, rec := range p.records {
+		if rec.Kind == WsRecordKind && !rec.Sent && sameURL(rec.URL, endpoint) {
+			messages = append(messages, rec)
+		}
+	}
+	speed := p.speed
+
+	quitC := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(quitC)
+		})
+	}
+
+	doneC := make(chan struct{})
+	go func() {
+		defer close(doneC)
+
+		if config.OnConnected != nil {
+			config.OnConnected()
+		}
+		for i, rec := range messages {
+			var delay time.Duration
+			if i > 0 && speed > 0 {
+				delay = time.Duration(float64(rec.Time.Sub(messages[i-1].Time)) / speed)
+			}
+			if delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-quitC:
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			} else {
+				select {
+				case <-quitC:
+					return
+				default:
+				}
+			}
+			handler([]byte(rec.Body))
+		}
+	}()
+
+	return newStream(ctx, stop, func() error {
+		return ErrReplayFinished
+	}, doneC)
+}
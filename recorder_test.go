@@ -0,0 +1,140 @@
+package bingx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type recorderTestSuite struct {
+	serverTestSuite
+}
+
+func TestRecorder(t *testing.T) {
+	suite.Run(t, new(recorderTestSuite))
+}
+
+func (s *recorderTestSuite) createOrder(client *Client) (*CreateOrderResponse, error) {
+	return client.NewCreateOrderService().
+		Symbol("BTC-USDT").
+		Type(LimitOrderType).
+		Side(BuySideType).
+		Price(40000).
+		Quantity(0.01).
+		Do(newContext())
+}
+
+func (s *recorderTestSuite) TestRecordAndReplay() {
+	r := s.r()
+	buf := new(bytes.Buffer)
+	recorder := NewRecorder(buf)
+	s.client.Recorder = recorder
+
+	recorded, err := s.createOrder(s.client)
+	r.NoError(err)
+	balance, err := s.client.NewGetBalanceService().Do(newContext())
+	r.NoError(err)
+
+	tradeC := make(chan *WsTradeEvent, 2)
+	stream, err := WsTradeServe(newContext(), "BTC-USDT", func(event *WsTradeEvent) {
+		tradeC <- event
+	}, nil, WithWsEndpoint(s.server.WsURL), WithWsRecorder(recorder))
+	r.NoError(err)
+	r.NoError(s.server.WaitSubscription("BTC-USDT@trade", 5*time.Second))
+	for _, price := range []string{"43000", "43001"} {
+		r.NoError(s.server.Publish("BTC-USDT@trade", []map[string]interface{}{{"s": "BTC-USDT", "p": price, "q": "1"}}))
+		<-tradeC
+	}
+	r.NoError(stream.Close())
+	r.NoError(recorder.Err())
+
+	r.NotContains(buf.String(), "dummyAPIKey")
+	r.NotContains(buf.String(), signatureKey)
+	records, err := ReadRecords(buf)
+	r.NoError(err)
+	var sent int
+	for _, rec := range records {
+		if rec.Sent {
+			sent++
+			r.Contains(rec.Body, "BTC-USDT@trade")
+		}
+	}
+	r.Equal(1, sent)
+
+	replayer := NewReplayer(records)
+	client := NewClient("otherAPIKey", "otherSecretKey")
+	client.BaseURL = "http://replay.invalid"
+	client.HTTPClient = &http.Client{Transport: replayer}
+
+	// requests are matched by parameters, not order
+	replayedBalance, err := client.NewGetBalanceService().Do(newContext())
+	r.NoError(err)
+	r.Equal(balance, replayedBalance)
+	replayed, err := s.createOrder(client)
+	r.NoError(err)
+	r.Equal(recorded, replayed)
+	_, err = s.createOrder(client)
+	r.True(errors.Is(err, ErrNoRecord), err)
+
+	var prices []float64
+	stream, err = WsTradeServe(newContext(), "BTC-USDT", func(event *WsTradeEvent) {
+		prices = append(prices, event.Price)
+	}, nil, WithWsReplay(replayer))
+	r.NoError(err)
+	<-stream.Done()
+	r.Equal([]float64{43000, 43001}, prices)
+	r.Equal(ErrReplayFinished, stream.Err())
+}
+
+func (s *recorderTestSuite) TestReadLargeRecords() {
+	r := s.r()
+	// all tickers response is escaped in record and grows beyond 1MB
+	body := `{"code":0,"data":[` + strings.Repeat(`{"symbol":"BTC-USDT","lastPrice":"43044.5"},`, 30000) + `{}]}`
+	buf := new(bytes.Buffer)
+	for _, rec := range []*Record{
+		{Kind: RestRecordKind, Method: http.MethodGet, URL: "/openApi/swap/v2/quote/ticker", Status: http.StatusOK, Body: body},
+		{Kind: WsRecordKind, Body: "{}"},
+	} {
+		data, err := json.Marshal(rec)
+		r.NoError(err)
+		buf.Write(append(data, '\n', '\n'))
+	}
+	r.Greater(buf.Len(), 1<<20)
+
+	records, err := ReadRecords(buf)
+	r.NoError(err)
+	r.Len(records, 2)
+	r.Equal(body, records[0].Body)
+	r.Equal("{}", records[1].Body)
+}
+
+func (s *recorderTestSuite) TestReplaySpeed() {
+	r := s.r()
+	start := time.Unix(1702719166, 0)
+	recording := `{"time":"` + start.Format(time.RFC3339Nano) + `","kind":"ws","url":"wss://open-api-swap.bingx.com/swap-market","body":"{\"dataType\":\"BTC-USDT@trade\",\"data\":[{\"p\":\"1\",\"q\":\"1\"}]}"}
+{"time":"` + start.Add(200*time.Millisecond).Format(time.RFC3339Nano) + `","kind":"ws","url":"wss://open-api-swap.bingx.com/swap-market","body":"{\"dataType\":\"BTC-USDT@trade\",\"data\":[{\"p\":\"2\",\"q\":\"1\"}]}"}
+`
+	records, err := ReadRecords(strings.NewReader(recording))
+	r.NoError(err)
+	r.Len(records, 2)
+
+	var prices []float64
+	market := NewMarketStream(nil, WithWsReplay(NewReplayer(records).Speed(2)))
+	r.NoError(market.SubscribeTrade("BTC-USDT", func(event *WsTradeEvent) {
+		prices = append(prices, event.Price)
+	}))
+	began := time.Now()
+	r.NoError(market.Start())
+	<-market.Done()
+	r.GreaterOrEqual(time.Since(began), 100*time.Millisecond)
+	r.Equal([]float64{1, 2}, prices)
+
+	_, err = ReadRecords(strings.NewReader("{"))
+	r.Error(err)
+}
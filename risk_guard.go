@@ -0,0 +1,498 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RiskRule Define pre-trade check of RiskGuard
+type RiskRule string
+
+const (
+	PositionSizeRiskRule  RiskRule = "POSITION_SIZE"
+	OrderNotionalRiskRule RiskRule = "ORDER_NOTIONAL"
+	OpenOrdersRiskRule    RiskRule = "OPEN_ORDERS"
+	DailyLossRiskRule     RiskRule = "DAILY_LOSS"
+	PriceBandRiskRule     RiskRule = "PRICE_BAND"
+	OrderRateRiskRule     RiskRule = "ORDER_RATE"
+	KillSwitchRiskRule    RiskRule = "KILL_SWITCH"
+)
+
+// RiskEventType Define kind of audit event
+type RiskEventType string
+
+const (
+	AcceptedRiskEventType RiskEventType = "ACCEPTED"
+	RejectedRiskEventType RiskEventType = "REJECTED"
+	KilledRiskEventType   RiskEventType = "KILLED"
+	ResumedRiskEventType  RiskEventType = "RESUMED"
+)
+
+// ErrRiskNoMarkPrice mark price of symbol is needed by a limit and can not be loaded
+var ErrRiskNoMarkPrice = errors.New("bingx: no mark price for risk check")
+
+// RiskError Define order rejected by RiskGuard
+type RiskError struct {
+	Rule   RiskRule
+	Symbol string
+	// Value of checked quantity which exceeds Limit
+	Value float64
+	Limit float64
+}
+
+func (e *RiskError) Error() string {
+	if e.Rule == KillSwitchRiskRule {
+		return fmt.Sprintf("<RiskError> rule=%s, symbol=%s", e.Rule, e.Symbol)
+	}
+	return fmt.Sprintf("<RiskError> rule=%s, symbol=%s, value=%g, limit=%g", e.Rule, e.Symbol, e.Value, e.Limit)
+}
+
+// RiskEvent Define audit event of RiskGuard
+type RiskEvent struct {
+	Time time.Time
+	Type RiskEventType
+	// Order checked order, nil for kill switch events
+	Order *OrderRequest
+	// Err reason order is rejected, *RiskError for violated rules
+	Err error
+}
+
+type RiskEventHandler func(*RiskEvent)
+
+// RiskLimits Define limits of RiskGuard, zero value disables a limit
+type RiskLimits struct {
+	// MaxPosition max absolute net position amount of symbol after order
+	MaxPosition map[string]float64
+	// MaxOrderNotional max quantity times price of order, mark price is used for market orders
+	MaxOrderNotional float64
+	// MaxOpenOrders max number of open orders of account, market orders are not limited
+	MaxOpenOrders int
+	// MaxDailyLoss max drop of equity since start of UTC day. Equity at day start is captured by
+	// RiskGuard.Start, without it the baseline is equity at the first check of the day.
+	MaxDailyLoss float64
+	// PriceBand max deviation of limit price from mark price as share of it, 0.05 is 5%
+	PriceBand float64
+	// MaxOrders max number of orders within OrderRateInterval
+	MaxOrders         int
+	OrderRateInterval time.Duration
+}
+
+// RiskGuard Checks orders of CreateOrderService before they are sent, set it as Client.RiskGuard.
+// Positions, mark prices, equity and open orders come from Portfolio and OrderTracker when they
+// are set and are loaded with REST otherwise. Reduce only orders are not limited by position
+// size and daily loss.
+type RiskGuard struct {
+	limits RiskLimits
+
+	mu           sync.Mutex
+	now          func() time.Time
+	portfolio    *Portfolio
+	tracker      *OrderTracker
+	markPrices   map[string]float64
+	auditHandler RiskEventHandler
+	killed       bool
+	orderTimes   []time.Time
+	// day UTC day of dayEquity, baseline of MaxDailyLoss
+	day       time.Time
+	dayEquity float64
+}
+
+func NewRiskGuard(limits RiskLimits) *RiskGuard {
+	return &RiskGuard{
+		limits:     limits,
+		now:        time.Now,
+		markPrices: map[string]float64{},
+	}
+}
+
+// Portfolio Set source of positions, mark prices and equity
+func (g *RiskGuard) Portfolio(portfolio *Portfolio) *RiskGuard {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.portfolio = portfolio
+	return g
+}
+
+// OrderTracker Set source of open orders
+func (g *RiskGuard) OrderTracker(tracker *OrderTracker) *RiskGuard {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.tracker = tracker
+	return g
+}
+
+// AuditHandler Set handler of accepted and rejected orders and kill switch changes
+func (g *RiskGuard) AuditHandler(handler RiskEventHandler) *RiskGuard {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.auditHandler = handler
+	return g
+}
+
+// Clock Set time source of order rate and daily loss limits
+func (g *RiskGuard) Clock(now func() time.Time) *RiskGuard {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.now = now
+	return g
+}
+
+// UpdateMarkPrice Set mark price of symbol, e.g. from WsMarkPriceServe, it takes precedence over Portfolio
+func (g *RiskGuard) UpdateMarkPrice(symbol string, price float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.markPrices[symbol] = price
+}
+
+// Killed Kill switch is on and orders are rejected
+func (g *RiskGuard) Killed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.killed
+}
+
+// Kill Reject all new orders and cancel open orders of every symbol.
+// Orders stay rejected when canceling fails, until Resume is called.
+func (g *RiskGuard) Kill(ctx context.Context, orders OrderAPI) error {
+	g.mu.Lock()
+	g.killed = true
+	g.mu.Unlock()
+	g.audit(&RiskEvent{Type: KilledRiskEventType})
+
+	open, err := orders.GetOpenOrders(ctx, "")
+	if err != nil {
+		return err
+	}
+	symbols := map[string]bool{}
+	for _, order := range open {
+		symbols[order.Symbol] = true
+	}
+	sorted := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		sorted = append(sorted, symbol)
+	}
+	sort.Strings(sorted)
+
+	for _, symbol := range sorted {
+		_, err := orders.CancelAllOrders(ctx, symbol)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resume Turn kill switch off
+func (g *RiskGuard) Resume() {
+	g.mu.Lock()
+	g.killed = false
+	g.mu.Unlock()
+	g.audit(&RiskEvent{Type: ResumedRiskEventType})
+}
+
+// Start Capture equity of MaxDailyLoss baseline now and at every UTC day rollover until ctx is done.
+// Equity comes from Portfolio when it is set and is loaded with client otherwise.
+func (g *RiskGuard) Start(ctx context.Context, client *Client, errHandler ErrHandler) error {
+	g.mu.Lock()
+	now := g.now()
+	g.mu.Unlock()
+	day := now.UTC().Truncate(24 * time.Hour)
+
+	err := g.captureDayEquity(ctx, client, day)
+	if err != nil {
+		return err
+	}
+
+	go g.run(ctx, client, errHandler, day, day.Add(24*time.Hour).Sub(now))
+
+	return nil
+}
+
+// run captures equity of the next day after wait and of every following day
+func (g *RiskGuard) run(ctx context.Context, client *Client, errHandler ErrHandler, day time.Time, wait time.Duration) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		day = day.Add(24 * time.Hour)
+		timer.Reset(24 * time.Hour)
+		err := g.captureDayEquity(ctx, client, day)
+		if err != nil && errHandler != nil && ctx.Err() == nil {
+			errHandler(err)
+		}
+	}
+}
+
+// captureDayEquity Set current equity as MaxDailyLoss baseline of day
+func (g *RiskGuard) captureDayEquity(ctx context.Context, client *Client, day time.Time) error {
+	g.mu.Lock()
+	portfolio := g.portfolio
+	g.mu.Unlock()
+
+	equity, err := g.equity(ctx, client, portfolio)
+	if err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if day.After(g.day) {
+		g.day, g.dayEquity = day, equity
+	}
+	return nil
+}
+
+// Check Check order against limits, REST requests are made with client when data is not available locally.
+// Accepted order is counted by order rate limit.
+func (g *RiskGuard) Check(ctx context.Context, client *Client, order *OrderRequest) error {
+	err := g.check(ctx, client, order)
+	event := &RiskEvent{Type: AcceptedRiskEventType, Order: order, Err: err}
+	if err != nil {
+		event.Type = RejectedRiskEventType
+	}
+	g.audit(event)
+	return err
+}
+
+func (g *RiskGuard) check(ctx context.Context, client *Client, order *OrderRequest) (err error) {
+	g.mu.Lock()
+	killed, now := g.killed, g.now()
+	portfolio, tracker := g.portfolio, g.tracker
+	g.mu.Unlock()
+
+	if killed {
+		return &RiskError{Rule: KillSwitchRiskRule, Symbol: order.Symbol}
+	}
+
+	limits := g.limits
+	if limits.MaxOrders > 0 && limits.OrderRateInterval > 0 {
+		g.mu.Lock()
+		since := now.Add(-limits.OrderRateInterval)
+		times := g.orderTimes[:0]
+		for _, t := range g.orderTimes {
+			if t.After(since) {
+				times = append(times, t)
+			}
+		}
+		count := len(times)
+		// slot is reserved before other rules so that concurrent checks can not pass the limit together
+		if count < limits.MaxOrders {
+			times = append(times, now)
+		}
+		g.orderTimes = times
+		g.mu.Unlock()
+
+		if count >= limits.MaxOrders {
+			return &RiskError{Rule: OrderRateRiskRule, Symbol: order.Symbol, Value: float64(count + 1), Limit: float64(limits.MaxOrders)}
+		}
+		defer func() {
+			if err != nil {
+				g.releaseOrderTime(now)
+			}
+		}()
+	}
+
+	needMark := limits.MaxOrderNotional > 0 && order.Price == 0 || limits.PriceBand > 0 && order.Price > 0
+	var mark float64
+	if needMark {
+		var err error
+		mark, err = g.markPrice(ctx, client, portfolio, order.Symbol)
+		if err != nil {
+			return err
+		}
+	}
+
+	if limits.PriceBand > 0 && order.Price > 0 {
+		deviation := math.Abs(order.Price-mark) / mark
+		if deviation > limits.PriceBand {
+			return &RiskError{Rule: PriceBandRiskRule, Symbol: order.Symbol, Value: deviation, Limit: limits.PriceBand}
+		}
+	}
+
+	if limits.MaxOrderNotional > 0 {
+		price := order.Price
+		if price == 0 {
+			price = mark
+		}
+		if notional := order.Quantity * price; notional > limits.MaxOrderNotional {
+			return &RiskError{Rule: OrderNotionalRiskRule, Symbol: order.Symbol, Value: notional, Limit: limits.MaxOrderNotional}
+		}
+	}
+
+	if limits.MaxOpenOrders > 0 && order.Type != MarketOrderType {
+		count, err := g.openOrders(ctx, client, tracker)
+		if err != nil {
+			return err
+		}
+		if count >= limits.MaxOpenOrders {
+			return &RiskError{Rule: OpenOrdersRiskRule, Symbol: order.Symbol, Value: float64(count + 1), Limit: float64(limits.MaxOpenOrders)}
+		}
+	}
+
+	if !order.ReduceOnly {
+		if limit, ok := limits.MaxPosition[order.Symbol]; ok {
+			amount, err := g.position(ctx, client, portfolio, order.Symbol)
+			if err != nil {
+				return err
+			}
+			if order.Side == SellSideType {
+				amount -= order.Quantity
+			} else {
+				amount += order.Quantity
+			}
+			if math.Abs(amount) > limit {
+				return &RiskError{Rule: PositionSizeRiskRule, Symbol: order.Symbol, Value: math.Abs(amount), Limit: limit}
+			}
+		}
+
+		if limits.MaxDailyLoss > 0 {
+			equity, err := g.equity(ctx, client, portfolio)
+			if err != nil {
+				return err
+			}
+
+			g.mu.Lock()
+			day := now.UTC().Truncate(24 * time.Hour)
+			// baseline of the day is not captured by Start yet
+			if day.After(g.day) {
+				g.day, g.dayEquity = day, equity
+			}
+			loss := g.dayEquity - equity
+			g.mu.Unlock()
+
+			if loss > limits.MaxDailyLoss {
+				return &RiskError{Rule: DailyLossRiskRule, Symbol: order.Symbol, Value: loss, Limit: limits.MaxDailyLoss}
+			}
+		}
+	}
+
+	return nil
+}
+
+// releaseOrderTime Free order rate slot reserved by rejected order
+func (g *RiskGuard) releaseOrderTime(t time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := len(g.orderTimes) - 1; i >= 0; i-- {
+		if g.orderTimes[i].Equal(t) {
+			g.orderTimes = append(g.orderTimes[:i], g.orderTimes[i+1:]...)
+			return
+		}
+	}
+}
+
+func (g *RiskGuard) markPrice(ctx context.Context, client *Client, portfolio *Portfolio, symbol string) (float64, error) {
+	g.mu.Lock()
+	mark, ok := g.markPrices[symbol]
+	g.mu.Unlock()
+	if ok {
+		return mark, nil
+	}
+	if portfolio != nil {
+		if mark, ok := portfolio.MarkPrice(symbol); ok {
+			return mark, nil
+		}
+	}
+
+	res, err := client.GetPremiumIndex(ctx, symbol)
+	if err != nil {
+		return 0, fmt.Errorf("%w of %s: %v", ErrRiskNoMarkPrice, symbol, err)
+	}
+	for _, index := range res {
+		if index.Symbol == symbol {
+			parser := &numberParser{optional: true}
+			mark := parser.parse("mark price", index.MarkPrice)
+			if parser.err == nil && mark > 0 {
+				return mark, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w of %s", ErrRiskNoMarkPrice, symbol)
+}
+
+func (g *RiskGuard) openOrders(ctx context.Context, client *Client, tracker *OrderTracker) (int, error) {
+	if tracker != nil {
+		return len(tracker.Orders(true)), nil
+	}
+	orders, err := client.GetOpenOrders(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+	return len(orders), nil
+}
+
+// position net amount of symbol over position sides
+func (g *RiskGuard) position(ctx context.Context, client *Client, portfolio *Portfolio, symbol string) (float64, error) {
+	if portfolio != nil {
+		var amount float64
+		for _, pos := range portfolio.State().Positions {
+			if pos.Symbol == symbol {
+				amount += pos.Amount
+			}
+		}
+		return amount, nil
+	}
+
+	positions, err := client.GetOpenPositions(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	parser := &numberParser{optional: true}
+	var amount float64
+	for _, pos := range positions {
+		if pos.Symbol != symbol {
+			continue
+		}
+		v := math.Abs(parser.parse("position amount", pos.PositionAmt))
+		if pos.PositionSide == string(ShortPositionSideType) {
+			v = -v
+		}
+		amount += v
+	}
+	return amount, parser.err
+}
+
+func (g *RiskGuard) equity(ctx context.Context, client *Client, portfolio *Portfolio) (float64, error) {
+	if portfolio != nil {
+		return portfolio.Equity(), nil
+	}
+
+	balance, err := client.GetBalance(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if balance == nil {
+		return 0, fmt.Errorf("bingx: empty balance")
+	}
+	parser := &numberParser{optional: true}
+	equity := parser.parse("equity", balance.Equity)
+	return equity, parser.err
+}
+
+func (g *RiskGuard) audit(event *RiskEvent) {
+	g.mu.Lock()
+	handler := g.auditHandler
+	event.Time = g.now()
+	g.mu.Unlock()
+
+	if handler != nil {
+		handler(event)
+	}
+}
@@ -0,0 +1,198 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type riskGuardTestSuite struct {
+	baseTestSuite
+	paper  *PaperClient
+	now    time.Time
+	events []*RiskEvent
+}
+
+func TestRiskGuard(t *testing.T) {
+	suite.Run(t, new(riskGuardTestSuite))
+}
+
+func (s *riskGuardTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.now = time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	s.events = nil
+	s.paper = NewPaperClient(s.client.Client).Balance(1000).Leverage("BTC-USDT", 10).Fees(0, 0)
+	s.paper.UpdatePrice("BTC-USDT", 100)
+}
+
+func (s *riskGuardTestSuite) guard(limits RiskLimits) *RiskGuard {
+	guard := NewRiskGuard(limits).
+		Clock(func() time.Time {
+			return s.now
+		}).
+		AuditHandler(func(event *RiskEvent) {
+			s.events = append(s.events, event)
+		})
+	s.paper.RiskGuard = guard
+	return guard
+}
+
+func (s *riskGuardTestSuite) order(orderType OrderType, side SideType, price, quantity float64) error {
+	_, err := s.paper.CreateOrder(newContext(), &OrderRequest{
+		Symbol:   "BTC-USDT",
+		Type:     orderType,
+		Side:     side,
+		Price:    price,
+		Quantity: quantity,
+	})
+	return err
+}
+
+func (s *riskGuardTestSuite) requireRule(rule RiskRule, err error) *RiskError {
+	riskErr := new(RiskError)
+	s.r().True(errors.As(err, &riskErr), err)
+	s.r().Equal(rule, riskErr.Rule)
+	return riskErr
+}
+
+func (s *riskGuardTestSuite) TestOrderLimits() {
+	r := s.r()
+	guard := s.guard(RiskLimits{
+		MaxPosition:      map[string]float64{"BTC-USDT": 5},
+		MaxOrderNotional: 400,
+		PriceBand:        0.1,
+	})
+	guard.UpdateMarkPrice("BTC-USDT", 100)
+
+	r.NoError(s.order(MarketOrderType, BuySideType, 0, 4))
+	riskErr := s.requireRule(OrderNotionalRiskRule, s.order(MarketOrderType, BuySideType, 0, 5))
+	r.Equal(float64(500), riskErr.Value)
+	r.Equal(float64(400), riskErr.Limit)
+
+	riskErr = s.requireRule(PositionSizeRiskRule, s.order(LimitOrderType, BuySideType, 95, 2))
+	r.Equal(float64(6), riskErr.Value)
+	r.NoError(s.order(LimitOrderType, SellSideType, 105, 3))
+
+	s.requireRule(PriceBandRiskRule, s.order(LimitOrderType, BuySideType, 89, 1))
+
+	// reduce only order may exceed position limit
+	_, err := s.paper.CreateOrder(newContext(), &OrderRequest{Symbol: "BTC-USDT", Type: MarketOrderType, Side: SellSideType, ReduceOnly: true, Quantity: 4})
+	r.NoError(err)
+
+	r.Len(s.events, 6)
+	r.Equal(AcceptedRiskEventType, s.events[0].Type)
+	r.Equal(RejectedRiskEventType, s.events[1].Type)
+	r.Equal(float64(5), s.events[1].Order.Quantity)
+	r.Equal(s.now, s.events[1].Time)
+}
+
+func (s *riskGuardTestSuite) TestOpenOrdersAndRate() {
+	r := s.r()
+	s.guard(RiskLimits{MaxOpenOrders: 2, MaxOrders: 3, OrderRateInterval: time.Second})
+
+	r.NoError(s.order(LimitOrderType, BuySideType, 90, 1))
+	r.NoError(s.order(LimitOrderType, BuySideType, 91, 1))
+	s.requireRule(OpenOrdersRiskRule, s.order(LimitOrderType, BuySideType, 92, 1))
+
+	r.NoError(s.order(MarketOrderType, BuySideType, 0, 1))
+	s.requireRule(OrderRateRiskRule, s.order(MarketOrderType, BuySideType, 0, 1))
+
+	s.now = s.now.Add(time.Second)
+	r.NoError(s.order(MarketOrderType, BuySideType, 0, 1))
+}
+
+func (s *riskGuardTestSuite) TestConcurrentRate() {
+	guard := NewRiskGuard(RiskLimits{MaxOrders: 5, OrderRateInterval: time.Minute, MaxPosition: map[string]float64{"BTC-USDT": 100}})
+	order := &OrderRequest{Symbol: "BTC-USDT", Type: MarketOrderType, Side: BuySideType, Quantity: 1}
+	// slow position request keeps checks running together
+	client := NewClient(s.apiKey, s.secretKey)
+	client.do = func(*http.Request) (*http.Response, error) {
+		time.Sleep(10 * time.Millisecond)
+		return newHTTPResponse([]byte(`{"code": 0, "data": []}`), http.StatusOK), nil
+	}
+
+	var wg sync.WaitGroup
+	var accepted int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if guard.Check(newContext(), client, order) == nil {
+				atomic.AddInt32(&accepted, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	s.r().Equal(int32(5), accepted)
+}
+
+func (s *riskGuardTestSuite) TestDailyLoss() {
+	r := s.r()
+	s.guard(RiskLimits{MaxDailyLoss: 50})
+
+	r.NoError(s.order(MarketOrderType, BuySideType, 0, 10))
+	s.paper.UpdatePrice("BTC-USDT", 94)
+	riskErr := s.requireRule(DailyLossRiskRule, s.order(MarketOrderType, BuySideType, 0, 1))
+	r.Equal(float64(60), riskErr.Value)
+
+	// loss is counted from the next day equity
+	s.now = s.now.Add(24 * time.Hour)
+	r.NoError(s.order(MarketOrderType, BuySideType, 0, 1))
+}
+
+func (s *riskGuardTestSuite) TestDailyLossStart() {
+	r := s.r()
+	guard := s.guard(RiskLimits{MaxDailyLoss: 50})
+	ctx, cancel := context.WithCancel(newContext())
+	defer cancel()
+	r.NoError(guard.Start(ctx, s.paper.Client, nil))
+
+	// loss made before the first check is counted from equity captured by Start
+	s.paper.RiskGuard = nil
+	r.NoError(s.order(MarketOrderType, BuySideType, 0, 10))
+	s.paper.UpdatePrice("BTC-USDT", 94)
+	s.paper.RiskGuard = guard
+
+	riskErr := s.requireRule(DailyLossRiskRule, s.order(MarketOrderType, BuySideType, 0, 1))
+	r.Equal(float64(60), riskErr.Value)
+}
+
+func (s *riskGuardTestSuite) TestKillSwitch() {
+	r := s.r()
+	guard := s.guard(RiskLimits{})
+
+	r.NoError(s.order(LimitOrderType, BuySideType, 90, 1))
+	r.NoError(guard.Kill(newContext(), s.paper))
+	r.True(guard.Killed())
+	open, err := s.paper.GetOpenOrders(newContext(), "")
+	r.NoError(err)
+	r.Empty(open)
+
+	s.requireRule(KillSwitchRiskRule, s.order(LimitOrderType, BuySideType, 90, 1))
+
+	guard.Resume()
+	r.NoError(s.order(LimitOrderType, BuySideType, 90, 1))
+
+	var types []RiskEventType
+	for _, event := range s.events {
+		types = append(types, event.Type)
+	}
+	r.Equal([]RiskEventType{AcceptedRiskEventType, KilledRiskEventType, RejectedRiskEventType, ResumedRiskEventType, AcceptedRiskEventType}, types)
+}
+
+func (s *riskGuardTestSuite) TestNoMarkPrice() {
+	r := s.r()
+	s.mockDo([]byte(`{"code": 0, "msg": "", "data": []}`), nil)
+	s.paper = NewPaperClient(s.client.Client).Balance(1000)
+	s.guard(RiskLimits{PriceBand: 0.1})
+
+	err := s.order(LimitOrderType, BuySideType, 90, 1)
+	r.True(errors.Is(err, ErrRiskNoMarkPrice), err)
+	s.assertDo()
+}
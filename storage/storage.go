@@ -0,0 +1,383 @@
+// Package storage persists kline series to local CSV files
+// and keeps them up to date incrementally.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+)
+
+// Format of series files
+type Format string
+
+const (
+	CSVFormat Format = "csv"
+)
+
+const (
+	indexFileName      = "index.json"
+	defaultSyncCandles = 1000
+)
+
+var csvHeader = []string{"time", "open", "high", "low", "close", "volume"}
+
+// Range Define continuous range of stored candles, both bounds are open times
+type Range struct {
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime"`
+}
+
+// Series Define index entry of stored series
+type Series struct {
+	Symbol    string         `json:"symbol"`
+	Interval  bingx.Interval `json:"interval"`
+	Format    Format         `json:"format"`
+	File      string         `json:"file"`
+	Count     int            `json:"count"`
+	Ranges    []Range        `json:"ranges"`
+	UpdatedAt int64          `json:"updatedAt"`
+}
+
+// Store keeps one file per symbol and interval under dir and an index of covered ranges
+type Store struct {
+	// SyncStartTime is where Sync starts when nothing is stored yet, zero means the last 1000 candles
+	SyncStartTime int64
+
+	dir    string
+	format Format
+	client *bingx.Client
+	mu     sync.Mutex
+}
+
+// NewStore Init store in dir, client is only required by Sync
+func NewStore(dir string, format Format, client *bingx.Client) *Store {
+	return &Store{
+		dir:    dir,
+		format: format,
+		client: client,
+	}
+}
+
+func (s *Store) fileName(symbol string, interval bingx.Interval) string {
+	name := string(interval)
+	// 1m and 1M collide on case-insensitive filesystems
+	if interval == bingx.Interval1M {
+		name = "1mo"
+	}
+	return filepath.Join(symbol, name+"."+string(s.format))
+}
+
+// Load Stored candles in ascending order, nil if series does not exist
+func (s *Store) Load(symbol string, interval bingx.Interval) ([]*bingx.Kline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(symbol, interval)
+}
+
+func (s *Store) load(symbol string, interval bingx.Interval) ([]*bingx.Kline, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, s.fileName(symbol, interval)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch s.format {
+	case CSVFormat:
+		return decodeCSV(data)
+	}
+	return nil, fmt.Errorf("storage: unsupported format %q", s.format)
+}
+
+// Save Merge candles into stored series, newer candles replace stored ones with the same time
+func (s *Store) Save(symbol string, interval bingx.Interval, klines []*bingx.Kline) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save(symbol, interval, klines)
+}
+
+func (s *Store) save(symbol string, interval bingx.Interval, klines []*bingx.Kline) error {
+	if !interval.Valid() {
+		return fmt.Errorf("storage: unsupported interval %q", interval)
+	}
+
+	stored, err := s.load(symbol, interval)
+	if err != nil {
+		return err
+	}
+
+	merged := mergeKlines(stored, klines)
+
+	var buf bytes.Buffer
+	switch s.format {
+	case CSVFormat:
+		err = encodeCSV(&buf, merged)
+	default:
+		err = fmt.Errorf("storage: unsupported format %q", s.format)
+	}
+	if err != nil {
+		return err
+	}
+
+	file := s.fileName(symbol, interval)
+	err = writeFileAtomic(filepath.Join(s.dir, file), buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	index, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+
+	index[seriesKey(symbol, interval)] = &Series{
+		Symbol:    symbol,
+		Interval:  interval,
+		Format:    s.format,
+		File:      filepath.ToSlash(file),
+		Count:     len(merged),
+		Ranges:    coveredRanges(merged, interval),
+		UpdatedAt: time.Now().UnixMilli(),
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filepath.Join(s.dir, indexFileName), data)
+}
+
+// Series Index entry of stored series, nil if series does not exist
+func (s *Store) Series(symbol string, interval bingx.Interval) (*Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return index[seriesKey(symbol, interval)], nil
+}
+
+// Index All stored series
+func (s *Store) Index() ([]*Series, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Series, 0, len(index))
+	for _, series := range index {
+		res = append(res, series)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Symbol != res[j].Symbol {
+			return res[i].Symbol < res[j].Symbol
+		}
+		return res[i].Interval < res[j].Interval
+	})
+
+	return res, nil
+}
+
+// Sync Fetch closed candles after the last stored one and save them, returns number of new candles
+func (s *Store) Sync(ctx context.Context, symbol string, interval bingx.Interval) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		return 0, fmt.Errorf("storage: client is required to sync")
+	}
+
+	if !interval.Valid() {
+		return 0, fmt.Errorf("storage: unsupported interval %q", interval)
+	}
+
+	stored, err := s.load(symbol, interval)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UnixMilli()
+	startTime := s.SyncStartTime
+	if len(stored) > 0 {
+		startTime = stored[len(stored)-1].Time + 1
+	} else if startTime == 0 {
+		startTime = now - defaultSyncCandles*interval.Duration().Milliseconds()
+	}
+
+	res, err := s.client.NewKlineDownloader().
+		Symbol(symbol).
+		Interval(interval).
+		StartTime(startTime).
+		EndTime(now).
+		Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := make([]*bingx.Kline, 0, len(res.Klines))
+	for _, kline := range res.Klines {
+		if interval.Next(time.UnixMilli(kline.Time)).UnixMilli() <= now {
+			closed = append(closed, kline)
+		}
+	}
+	if len(closed) == 0 {
+		return 0, nil
+	}
+
+	err = s.save(symbol, interval, closed)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(closed), nil
+}
+
+func (s *Store) readIndex() (map[string]*Series, error) {
+	index := map[string]*Series{}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, indexFileName))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &index)
+	if err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func seriesKey(symbol string, interval bingx.Interval) string {
+	return symbol + "@" + string(interval)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func mergeKlines(stored, klines []*bingx.Kline) []*bingx.Kline {
+	byTime := make(map[int64]*bingx.Kline, len(stored)+len(klines))
+	for _, kline := range stored {
+		byTime[kline.Time] = kline
+	}
+	for _, kline := range klines {
+		byTime[kline.Time] = kline
+	}
+
+	merged := make([]*bingx.Kline, 0, len(byTime))
+	for _, kline := range byTime {
+		merged = append(merged, kline)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Time < merged[j].Time
+	})
+
+	return merged
+}
+
+func coveredRanges(klines []*bingx.Kline, interval bingx.Interval) []Range {
+	var ranges []Range
+	for i, kline := range klines {
+		if i == 0 || kline.Time > interval.Next(time.UnixMilli(klines[i-1].Time)).UnixMilli() {
+			ranges = append(ranges, Range{StartTime: kline.Time})
+		}
+		ranges[len(ranges)-1].EndTime = kline.Time
+	}
+
+	return ranges
+}
+
+func encodeCSV(w io.Writer, klines []*bingx.Kline) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, kline := range klines {
+		err = cw.Write([]string{
+			strconv.FormatInt(kline.Time, 10),
+			kline.Open,
+			kline.High,
+			kline.Low,
+			kline.Close,
+			kline.Volume,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func decodeCSV(data []byte) ([]*bingx.Kline, error) {
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = len(csvHeader)
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	klines := make([]*bingx.Kline, 0, len(records))
+	for i, record := range records {
+		if i == 0 && record[0] == csvHeader[0] {
+			continue
+		}
+
+		t, err := strconv.ParseInt(record[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("storage: invalid csv time on line %d: %w", i+1, err)
+		}
+
+		klines = append(klines, &bingx.Kline{
+			Time:   t,
+			Open:   record[1],
+			High:   record[2],
+			Low:    record[3],
+			Close:  record[4],
+			Volume: record[5],
+		})
+	}
+
+	return klines, nil
+}
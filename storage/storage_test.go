@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/magicaleks/go-bingx"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func testKlines(times ...int64) []*bingx.Kline {
+	klines := make([]*bingx.Kline, len(times))
+	for i, t := range times {
+		klines[i] = &bingx.Kline{
+			Time:   t,
+			Open:   "0.10278577",
+			High:   "43062.3",
+			Low:    "1.50",
+			Close:  strconv.FormatInt(t/60000, 10),
+			Volume: "17.47929838",
+		}
+	}
+	return klines
+}
+
+func TestSaveLoad(t *testing.T) {
+	r := require.New(t)
+	store := NewStore(t.TempDir(), CSVFormat, nil)
+
+	r.NoError(store.Save("BTC-USDT", bingx.Interval1, testKlines(180000, 0, 60000)))
+	updated := testKlines(60000, 300000)
+	updated[0].Close = "42"
+	r.NoError(store.Save("BTC-USDT", bingx.Interval1, updated))
+
+	klines, err := store.Load("BTC-USDT", bingx.Interval1)
+	r.NoError(err)
+	expected := testKlines(0, 60000, 180000, 300000)
+	expected[1].Close = "42"
+	r.Equal(expected, klines)
+
+	series, err := store.Series("BTC-USDT", bingx.Interval1)
+	r.NoError(err)
+	r.Equal(4, series.Count)
+	r.Equal(CSVFormat, series.Format)
+	r.Equal([]Range{{0, 60000}, {180000, 180000}, {300000, 300000}}, series.Ranges)
+
+	missing, err := store.Load("ETH-USDT", bingx.Interval1)
+	r.NoError(err)
+	r.Nil(missing)
+}
+
+func TestIntervalFileNames(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	store := NewStore(dir, CSVFormat, nil)
+
+	r.NoError(store.Save("BTC-USDT", bingx.Interval1, testKlines(0)))
+	r.NoError(store.Save("BTC-USDT", bingx.Interval1M, testKlines(0, 2678400000)))
+
+	index, err := store.Index()
+	r.NoError(err)
+	r.Len(index, 2)
+
+	_, err = os.Stat(filepath.Join(dir, "BTC-USDT", "1mo.csv"))
+	r.NoError(err)
+}
+
+func TestSync(t *testing.T) {
+	r := require.New(t)
+	step := int64(60000)
+	now := time.Now().UnixMilli()
+	last := now - now%step - 5*step
+
+	requests := 0
+	client := bingx.NewClient("key", "secret")
+	client.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		start, _ := strconv.ParseInt(req.URL.Query().Get("startTime"), 10, 64)
+		r.Equal(last+1, start)
+
+		var times []int64
+		for t := now - now%step; t > last; t -= step {
+			times = append(times, t)
+		}
+		data, _ := json.Marshal(map[string]interface{}{"code": 0, "data": testKlines(times...)})
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(data))}, nil
+	})}
+
+	store := NewStore(t.TempDir(), CSVFormat, client)
+	r.NoError(store.Save("BTC-USDT", bingx.Interval1, testKlines(last-step, last)))
+
+	added, err := store.Sync(context.Background(), "BTC-USDT", bingx.Interval1)
+	r.NoError(err)
+	r.Equal(4, added)
+	r.Equal(1, requests)
+
+	series, err := store.Series("BTC-USDT", bingx.Interval1)
+	r.NoError(err)
+	r.Equal([]Range{{last - step, last + 4*step}}, series.Ranges)
+}
@@ -0,0 +1,218 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// User data event types
+const (
+	AccountUpdateEventType       = "ACCOUNT_UPDATE"
+	OrderTradeUpdateEventType    = "ORDER_TRADE_UPDATE"
+	AccountConfigUpdateEventType = "ACCOUNT_CONFIG_UPDATE"
+	MarginCallEventType          = "MARGIN_CALL"
+	ListenKeyExpiredEventType    = "listenKeyExpired"
+)
+
+// WsBalance Define balance of ACCOUNT_UPDATE event
+type WsBalance struct {
+	Asset              string `json:"a"`
+	WalletBalance      string `json:"wb"`
+	CrossWalletBalance string `json:"cw"`
+	BalanceChange      string `json:"bc"`
+}
+
+// WsPosition Define position of ACCOUNT_UPDATE event
+type WsPosition struct {
+	Symbol         string           `json:"s"`
+	PositionSide   PositionSideType `json:"ps"`
+	PositionAmt    string           `json:"pa"`
+	EntryPrice     string           `json:"ep"`
+	UnrealizedPnl  string           `json:"up"`
+	MarginType     string           `json:"mt"`
+	IsolatedWallet string           `json:"iw"`
+}
+
+type WsAccountUpdate struct {
+	// Reason of update e.g. "ORDER", "FUNDING_FEE", "DEPOSIT"
+	Reason    string        `json:"m"`
+	Balances  []*WsBalance  `json:"B"`
+	Positions []*WsPosition `json:"P"`
+}
+
+type WsAccountUpdateEvent struct {
+	EventType string           `json:"e"`
+	Time      int64            `json:"E"`
+	Update    *WsAccountUpdate `json:"a"`
+}
+
+type WsAccountUpdateHandler func(*WsAccountUpdateEvent)
+
+// WsAccountConfig Define leverage and margin mode of symbol
+type WsAccountConfig struct {
+	Symbol        string `json:"s"`
+	LongLeverage  int    `json:"l"`
+	ShortLeverage int    `json:"S"`
+	MarginType    string `json:"mt"`
+}
+
+type WsAccountConfigUpdateEvent struct {
+	EventType string           `json:"e"`
+	Time      int64            `json:"E"`
+	Config    *WsAccountConfig `json:"ac"`
+}
+
+type WsAccountConfigUpdateHandler func(*WsAccountConfigUpdateEvent)
+
+// WsMarginCallPosition Define position at risk of liquidation
+type WsMarginCallPosition struct {
+	Symbol            string           `json:"s"`
+	PositionSide      PositionSideType `json:"ps"`
+	PositionAmt       string           `json:"pa"`
+	MarginType        string           `json:"mt"`
+	IsolatedWallet    string           `json:"iw"`
+	MarkPrice         string           `json:"mp"`
+	UnrealizedPnl     string           `json:"up"`
+	MaintenanceMargin string           `json:"mm"`
+}
+
+type WsMarginCallEvent struct {
+	EventType          string                  `json:"e"`
+	Time               int64                   `json:"E"`
+	CrossWalletBalance string                  `json:"cw"`
+	Positions          []*WsMarginCallPosition `json:"p"`
+}
+
+type WsMarginCallHandler func(*WsMarginCallEvent)
+
+type WsListenKeyExpiredEvent struct {
+	EventType string `json:"e"`
+	Time      int64  `json:"E"`
+	ListenKey string `json:"listenKey"`
+}
+
+type WsListenKeyExpiredHandler func(*WsListenKeyExpiredEvent)
+
+// UserDataStream Routes user data events of listen key to typed handlers.
+// Events without handler are ignored, unknown events go to fallback handler.
+type UserDataStream struct {
+	listenKey string
+
+	accountUpdateHandler       WsAccountUpdateHandler
+	orderUpdateHandler         WsOrderUpdateHandler
+	accountConfigUpdateHandler WsAccountConfigUpdateHandler
+	marginCallHandler          WsMarginCallHandler
+	listenKeyExpiredHandler    WsListenKeyExpiredHandler
+	fallbackHandler            WsHandler
+}
+
+func NewUserDataStream(listenKey string) *UserDataStream {
+	return &UserDataStream{listenKey: listenKey}
+}
+
+// AccountUpdateHandler Handle balance and position changes
+func (s *UserDataStream) AccountUpdateHandler(handler WsAccountUpdateHandler) *UserDataStream {
+	s.accountUpdateHandler = handler
+	return s
+}
+
+// OrderUpdateHandler Handle order status changes and fills
+func (s *UserDataStream) OrderUpdateHandler(handler WsOrderUpdateHandler) *UserDataStream {
+	s.orderUpdateHandler = handler
+	return s
+}
+
+// AccountConfigUpdateHandler Handle leverage and margin mode changes
+func (s *UserDataStream) AccountConfigUpdateHandler(handler WsAccountConfigUpdateHandler) *UserDataStream {
+	s.accountConfigUpdateHandler = handler
+	return s
+}
+
+func (s *UserDataStream) MarginCallHandler(handler WsMarginCallHandler) *UserDataStream {
+	s.marginCallHandler = handler
+	return s
+}
+
+// ListenKeyExpiredHandler Handle expiration of listen key, no events arrive
+// after it until stream is served again with a new listen key
+func (s *UserDataStream) ListenKeyExpiredHandler(handler WsListenKeyExpiredHandler) *UserDataStream {
+	s.listenKeyExpiredHandler = handler
+	return s
+}
+
+// FallbackHandler Handle raw messages of unknown event types
+func (s *UserDataStream) FallbackHandler(handler WsHandler) *UserDataStream {
+	s.fallbackHandler = handler
+	return s
+}
+
+// Serve Connect to user data stream of listen key
+func (s *UserDataStream) Serve(ctx context.Context, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	return wsServe(ctx, nil, newWsConfig(getAccountWsEndpoint(s.listenKey), opts...), s.handler(errHandler), errHandler)
+}
+
+func (s *UserDataStream) handler(errHandler ErrHandler) WsHandler {
+	return func(data []byte) {
+		// Time is matched explicitly, otherwise "E" is decoded into EventType
+		ev := new(struct {
+			EventType string `json:"e"`
+			Time      int64  `json:"E"`
+		})
+		err := json.Unmarshal(data, ev)
+		if err != nil {
+			errHandler(err)
+			return
+		}
+
+		switch ev.EventType {
+		case AccountUpdateEventType:
+			if s.accountUpdateHandler != nil {
+				event := new(WsAccountUpdateEvent)
+				if decodeUserDataEvent(data, event, errHandler) {
+					s.accountUpdateHandler(event)
+				}
+			}
+		case OrderTradeUpdateEventType:
+			if s.orderUpdateHandler != nil {
+				event := new(WsOrderUpdateEvent)
+				if decodeUserDataEvent(data, event, errHandler) && event.Order != nil {
+					s.orderUpdateHandler(event.Order)
+				}
+			}
+		case AccountConfigUpdateEventType:
+			if s.accountConfigUpdateHandler != nil {
+				event := new(WsAccountConfigUpdateEvent)
+				if decodeUserDataEvent(data, event, errHandler) {
+					s.accountConfigUpdateHandler(event)
+				}
+			}
+		case MarginCallEventType:
+			if s.marginCallHandler != nil {
+				event := new(WsMarginCallEvent)
+				if decodeUserDataEvent(data, event, errHandler) {
+					s.marginCallHandler(event)
+				}
+			}
+		case ListenKeyExpiredEventType:
+			if s.listenKeyExpiredHandler != nil {
+				event := new(WsListenKeyExpiredEvent)
+				if decodeUserDataEvent(data, event, errHandler) {
+					s.listenKeyExpiredHandler(event)
+				}
+			}
+		default:
+			if s.fallbackHandler != nil {
+				s.fallbackHandler(data)
+			}
+		}
+	}
+}
+
+func decodeUserDataEvent(data []byte, event interface{}, errHandler ErrHandler) bool {
+	err := json.Unmarshal(data, event)
+	if err != nil {
+		errHandler(err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package bingx
+
+func (s *websocketServiceTestSuite) TestUserDataStream() {
+	data := [][]byte{
+		[]byte(`{
+			"e": "ACCOUNT_UPDATE",
+			"E": 1702719166000,
+			"a": {
+				"m": "ORDER",
+				"B": [{"a": "USDT", "wb": "1000.5", "cw": "990.1", "bc": "0"}],
+				"P": [{"s": "BTC-USDT", "pa": "0.01", "ep": "43000", "up": "1.5", "mt": "isolated", "iw": "43.1", "ps": "LONG"}]
+			}
+		}`),
+		[]byte(`{
+			"e": "ORDER_TRADE_UPDATE",
+			"E": 1702719166001,
+			"o": {
+				"s": "BTC-USDT", "c": "my-order", "i": 42, "S": "BUY", "o": "LIMIT", "q": "0.01",
+				"p": "43000", "sp": "0", "ap": "43000", "x": "TRADE", "X": "FILLED", "N": "USDT",
+				"n": "-0.215", "T": 1702719166001, "wt": "MARK_PRICE", "ps": "LONG", "rp": "0", "z": "0.01", "l": "0.01"
+			}
+		}`),
+		[]byte(`{"e": "ACCOUNT_CONFIG_UPDATE", "E": 1702719166002, "ac": {"s": "BTC-USDT", "l": 10, "S": 5, "mt": "cross"}}`),
+		[]byte(`{
+			"e": "MARGIN_CALL",
+			"E": 1702719166003,
+			"cw": "3.1",
+			"p": [{"s": "BTC-USDT", "ps": "LONG", "pa": "1", "mt": "cross", "iw": "0", "mp": "42000", "up": "-1000", "mm": "210"}]
+		}`),
+		[]byte(`{"e": "listenKeyExpired", "E": 1702719166004, "listenKey": "key"}`),
+		[]byte(`{"e": "SNAPSHOT", "E": 1702719166005}`),
+		[]byte(`{"E": 1702719166006}`),
+		[]byte(`{"e": "ORDER_TRADE_UPDATE", "o": []}`),
+		[]byte(`Pong`),
+	}
+	s.mockWsServe(data, nil)
+	defer s.assertWsServe()
+
+	var accountUpdate *WsAccountUpdateEvent
+	var order *WsOrder
+	var configUpdate *WsAccountConfigUpdateEvent
+	var marginCall *WsMarginCallEvent
+	var expired *WsListenKeyExpiredEvent
+	var unknown []string
+	var errs []error
+	stream, err := NewUserDataStream("key").
+		AccountUpdateHandler(func(event *WsAccountUpdateEvent) {
+			accountUpdate = event
+		}).
+		OrderUpdateHandler(func(event *WsOrder) {
+			order = event
+		}).
+		AccountConfigUpdateHandler(func(event *WsAccountConfigUpdateEvent) {
+			configUpdate = event
+		}).
+		MarginCallHandler(func(event *WsMarginCallEvent) {
+			marginCall = event
+		}).
+		ListenKeyExpiredHandler(func(event *WsListenKeyExpiredEvent) {
+			expired = event
+		}).
+		FallbackHandler(func(data []byte) {
+			unknown = append(unknown, string(data))
+		}).
+		Serve(newContext(), func(err error) {
+			errs = append(errs, err)
+		})
+	r := s.r()
+	r.NoError(err)
+	stream.Close()
+
+	r.Equal(&WsAccountUpdateEvent{
+		EventType: AccountUpdateEventType,
+		Time:      1702719166000,
+		Update: &WsAccountUpdate{
+			Reason:   "ORDER",
+			Balances: []*WsBalance{{Asset: "USDT", WalletBalance: "1000.5", CrossWalletBalance: "990.1", BalanceChange: "0"}},
+			Positions: []*WsPosition{{
+				Symbol:         "BTC-USDT",
+				PositionSide:   LongPositionSideType,
+				PositionAmt:    "0.01",
+				EntryPrice:     "43000",
+				UnrealizedPnl:  "1.5",
+				MarginType:     "isolated",
+				IsolatedWallet: "43.1",
+			}},
+		},
+	}, accountUpdate)
+	r.Equal(&WsOrder{
+		Symbol:        "BTC-USDT",
+		Side:          BuySideType,
+		OrderType:     LimitOrderType,
+		PositionSide:  LongPositionSideType,
+		WorkingType:   MarkOrderWorkingType,
+		Price:         "43000",
+		AveragePrice:  "43000",
+		Quantity:      "0.01",
+		StopPrice:     "0",
+		Status:        FilledOrderStatus,
+		Spec:          TradeOrderSpecType,
+		Timestamp:     1702719166001,
+		OrderId:       42,
+		ClientOrderID: "my-order",
+		LastFilledQty: "0.01",
+		FilledQty:     "0.01",
+		FeeAsset:      "USDT",
+		Fee:           "-0.215",
+		RealizedPnl:   "0",
+	}, order)
+	r.Equal(&WsAccountConfigUpdateEvent{
+		EventType: AccountConfigUpdateEventType,
+		Time:      1702719166002,
+		Config:    &WsAccountConfig{Symbol: "BTC-USDT", LongLeverage: 10, ShortLeverage: 5, MarginType: "cross"},
+	}, configUpdate)
+	r.Equal(&WsMarginCallEvent{
+		EventType:          MarginCallEventType,
+		Time:               1702719166003,
+		CrossWalletBalance: "3.1",
+		Positions: []*WsMarginCallPosition{{
+			Symbol:            "BTC-USDT",
+			PositionSide:      LongPositionSideType,
+			PositionAmt:       "1",
+			MarginType:        "cross",
+			IsolatedWallet:    "0",
+			MarkPrice:         "42000",
+			UnrealizedPnl:     "-1000",
+			MaintenanceMargin: "210",
+		}},
+	}, marginCall)
+	r.Equal(&WsListenKeyExpiredEvent{EventType: ListenKeyExpiredEventType, Time: 1702719166004, ListenKey: "key"}, expired)
+	r.Equal([]string{`{"e": "SNAPSHOT", "E": 1702719166005}`, `{"E": 1702719166006}`}, unknown)
+	r.Len(errs, 2)
+}
+
+func (s *websocketServiceTestSuite) TestOrderUpdateServeIgnoresOtherEvents() {
+	data := [][]byte{
+		[]byte(`{"e": "listenKeyExpired", "E": 1702719166004, "listenKey": "key"}`),
+		[]byte(`{"E": 1702719166006}`),
+		[]byte(`{"e": "ORDER_TRADE_UPDATE", "E": 1702719166001, "o": {"s": "BTC-USDT", "i": 42, "X": "NEW"}}`),
+	}
+	s.mockWsServe(data, nil)
+	defer s.assertWsServe()
+
+	var orders []*WsOrder
+	stream, err := WsOrderUpdateServe(newContext(), "key", func(order *WsOrder) {
+		orders = append(orders, order)
+	}, func(err error) {
+		s.r().NoError(err)
+	})
+	r := s.r()
+	r.NoError(err)
+	stream.Close()
+
+	r.Equal([]*WsOrder{{Symbol: "BTC-USDT", OrderId: 42, Status: NewOrderStatus}}, orders)
+}
@@ -0,0 +1,236 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// OverflowPolicy Define what stream does when its handler or channel consumer does not keep up
+type OverflowPolicy int
+
+const (
+	// OverflowBlock waits for consumer, connection is not read meanwhile once its queue is full,
+	// so heartbeats stall as well
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest buffered message or event to make room for new one
+	OverflowDropOldest
+	// OverflowDisconnect closes stream with ErrWsOverflow
+	OverflowDisconnect
+)
+
+// ErrWsOverflow consumer of stream is too slow
+var ErrWsOverflow = errors.New("bingx: websocket consumer is too slow")
+
+// WsChan Typed stream of events delivered through channel.
+// Events are read with C or Seq, stream stops when context is done or Close is called.
+// With OverflowBlock a consumer which falls behind by more than the buffer stalls connection
+// heartbeats and may get it dropped by server, use OverflowDropOldest or OverflowDisconnect to avoid it.
+type WsChan[T any] struct {
+	policy OverflowPolicy
+	c      chan T
+	errC   chan error
+
+	mu  sync.Mutex
+	err error
+
+	quitC    chan struct{}
+	quitOnce sync.Once
+	doneC    chan struct{}
+}
+
+func newWsChan[T any](config *WsConfig) *WsChan[T] {
+	size := config.BufferSize
+	if size < 0 {
+		size = 0
+	}
+	return &WsChan[T]{
+		policy: config.Overflow,
+		c:      make(chan T, size),
+		errC:   make(chan error, size),
+		quitC:  make(chan struct{}),
+		doneC:  make(chan struct{}),
+	}
+}
+
+// C Channel of events, closed when stream stops
+func (c *WsChan[T]) C() <-chan T {
+	return c.c
+}
+
+// Errors Channel of errors which do not stop stream e.g. malformed messages or reconnects.
+// Errors are dropped when channel is full.
+func (c *WsChan[T]) Errors() <-chan error {
+	return c.errC
+}
+
+// Done Closed when stream stops
+func (c *WsChan[T]) Done() <-chan struct{} {
+	return c.doneC
+}
+
+// Err Reason stream stopped: context error, ErrWsOverflow or the last connection error.
+// It is nil while stream runs or after Close.
+func (c *WsChan[T]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Close Stop stream and wait until channels are closed, safe to call many times.
+// Returns reason stream stopped if it stopped before Close.
+func (c *WsChan[T]) Close() error {
+	c.quit(nil)
+	<-c.doneC
+	return c.Err()
+}
+
+// Seq Iterate events until stream stops or yield returns false,
+// with Go 1.23 it may be used as `for event := range stream.Seq()`
+func (c *WsChan[T]) Seq() func(yield func(T) bool) {
+	return func(yield func(T) bool) {
+		for event := range c.c {
+			if !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+func (c *WsChan[T]) quit(err error) {
+	c.quitOnce.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+		close(c.quitC)
+	})
+}
+
+// push is called by connection dispatcher. Blocking fills connection queue and then stops its reader,
+// so server pings are not answered and pongs do not extend read deadline until consumer catches up.
+func (c *WsChan[T]) push(event T) {
+	select {
+	case <-c.quitC:
+		return
+	default:
+	}
+
+	switch c.policy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case c.c <- event:
+				return
+			default:
+			}
+			select {
+			case <-c.c:
+			default:
+			}
+		}
+	case OverflowDisconnect:
+		select {
+		case c.c <- event:
+		default:
+			c.quit(ErrWsOverflow)
+		}
+	default:
+		select {
+		case c.c <- event:
+		case <-c.quitC:
+		}
+	}
+}
+
+func (c *WsChan[T]) pushErr(err error) {
+	select {
+	case c.errC <- err:
+	default:
+	}
+}
+
+// run closes channels after the last event, stream does not call handlers once it is done
+func (c *WsChan[T]) run(stream *Stream) {
+	select {
+	case <-c.quitC:
+	case <-stream.Done():
+	}
+
+	stream.Close()
+	c.quit(stream.Err())
+
+	close(c.c)
+	close(c.errC)
+	close(c.doneC)
+}
+
+// serveWsChan adapts callback based serve function to channel stream
+func serveWsChan[T any](opts []WsOption, serve func(handler func(T), errHandler ErrHandler) (*Stream, error)) (*WsChan[T], error) {
+	c := newWsChan[T](newWsConfig("", opts...))
+
+	stream, err := serve(c.push, c.pushErr)
+	if err != nil {
+		return nil, err
+	}
+
+	go c.run(stream)
+
+	return c, nil
+}
+
+// WsKlineChan Stream kline events through channel
+func WsKlineChan(ctx context.Context, symbol string, interval Interval, opts ...WsOption) (*WsChan[*WsKlineEvent], error) {
+	return serveWsChan(opts, func(handler func(*WsKlineEvent), errHandler ErrHandler) (*Stream, error) {
+		return WsKlineServe(ctx, symbol, interval, handler, errHandler, opts...)
+	})
+}
+
+// WsDepthChan Stream depth events through channel
+func WsDepthChan(ctx context.Context, symbol string, level int, opts ...WsOption) (*WsChan[*WsDepthEvent], error) {
+	return serveWsChan(opts, func(handler func(*WsDepthEvent), errHandler ErrHandler) (*Stream, error) {
+		return WsDepthServe(ctx, symbol, level, handler, errHandler, opts...)
+	})
+}
+
+// WsTradeChan Stream public trades through channel
+func WsTradeChan(ctx context.Context, symbol string, opts ...WsOption) (*WsChan[*WsTradeEvent], error) {
+	return serveWsChan(opts, func(handler func(*WsTradeEvent), errHandler ErrHandler) (*Stream, error) {
+		return WsTradeServe(ctx, symbol, handler, errHandler, opts...)
+	})
+}
+
+// WsTickerChan Stream 24h ticker through channel
+func WsTickerChan(ctx context.Context, symbol string, opts ...WsOption) (*WsChan[*WsTickerEvent], error) {
+	return serveWsChan(opts, func(handler func(*WsTickerEvent), errHandler ErrHandler) (*Stream, error) {
+		return WsTickerServe(ctx, symbol, handler, errHandler, opts...)
+	})
+}
+
+// WsMarkPriceChan Stream mark price through channel
+func WsMarkPriceChan(ctx context.Context, symbol string, opts ...WsOption) (*WsChan[*WsMarkPriceEvent], error) {
+	return serveWsChan(opts, func(handler func(*WsMarkPriceEvent), errHandler ErrHandler) (*Stream, error) {
+		return WsMarkPriceServe(ctx, symbol, handler, errHandler, opts...)
+	})
+}
+
+// WsBookTickerChan Stream best bid and ask through channel
+func WsBookTickerChan(ctx context.Context, symbol string, opts ...WsOption) (*WsChan[*WsBookTickerEvent], error) {
+	return serveWsChan(opts, func(handler func(*WsBookTickerEvent), errHandler ErrHandler) (*Stream, error) {
+		return WsBookTickerServe(ctx, symbol, handler, errHandler, opts...)
+	})
+}
+
+// WsLastPriceChan Stream last traded price through channel
+func WsLastPriceChan(ctx context.Context, symbol string, opts ...WsOption) (*WsChan[*WsLastPriceEvent], error) {
+	return serveWsChan(opts, func(handler func(*WsLastPriceEvent), errHandler ErrHandler) (*Stream, error) {
+		return WsLastPriceServe(ctx, symbol, handler, errHandler, opts...)
+	})
+}
+
+// WsOrderUpdateChan Stream order updates of account through channel
+func WsOrderUpdateChan(ctx context.Context, listenKey string, opts ...WsOption) (*WsChan[*WsOrder], error) {
+	return serveWsChan(opts, func(handler func(*WsOrder), errHandler ErrHandler) (*Stream, error) {
+		return WsOrderUpdateServe(ctx, listenKey, handler, errHandler, opts...)
+	})
+}
@@ -0,0 +1,129 @@
+package bingx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+type websocketChanTestSuite struct {
+	wsServerTestSuite
+}
+
+func TestWebsocketChan(t *testing.T) {
+	suite.Run(t, new(websocketChanTestSuite))
+}
+
+func (s *websocketChanTestSuite) writeKline(conn *websocket.Conn, t int64) {
+	msg := fmt.Sprintf(`{"code":0,"dataType":"BTC-USDT@kline_1m","data":[{"c":"1","o":"1","h":"1","l":"1","v":"1","T":%d}]}`, t)
+	s.Require().NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage(msg)))
+}
+
+func (s *websocketChanTestSuite) TestSlowConsumerDoesNotBlockPing() {
+	r := s.Require()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// channel fills up and blocks dispatcher, connection queue still has room for the rest
+	stream, err := WsKlineChan(ctx, "BTC-USDT", Interval1, WithWsEndpoint(s.endpoint()), WithWsBuffer(3))
+	r.NoError(err)
+
+	conn := s.accept()
+	s.readText(conn)
+	for i := int64(1); i <= 5; i++ {
+		s.writeKline(conn, i*60000)
+	}
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage("Ping")))
+	r.Equal("Pong", s.readText(conn))
+
+	var times []float64
+	for event := range stream.C() {
+		times = append(times, event.Time)
+		if len(times) == 5 {
+			cancel()
+		}
+	}
+	// the first kline is emitted once more when it is completed by the next one
+	r.Equal([]float64{60000, 60000, 120000, 180000, 240000}, times)
+	r.ErrorIs(stream.Err(), context.Canceled)
+}
+
+func (s *websocketChanTestSuite) TestSeq() {
+	r := s.Require()
+	stream, err := WsKlineChan(context.Background(), "BTC-USDT", Interval1, WithWsEndpoint(s.endpoint()))
+	r.NoError(err)
+
+	conn := s.accept()
+	s.readText(conn)
+	for i := int64(1); i <= 3; i++ {
+		s.writeKline(conn, i*60000)
+	}
+
+	var times []float64
+	stream.Seq()(func(event *WsKlineEvent) bool {
+		times = append(times, event.Time)
+		return len(times) < 3
+	})
+	r.Equal([]float64{60000, 60000, 120000}, times)
+
+	r.NoError(stream.Close())
+	r.NoError(stream.Close())
+	r.NoError(stream.Err())
+	_, ok := <-stream.C()
+	r.False(ok)
+}
+
+func (s *websocketChanTestSuite) TestConnectionLost() {
+	r := s.Require()
+	stream, err := WsKlineChan(context.Background(), "BTC-USDT", Interval1, WithWsEndpoint(s.endpoint()), WithWsReconnect(false))
+	r.NoError(err)
+
+	conn := s.accept()
+	s.readText(conn)
+	conn.Close()
+
+	select {
+	case <-stream.Done():
+	case <-time.After(5 * time.Second):
+		s.FailNow("stream is not closed")
+	}
+	r.Error(stream.Err())
+	r.Error(<-stream.Errors())
+}
+
+func (s *websocketChanTestSuite) TestDropOldest() {
+	r := s.Require()
+	c := newWsChan[int](newWsConfig("", WithWsBuffer(2), WithWsOverflow(OverflowDropOldest)))
+	for i := 1; i <= 4; i++ {
+		c.push(i)
+	}
+	r.Equal(3, <-c.C())
+	r.Equal(4, <-c.C())
+	r.Len(c.C(), 0)
+}
+
+func (s *websocketChanTestSuite) TestDisconnect() {
+	r := s.Require()
+	doneC := make(chan struct{})
+	stream := newStream(context.Background(), func() {
+		close(doneC)
+	}, func() error {
+		return nil
+	}, doneC)
+
+	c := newWsChan[int](newWsConfig("", WithWsBuffer(1), WithWsOverflow(OverflowDisconnect)))
+	go c.run(stream)
+	c.push(1)
+	c.push(2)
+	c.push(3)
+
+	<-c.Done()
+	r.ErrorIs(c.Err(), ErrWsOverflow)
+	r.Equal(1, <-c.C())
+	_, ok := <-c.C()
+	r.False(ok)
+}
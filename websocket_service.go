@@ -1,7 +1,9 @@
 package bingx
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -40,6 +42,122 @@ type RequestEvent struct {
 	DataType string        `json:"dataType"`
 }
 
+// ResponseEvent Define reply to RequestEvent, Code is zero on success
+type ResponseEvent struct {
+	Id       string `json:"id"`
+	Code     int    `json:"code"`
+	Msg      string `json:"msg"`
+	DataType string `json:"dataType"`
+}
+
+// parseResponseEvent returns reply to request, false if data is a regular event
+func parseResponseEvent(data []byte) (*ResponseEvent, bool) {
+	ev := new(ResponseEvent)
+	err := json.Unmarshal(data, ev)
+	if err != nil || ev.Id == "" {
+		return nil, false
+	}
+	return ev, true
+}
+
+// ErrWsRequestTimeout request was not acknowledged in time
+var ErrWsRequestTimeout = errors.New("bingx: websocket request was not acknowledged in time")
+
+// WsRequestError Define rejected subscribe or unsubscribe request, e.g. unknown symbol or data type
+type WsRequestError struct {
+	Id       string
+	ReqType  WsRequestType
+	DataType string
+	Code     int
+	Msg      string
+}
+
+func (e WsRequestError) Error() string {
+	return fmt.Sprintf("<WsRequestError> code=%d, msg=%s, reqType=%s, dataType=%s", e.Code, e.Msg, e.ReqType, e.DataType)
+}
+
+// wsNumber Define numeric field sent either as JSON string or number
+type wsNumber string
+
+func (n *wsNumber) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		err := json.Unmarshal(data, &s)
+		if err != nil {
+			return err
+		}
+		*n = wsNumber(s)
+		return nil
+	}
+	*n = wsNumber(data)
+	return nil
+}
+
+func parseWsFloat(field, value string) (float64, error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bingx: invalid %s %q: %w", field, value, err)
+	}
+	return v, nil
+}
+
+// formatFloat formats number the way exchange sends it, without exponent and trailing zeros
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// numberParser parses several fields keeping the first error
+type numberParser struct {
+	err error
+	// optional empty values are zero instead of error
+	optional bool
+}
+
+func (p *numberParser) parse(field, value string) float64 {
+	if p.err != nil || (p.optional && value == "") {
+		return 0
+	}
+	v, err := parseWsFloat(field, value)
+	p.err = err
+	return v
+}
+
+// decodeWsEvent unmarshals message into v if it is event of dataType
+func decodeWsEvent(data []byte, dataType string, v interface{}) (bool, error) {
+	ev := new(struct {
+		DataType string `json:"dataType"`
+	})
+	err := json.Unmarshal(data, ev)
+	if err != nil {
+		return false, err
+	}
+
+	if ev.DataType != dataType {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(data, v)
+}
+
+// withWsResponseHandler reports rejection of reqEvent and passes other messages to handler
+func withWsResponseHandler(reqEvent RequestEvent, handler WsHandler, errHandler ErrHandler) WsHandler {
+	return func(data []byte) {
+		if res, ok := parseResponseEvent(data); ok {
+			if res.Code != 0 {
+				errHandler(&WsRequestError{
+					Id:       res.Id,
+					ReqType:  reqEvent.ReqType,
+					DataType: reqEvent.DataType,
+					Code:     res.Code,
+					Msg:      res.Msg,
+				})
+			}
+			return
+		}
+		handler(data)
+	}
+}
+
 type WsKlineEvent struct {
 	Symbol    string  `json:"s"`
 	Open      float64 `json:"o"`
@@ -53,54 +171,56 @@ type WsKlineEvent struct {
 
 type WsKlineHandler func(*WsKlineEvent)
 
-func WsKlineServe(symbol string, interval Interval, handler WsKlineHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
-	// Symbol e.g. "BTC-USDT"
-	// Interval e.g. "1m", "3h"
-	reqEvent := RequestEvent{
-		Id:       uuid.New(),
-		ReqType:  SubscribeRequestType,
-		DataType: fmt.Sprintf("%s@kline_%s", symbol, interval),
-	}
+func klineDataType(symbol string, interval Interval) string {
+	return fmt.Sprintf("%s@kline_%s", symbol, interval)
+}
 
-	var lastEvent *WsKlineEvent
+// parseWsKlines decodes kline message, ok is false for messages of other data types
+func parseWsKlines(data []byte, dataType string) (events []*WsKlineEvent, ok bool, err error) {
+	_eventData := new(struct {
+		Symbol string `json:"s"`
+		Data   []struct {
+			Open   wsNumber `json:"o"`
+			Close  wsNumber `json:"c"`
+			High   wsNumber `json:"h"`
+			Low    wsNumber `json:"l"`
+			Volume wsNumber `json:"v"`
+			Time   float64  `json:"T"`
+		} `json:"data"`
+	})
+	ok, err = decodeWsEvent(data, dataType, _eventData)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
 
-	var wsHandler = func(data []byte) {
-		ev := new(Event)
-		err := json.Unmarshal(data, ev)
-		if err != nil {
-			errHandler(err)
-			return
+	for _, kline := range _eventData.Data {
+		p := new(numberParser)
+		event := &WsKlineEvent{
+			Symbol: _eventData.Symbol,
+			Open:   p.parse("open", string(kline.Open)),
+			Close:  p.parse("close", string(kline.Close)),
+			High:   p.parse("high", string(kline.High)),
+			Low:    p.parse("low", string(kline.Low)),
+			Volume: p.parse("volume", string(kline.Volume)),
+			Time:   kline.Time,
 		}
+		if p.err != nil {
+			return events, true, p.err
+		}
+		events = append(events, event)
+	}
 
-		if ev.DataType == reqEvent.DataType {
-			_eventData := new(struct {
-				Symbol string                   `json:"s"`
-				Data   []map[string]interface{} `json:"data"`
-			})
-			err := json.Unmarshal(data, _eventData)
-			if err != nil {
-				errHandler(err)
-				return
-			}
+	return events, true, nil
+}
 
-			c, _ := strconv.ParseFloat(_eventData.Data[0]["c"].(string), 64)
-			h, _ := strconv.ParseFloat(_eventData.Data[0]["h"].(string), 64)
-			l, _ := strconv.ParseFloat(_eventData.Data[0]["l"].(string), 64)
-			o, _ := strconv.ParseFloat(_eventData.Data[0]["o"].(string), 64)
-			v, _ := strconv.ParseFloat(_eventData.Data[0]["v"].(string), 64)
-			t := _eventData.Data[0]["T"].(float64)
-
-			event := &WsKlineEvent{
-				Symbol:    _eventData.Symbol,
-				Open:      o,
-				Close:     c,
-				High:      h,
-				Low:       l,
-				Volume:    v,
-				Time:      t,
-				Completed: false,
-			}
+// newWsKlineHandler parses kline events of dataType and marks previous candle completed when next one starts
+func newWsKlineHandler(dataType string, handler WsKlineHandler, errHandler ErrHandler) WsHandler {
+	var lastEvent *WsKlineEvent
+
+	return func(data []byte) {
+		events, _, err := parseWsKlines(data, dataType)
 
+		for _, event := range events {
 			if lastEvent == nil {
 				lastEvent = event
 			}
@@ -112,32 +232,64 @@ func WsKlineServe(symbol string, interval Interval, handler WsKlineHandler, errH
 			handler(lastEvent)
 
 			lastEvent = event
+		}
 
+		if err != nil {
+			errHandler(err)
 		}
+	}
+}
 
+// wsServeDataType subscribes to single dataType of market stream
+func wsServeDataType(ctx context.Context, dataType string, handler WsHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	reqEvent := RequestEvent{
+		Id:       uuid.New(),
+		ReqType:  SubscribeRequestType,
+		DataType: dataType,
 	}
 
 	initMessage, err := json.Marshal(reqEvent)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return wsServe(initMessage, newWsConfig(getWsEndpoint()), wsHandler, errHandler)
+	wsHandler := withWsResponseHandler(reqEvent, handler, errHandler)
+
+	return wsServe(ctx, initMessage, newWsConfig(getWsEndpoint(), opts...), wsHandler, errHandler)
 }
 
+func WsKlineServe(ctx context.Context, symbol string, interval Interval, handler WsKlineHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	// Symbol e.g. "BTC-USDT"
+	// Interval e.g. "1m", "3h"
+	dataType := klineDataType(symbol, interval)
+	return wsServeDataType(ctx, dataType, newWsKlineHandler(dataType, handler, errHandler), errHandler, opts...)
+}
+
+// WsOrder Define order of ORDER_TRADE_UPDATE event
 type WsOrder struct {
-	Symbol        string        `json:"s"`
-	Side          SideType      `json:"S"`
-	OrderType     OrderType     `json:"o"`
-	Price         string        `json:"p"`
-	AveragePrice  string        `json:"ap"`
-	Quantity      string        `json:"q"`
-	StopPrice     string        `json:"sp"`
-	Status        OrderStatus   `json:"X"`
-	Spec          OrderSpecType `json:"x"`
-	Timestamp     int           `json:"T"`
-	OrderId       int64         `json:"i"`
-	ClientOrderID string        `json:"c"`
+	Symbol        string           `json:"s"`
+	Side          SideType         `json:"S"`
+	OrderType     OrderType        `json:"o"`
+	PositionSide  PositionSideType `json:"ps"`
+	WorkingType   OrderWorkingType `json:"wt"`
+	Price         string           `json:"p"`
+	AveragePrice  string           `json:"ap"`
+	Quantity      string           `json:"q"`
+	StopPrice     string           `json:"sp"`
+	Status        OrderStatus      `json:"X"`
+	Spec          OrderSpecType    `json:"x"`
+	Timestamp     int              `json:"T"`
+	OrderId       int64            `json:"i"`
+	ClientOrderID string           `json:"c"`
+	// LastFilledQty quantity filled by this trade
+	LastFilledQty string `json:"l"`
+	// LastFilledPrice price of this trade, may be missing
+	LastFilledPrice string `json:"L"`
+	// FilledQty accumulated filled quantity of order
+	FilledQty   string `json:"z"`
+	FeeAsset    string `json:"N"`
+	Fee         string `json:"n"`
+	RealizedPnl string `json:"rp"`
 }
 
 type WsOrderUpdateEvent struct {
@@ -148,27 +300,409 @@ type WsOrderUpdateEvent struct {
 
 type WsOrderUpdateHandler func(*WsOrder)
 
-func WsOrderUpdateServe(listenKey string, handler WsOrderUpdateHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
-	var wsHandler = func(data []byte) {
+// WsOrderUpdateServe Serve order updates of account, other user data events are ignored.
+// Use UserDataStream to handle all of them.
+func WsOrderUpdateServe(ctx context.Context, listenKey string, handler WsOrderUpdateHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	stream := NewUserDataStream(listenKey).OrderUpdateHandler(handler)
+	return wsServe(ctx, nil, newWsConfig(getAccountWsEndpoint(listenKey), opts...), stream.handler(errHandler), errHandler)
+}
 
-		var evMap map[string]interface{}
-		err := json.Unmarshal(data, &evMap)
+// WsDepthLevel Define order book level of depth stream
+type WsDepthLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+type WsDepthEvent struct {
+	Symbol string
+	// Time of event in milliseconds, zero if server did not send it
+	Time int64
+	Bids []WsDepthLevel
+	Asks []WsDepthLevel
+}
+
+type WsDepthHandler func(*WsDepthEvent)
+
+func depthDataType(symbol string, level int) string {
+	return fmt.Sprintf("%s@depth%d", symbol, level)
+}
+
+func parseWsDepthLevels(levels []PriceLevel) ([]WsDepthLevel, error) {
+	res := make([]WsDepthLevel, len(levels))
+	for i, level := range levels {
+		price, err := parseWsFloat("price", level.Price)
+		if err != nil {
+			return nil, err
+		}
+		quantity, err := parseWsFloat("quantity", level.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = WsDepthLevel{Price: price, Quantity: quantity}
+	}
+	return res, nil
+}
+
+func newWsDepthHandler(symbol string, dataType string, handler WsDepthHandler, errHandler ErrHandler) WsHandler {
+	return func(data []byte) {
+		ev := new(struct {
+			Time int64 `json:"ts"`
+			Data *struct {
+				Bids []PriceLevel `json:"bids"`
+				Asks []PriceLevel `json:"asks"`
+			} `json:"data"`
+		})
+		ok, err := decodeWsEvent(data, dataType, ev)
+		if err != nil {
+			errHandler(err)
+			return
+		}
+		if !ok || ev.Data == nil {
+			return
+		}
+
+		bids, err := parseWsDepthLevels(ev.Data.Bids)
+		if err != nil {
+			errHandler(err)
+			return
+		}
+		asks, err := parseWsDepthLevels(ev.Data.Asks)
 		if err != nil {
 			errHandler(err)
 			return
 		}
 
-		if evMap["e"].(string) == "ORDER_TRADE_UPDATE" {
-			event := new(WsOrderUpdateEvent)
-			err = json.Unmarshal(data, event)
-			if err != nil {
-				errHandler(err)
+		handler(&WsDepthEvent{
+			Symbol: symbol,
+			Time:   ev.Time,
+			Bids:   bids,
+			Asks:   asks,
+		})
+	}
+}
+
+// WsDepthServe Serve top level depth snapshots, level is one of 5, 10, 20, 50, 100
+func WsDepthServe(ctx context.Context, symbol string, level int, handler WsDepthHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	dataType := depthDataType(symbol, level)
+	return wsServeDataType(ctx, dataType, newWsDepthHandler(symbol, dataType, handler, errHandler), errHandler, opts...)
+}
+
+type WsTradeEvent struct {
+	Symbol       string
+	Price        float64
+	Quantity     float64
+	Time         int64
+	IsBuyerMaker bool
+}
+
+type WsTradeHandler func(*WsTradeEvent)
+
+func tradeDataType(symbol string) string {
+	return symbol + "@trade"
+}
+
+func newWsTradeHandler(dataType string, handler WsTradeHandler, errHandler ErrHandler) WsHandler {
+	return func(data []byte) {
+		ev := new(struct {
+			Data []struct {
+				Symbol       string   `json:"s"`
+				Price        wsNumber `json:"p"`
+				Quantity     wsNumber `json:"q"`
+				Time         int64    `json:"T"`
+				IsBuyerMaker bool     `json:"m"`
+			} `json:"data"`
+		})
+		ok, err := decodeWsEvent(data, dataType, ev)
+		if err != nil {
+			errHandler(err)
+			return
+		}
+		if !ok {
+			return
+		}
+
+		for _, trade := range ev.Data {
+			p := new(numberParser)
+			event := &WsTradeEvent{
+				Symbol:       trade.Symbol,
+				Price:        p.parse("price", string(trade.Price)),
+				Quantity:     p.parse("quantity", string(trade.Quantity)),
+				Time:         trade.Time,
+				IsBuyerMaker: trade.IsBuyerMaker,
+			}
+			if p.err != nil {
+				errHandler(p.err)
 				return
 			}
-			handler(event.Order)
+			handler(event)
+		}
+	}
+}
+
+// WsTradeServe Serve public trades, handler is called for every trade
+func WsTradeServe(ctx context.Context, symbol string, handler WsTradeHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	dataType := tradeDataType(symbol)
+	return wsServeDataType(ctx, dataType, newWsTradeHandler(dataType, handler, errHandler), errHandler, opts...)
+}
+
+// WsTickerEvent Define 24h rolling window statistics
+type WsTickerEvent struct {
+	Symbol             string
+	Time               int64
+	PriceChange        float64
+	PriceChangePercent float64
+	LastPrice          float64
+	LastQty            float64
+	High               float64
+	Low                float64
+	Volume             float64
+	QuoteVolume        float64
+	Open               float64
+	OpenTime           int64
+	CloseTime          int64
+	BidPrice           float64
+	BidQty             float64
+	AskPrice           float64
+	AskQty             float64
+}
+
+type WsTickerHandler func(*WsTickerEvent)
+
+func tickerDataType(symbol string) string {
+	return symbol + "@ticker"
+}
+
+func newWsTickerHandler(dataType string, handler WsTickerHandler, errHandler ErrHandler) WsHandler {
+	return func(data []byte) {
+		ev := new(struct {
+			Data *struct {
+				// Event is matched explicitly, otherwise "e" is decoded into Time
+				Event              string   `json:"e"`
+				Time               int64    `json:"E"`
+				Symbol             string   `json:"s"`
+				PriceChange        wsNumber `json:"p"`
+				PriceChangePercent wsNumber `json:"P"`
+				LastPrice          wsNumber `json:"c"`
+				LastQty            wsNumber `json:"L"`
+				High               wsNumber `json:"h"`
+				Low                wsNumber `json:"l"`
+				Volume             wsNumber `json:"v"`
+				QuoteVolume        wsNumber `json:"q"`
+				Open               wsNumber `json:"o"`
+				OpenTime           int64    `json:"O"`
+				CloseTime          int64    `json:"C"`
+				BidPrice           wsNumber `json:"B"`
+				BidQty             wsNumber `json:"b"`
+				AskPrice           wsNumber `json:"A"`
+				AskQty             wsNumber `json:"a"`
+			} `json:"data"`
+		})
+		ok, err := decodeWsEvent(data, dataType, ev)
+		if err != nil {
+			errHandler(err)
+			return
+		}
+		if !ok || ev.Data == nil {
+			return
+		}
+
+		d := ev.Data
+		p := new(numberParser)
+		event := &WsTickerEvent{
+			Symbol:             d.Symbol,
+			Time:               d.Time,
+			PriceChange:        p.parse("price change", string(d.PriceChange)),
+			PriceChangePercent: p.parse("price change percent", string(d.PriceChangePercent)),
+			LastPrice:          p.parse("last price", string(d.LastPrice)),
+			LastQty:            p.parse("last quantity", string(d.LastQty)),
+			High:               p.parse("high", string(d.High)),
+			Low:                p.parse("low", string(d.Low)),
+			Volume:             p.parse("volume", string(d.Volume)),
+			QuoteVolume:        p.parse("quote volume", string(d.QuoteVolume)),
+			Open:               p.parse("open", string(d.Open)),
+			OpenTime:           d.OpenTime,
+			CloseTime:          d.CloseTime,
+			BidPrice:           p.parse("bid price", string(d.BidPrice)),
+			BidQty:             p.parse("bid quantity", string(d.BidQty)),
+			AskPrice:           p.parse("ask price", string(d.AskPrice)),
+			AskQty:             p.parse("ask quantity", string(d.AskQty)),
+		}
+		if p.err != nil {
+			errHandler(p.err)
+			return
+		}
+		handler(event)
+	}
+}
+
+// WsTickerServe Serve 24h ticker
+func WsTickerServe(ctx context.Context, symbol string, handler WsTickerHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	dataType := tickerDataType(symbol)
+	return wsServeDataType(ctx, dataType, newWsTickerHandler(dataType, handler, errHandler), errHandler, opts...)
+}
+
+type WsMarkPriceEvent struct {
+	Symbol    string
+	Time      int64
+	MarkPrice float64
+}
+
+type WsMarkPriceHandler func(*WsMarkPriceEvent)
+
+func markPriceDataType(symbol string) string {
+	return symbol + "@markPrice"
+}
+
+func newWsMarkPriceHandler(dataType string, handler WsMarkPriceHandler, errHandler ErrHandler) WsHandler {
+	return func(data []byte) {
+		ev := new(struct {
+			Data *struct {
+				Event     string   `json:"e"`
+				Time      int64    `json:"E"`
+				Symbol    string   `json:"s"`
+				MarkPrice wsNumber `json:"p"`
+			} `json:"data"`
+		})
+		ok, err := decodeWsEvent(data, dataType, ev)
+		if err != nil {
+			errHandler(err)
+			return
+		}
+		if !ok || ev.Data == nil {
+			return
 		}
 
+		p := new(numberParser)
+		event := &WsMarkPriceEvent{
+			Symbol:    ev.Data.Symbol,
+			Time:      ev.Data.Time,
+			MarkPrice: p.parse("mark price", string(ev.Data.MarkPrice)),
+		}
+		if p.err != nil {
+			errHandler(p.err)
+			return
+		}
+		handler(event)
 	}
+}
+
+// WsMarkPriceServe Serve mark price updates
+func WsMarkPriceServe(ctx context.Context, symbol string, handler WsMarkPriceHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	dataType := markPriceDataType(symbol)
+	return wsServeDataType(ctx, dataType, newWsMarkPriceHandler(dataType, handler, errHandler), errHandler, opts...)
+}
+
+type WsBookTickerEvent struct {
+	Symbol   string
+	UpdateId int64
+	Time     int64
+	BidPrice float64
+	BidQty   float64
+	AskPrice float64
+	AskQty   float64
+}
+
+type WsBookTickerHandler func(*WsBookTickerEvent)
+
+func bookTickerDataType(symbol string) string {
+	return symbol + "@bookTicker"
+}
+
+func newWsBookTickerHandler(dataType string, handler WsBookTickerHandler, errHandler ErrHandler) WsHandler {
+	return func(data []byte) {
+		ev := new(struct {
+			Data *struct {
+				UpdateId int64    `json:"u"`
+				Event    string   `json:"e"`
+				Time     int64    `json:"E"`
+				Symbol   string   `json:"s"`
+				BidPrice wsNumber `json:"b"`
+				BidQty   wsNumber `json:"B"`
+				AskPrice wsNumber `json:"a"`
+				AskQty   wsNumber `json:"A"`
+			} `json:"data"`
+		})
+		ok, err := decodeWsEvent(data, dataType, ev)
+		if err != nil {
+			errHandler(err)
+			return
+		}
+		if !ok || ev.Data == nil {
+			return
+		}
+
+		d := ev.Data
+		p := new(numberParser)
+		event := &WsBookTickerEvent{
+			Symbol:   d.Symbol,
+			UpdateId: d.UpdateId,
+			Time:     d.Time,
+			BidPrice: p.parse("bid price", string(d.BidPrice)),
+			BidQty:   p.parse("bid quantity", string(d.BidQty)),
+			AskPrice: p.parse("ask price", string(d.AskPrice)),
+			AskQty:   p.parse("ask quantity", string(d.AskQty)),
+		}
+		if p.err != nil {
+			errHandler(p.err)
+			return
+		}
+		handler(event)
+	}
+}
+
+// WsBookTickerServe Serve best bid and ask updates
+func WsBookTickerServe(ctx context.Context, symbol string, handler WsBookTickerHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	dataType := bookTickerDataType(symbol)
+	return wsServeDataType(ctx, dataType, newWsBookTickerHandler(dataType, handler, errHandler), errHandler, opts...)
+}
+
+type WsLastPriceEvent struct {
+	Symbol    string
+	Time      int64
+	LastPrice float64
+}
+
+type WsLastPriceHandler func(*WsLastPriceEvent)
+
+func lastPriceDataType(symbol string) string {
+	return symbol + "@lastPrice"
+}
+
+func newWsLastPriceHandler(dataType string, handler WsLastPriceHandler, errHandler ErrHandler) WsHandler {
+	return func(data []byte) {
+		ev := new(struct {
+			Data *struct {
+				Event     string   `json:"e"`
+				Time      int64    `json:"E"`
+				Symbol    string   `json:"s"`
+				LastPrice wsNumber `json:"c"`
+			} `json:"data"`
+		})
+		ok, err := decodeWsEvent(data, dataType, ev)
+		if err != nil {
+			errHandler(err)
+			return
+		}
+		if !ok || ev.Data == nil {
+			return
+		}
+
+		p := new(numberParser)
+		event := &WsLastPriceEvent{
+			Symbol:    ev.Data.Symbol,
+			Time:      ev.Data.Time,
+			LastPrice: p.parse("last price", string(ev.Data.LastPrice)),
+		}
+		if p.err != nil {
+			errHandler(p.err)
+			return
+		}
+		handler(event)
+	}
+}
 
-	return wsServe(nil, newWsConfig(getAccountWsEndpoint(listenKey)), wsHandler, errHandler)
+// WsLastPriceServe Serve last traded price updates
+func WsLastPriceServe(ctx context.Context, symbol string, handler WsLastPriceHandler, errHandler ErrHandler, opts ...WsOption) (*Stream, error) {
+	dataType := lastPriceDataType(symbol)
+	return wsServeDataType(ctx, dataType, newWsLastPriceHandler(dataType, handler, errHandler), errHandler, opts...)
 }
@@ -1,6 +1,7 @@
 package bingx
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -9,7 +10,7 @@ import (
 
 type websocketServiceTestSuite struct {
 	baseTestSuite
-	origWsServe func([]byte, *WsConfig, WsHandler, ErrHandler) (chan struct{}, chan struct{}, error)
+	origWsServe func(context.Context, []byte, *WsConfig, WsHandler, ErrHandler) (*Stream, error)
 	serveCount  int
 }
 
@@ -18,6 +19,7 @@ func TestWebsocketService(t *testing.T) {
 }
 
 func (s *websocketServiceTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
 	s.origWsServe = wsServe
 }
 
@@ -27,10 +29,10 @@ func (s *websocketServiceTestSuite) TearDownTest() {
 }
 
 func (s *websocketServiceTestSuite) mockWsServe(data [][]byte, err error) {
-	wsServe = func(initMessage []byte, config *WsConfig, handler WsHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
+	wsServe = func(ctx context.Context, initMessage []byte, config *WsConfig, handler WsHandler, errHandler ErrHandler) (*Stream, error) {
 		s.serveCount++
-		doneC = make(chan struct{})
-		stopC = make(chan struct{})
+		doneC := make(chan struct{})
+		stopC := make(chan struct{})
 		go func() {
 			<-stopC
 			close(doneC)
@@ -41,7 +43,13 @@ func (s *websocketServiceTestSuite) mockWsServe(data [][]byte, err error) {
 		if err != nil {
 			errHandler(err)
 		}
-		return doneC, stopC, nil
+		stop := func() {
+			close(stopC)
+		}
+		connErr := func() error {
+			return nil
+		}
+		return newStream(ctx, stop, connErr, doneC), nil
 	}
 }
 
@@ -89,7 +97,7 @@ func (s *websocketServiceTestSuite) TestKlineServe() {
 
 	steps := 0
 
-	doneC, stopC, err := WsKlineServe("ETHBTC", Interval1, func(event *WsKlineEvent) {
+	stream, err := WsKlineServe(newContext(), "ETHBTC", Interval1, func(event *WsKlineEvent) {
 		var e *WsKlineEvent
 		switch steps {
 		case 0:
@@ -122,8 +130,181 @@ func (s *websocketServiceTestSuite) TestKlineServe() {
 		s.r().EqualError(err, fakeErrMsg)
 	})
 	s.r().NoError(err)
-	stopC <- struct{}{}
-	<-doneC
+	stream.Close()
+}
+
+func (s *websocketServiceTestSuite) TestDepthServe() {
+	data := [][]byte{
+		[]byte(`{"id":"e745cd6d-d0f6-4a70-8d5a-043e4c741b40","code":80015,"msg":"dataType not support","dataType":""}`),
+		[]byte(`{
+			"code": 0,
+			"dataType": "BTC-USDT@depth5",
+			"ts": 1702718831034,
+			"data": {
+				"bids": [["43062.3", "10.29"]],
+				"asks": [["43062.6", "4.12"], ["43063.1", "0.5"]]
+			}
+		}`),
+		[]byte(`{"code": 0, "dataType": "BTC-USDT@depth5", "data": {"bids": [["bad", "1"]], "asks": []}}`),
+	}
+	s.mockWsServe(data, nil)
+	defer s.assertWsServe()
+
+	var events []*WsDepthEvent
+	var errs []error
+	stream, err := WsDepthServe(newContext(), "BTC-USDT", 5, func(event *WsDepthEvent) {
+		events = append(events, event)
+	}, func(err error) {
+		errs = append(errs, err)
+	})
+	r := s.r()
+	r.NoError(err)
+	stream.Close()
+
+	r.Equal([]*WsDepthEvent{{
+		Symbol: "BTC-USDT",
+		Time:   1702718831034,
+		Bids:   []WsDepthLevel{{Price: 43062.3, Quantity: 10.29}},
+		Asks:   []WsDepthLevel{{Price: 43062.6, Quantity: 4.12}, {Price: 43063.1, Quantity: 0.5}},
+	}}, events)
+	r.Len(errs, 2)
+	var reqErr *WsRequestError
+	r.ErrorAs(errs[0], &reqErr)
+	r.Equal("BTC-USDT@depth5", reqErr.DataType)
+	r.EqualError(errs[1], `bingx: invalid price "bad": strconv.ParseFloat: parsing "bad": invalid syntax`)
+}
+
+func (s *websocketServiceTestSuite) TestTradeServe() {
+	data := [][]byte{
+		[]byte(`{
+			"code": 0,
+			"dataType": "BTC-USDT@trade",
+			"data": [
+				{"q": "0.0010", "p": "43000.1", "T": 1702719166000, "m": true, "s": "BTC-USDT"},
+				{"q": 0.5, "p": 43000.2, "T": 1702719166001, "m": false, "s": "BTC-USDT"}
+			]
+		}`),
+		[]byte(`{"code": 0, "dataType": "BTC-USDT@trade", "data": [{"q": "x", "p": "1", "s": "BTC-USDT"}]}`),
+	}
+	s.mockWsServe(data, nil)
+	defer s.assertWsServe()
+
+	var events []*WsTradeEvent
+	var errs []error
+	stream, err := WsTradeServe(newContext(), "BTC-USDT", func(event *WsTradeEvent) {
+		events = append(events, event)
+	}, func(err error) {
+		errs = append(errs, err)
+	})
+	r := s.r()
+	r.NoError(err)
+	stream.Close()
+
+	r.Equal([]*WsTradeEvent{
+		{Symbol: "BTC-USDT", Price: 43000.1, Quantity: 0.001, Time: 1702719166000, IsBuyerMaker: true},
+		{Symbol: "BTC-USDT", Price: 43000.2, Quantity: 0.5, Time: 1702719166001},
+	}, events)
+	r.Len(errs, 1)
+	r.EqualError(errs[0], `bingx: invalid quantity "x": strconv.ParseFloat: parsing "x": invalid syntax`)
+}
+
+func (s *websocketServiceTestSuite) TestTickerServe() {
+	data := [][]byte{
+		[]byte(`{
+			"code": 0,
+			"dataType": "BTC-USDT@ticker",
+			"data": {
+				"e": "24hTicker", "E": 1702719166000, "s": "BTC-USDT",
+				"p": "-100.5", "P": "-0.23", "c": "43000.1", "L": "0.01",
+				"h": "43500", "l": "42800", "v": "1200.5", "q": "51600000",
+				"o": "43100.6", "O": 1702632766000, "C": 1702719166000,
+				"B": "43000.0", "b": "1.5", "A": "43000.2", "a": "2.5"
+			}
+		}`),
+	}
+	s.mockWsServe(data, nil)
+	defer s.assertWsServe()
+
+	var events []*WsTickerEvent
+	stream, err := WsTickerServe(newContext(), "BTC-USDT", func(event *WsTickerEvent) {
+		events = append(events, event)
+	}, func(err error) {
+		s.r().NoError(err)
+	})
+	r := s.r()
+	r.NoError(err)
+	stream.Close()
+
+	r.Equal([]*WsTickerEvent{{
+		Symbol:             "BTC-USDT",
+		Time:               1702719166000,
+		PriceChange:        -100.5,
+		PriceChangePercent: -0.23,
+		LastPrice:          43000.1,
+		LastQty:            0.01,
+		High:               43500,
+		Low:                42800,
+		Volume:             1200.5,
+		QuoteVolume:        51600000,
+		Open:               43100.6,
+		OpenTime:           1702632766000,
+		CloseTime:          1702719166000,
+		BidPrice:           43000.0,
+		BidQty:             1.5,
+		AskPrice:           43000.2,
+		AskQty:             2.5,
+	}}, events)
+}
+
+func (s *websocketServiceTestSuite) TestPriceServe() {
+	data := [][]byte{
+		[]byte(`{"code": 0, "dataType": "BTC-USDT@markPrice", "data": {"e": "markPriceUpdate", "E": 1702719166000, "s": "BTC-USDT", "p": "43001.5"}}`),
+		[]byte(`{"code": 0, "dataType": "BTC-USDT@lastPrice", "data": {"e": "lastPriceUpdate", "E": 1702719166001, "s": "BTC-USDT", "c": "43000.1"}}`),
+		[]byte(`{
+			"code": 0,
+			"dataType": "BTC-USDT@bookTicker",
+			"data": {"e": "bookTicker", "u": 42, "E": 1702719166002, "T": 1702719166002, "s": "BTC-USDT", "b": "43000.0", "B": "1.5", "a": "43000.2", "A": "2.5"}
+		}`),
+	}
+	s.mockWsServe(data, nil)
+	defer s.assertWsServe(3)
+
+	r := s.r()
+	errHandler := func(err error) {
+		r.NoError(err)
+	}
+
+	var markPrice *WsMarkPriceEvent
+	stream, err := WsMarkPriceServe(newContext(), "BTC-USDT", func(event *WsMarkPriceEvent) {
+		markPrice = event
+	}, errHandler)
+	r.NoError(err)
+	stream.Close()
+	r.Equal(&WsMarkPriceEvent{Symbol: "BTC-USDT", Time: 1702719166000, MarkPrice: 43001.5}, markPrice)
+
+	var lastPrice *WsLastPriceEvent
+	stream, err = WsLastPriceServe(newContext(), "BTC-USDT", func(event *WsLastPriceEvent) {
+		lastPrice = event
+	}, errHandler)
+	r.NoError(err)
+	stream.Close()
+	r.Equal(&WsLastPriceEvent{Symbol: "BTC-USDT", Time: 1702719166001, LastPrice: 43000.1}, lastPrice)
+
+	var bookTicker *WsBookTickerEvent
+	stream, err = WsBookTickerServe(newContext(), "BTC-USDT", func(event *WsBookTickerEvent) {
+		bookTicker = event
+	}, errHandler)
+	r.NoError(err)
+	stream.Close()
+	r.Equal(&WsBookTickerEvent{
+		Symbol:   "BTC-USDT",
+		UpdateId: 42,
+		Time:     1702719166002,
+		BidPrice: 43000.0,
+		BidQty:   1.5,
+		AskPrice: 43000.2,
+		AskQty:   2.5,
+	}, bookTicker)
 }
 
 func (s *websocketServiceTestSuite) assertWsKlineEventEqual(e, a *WsKlineEvent) {
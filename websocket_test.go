@@ -0,0 +1,385 @@
+package bingx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+// wsServerTestSuite runs websocket server, connections are received with accept
+type wsServerTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	connsC   chan *websocket.Conn
+	headersC chan http.Header
+}
+
+type websocketTestSuite struct {
+	wsServerTestSuite
+}
+
+func TestWebsocket(t *testing.T) {
+	suite.Run(t, new(websocketTestSuite))
+}
+
+func (s *wsServerTestSuite) SetupTest() {
+	s.connsC = make(chan *websocket.Conn, 10)
+	s.headersC = make(chan http.Header, 10)
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.headersC <- r.Header
+		s.connsC <- conn
+	}))
+}
+
+func (s *wsServerTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *wsServerTestSuite) endpoint() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+func (s *wsServerTestSuite) accept() *websocket.Conn {
+	select {
+	case conn := <-s.connsC:
+		return conn
+	case <-time.After(5 * time.Second):
+		s.FailNow("connection timeout")
+		return nil
+	}
+}
+
+func gzipMessage(msg string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(msg))
+	w.Close()
+	return buf.Bytes()
+}
+
+func (s *wsServerTestSuite) readText(conn *websocket.Conn) string {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, msg, err := conn.ReadMessage()
+	s.Require().NoError(err)
+	return string(msg)
+}
+
+func (s *websocketTestSuite) TestPingPong() {
+	r := s.Require()
+	stream, err := wsServe(context.Background(), nil, newWsConfig(s.endpoint()), func(data []byte) {}, func(err error) {})
+	r.NoError(err)
+
+	conn := s.accept()
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage("Ping")))
+	r.Equal("Pong", s.readText(conn))
+
+	r.NoError(stream.Close())
+	r.NoError(stream.Close())
+	r.NoError(stream.Err())
+}
+
+func (s *websocketTestSuite) TestPlainFrame() {
+	r := s.Require()
+	messageC := make(chan string, 10)
+	errC := make(chan error, 10)
+	stream, err := wsServe(context.Background(), nil, newWsConfig(s.endpoint()), func(data []byte) {
+		messageC <- string(data)
+	}, func(err error) {
+		errC <- err
+	})
+	r.NoError(err)
+	defer stream.Close()
+
+	conn := s.accept()
+	r.NoError(conn.WriteMessage(websocket.TextMessage, []byte(`{"plain":true}`)))
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, []byte{0x1f, 0x8b, 0x00}))
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage(`{"gzip":true}`)))
+	r.NoError(conn.WriteMessage(websocket.TextMessage, []byte("Ping")))
+	r.Equal("Pong", s.readText(conn))
+
+	r.Equal(`{"plain":true}`, <-messageC)
+	r.Equal(`{"gzip":true}`, <-messageC)
+	r.Error(<-errC)
+}
+
+func (s *websocketTestSuite) TestConnectionOptions() {
+	r := s.Require()
+	stream, err := wsServe(context.Background(), nil, newWsConfig(s.endpoint(),
+		WithWsHeader("X-Test", "1"),
+		WithWsHandshakeTimeout(time.Second),
+		WithWsReadLimit(16),
+		WithWsPingInterval(10*time.Millisecond),
+		WithWsReconnect(false),
+	), func(data []byte) {}, func(err error) {})
+	r.NoError(err)
+
+	header := <-s.headersC
+	r.Equal("1", header.Get("X-Test"))
+	r.Equal("gzip", header.Get("Accept-Encoding"))
+
+	conn := s.accept()
+	pingC := make(chan struct{}, 10)
+	conn.SetPingHandler(func(string) error {
+		pingC <- struct{}{}
+		return nil
+	})
+	go conn.ReadMessage()
+	select {
+	case <-pingC:
+	case <-time.After(5 * time.Second):
+		s.FailNow("ping is not sent")
+	}
+
+	// message over read limit closes connection
+	r.NoError(conn.WriteMessage(websocket.TextMessage, []byte(`{"too":"long message"}`)))
+	<-stream.Done()
+	r.ErrorIs(stream.Err(), websocket.ErrReadLimit)
+}
+
+func (s *websocketTestSuite) TestReadTimeout() {
+	r := s.Require()
+	stream, err := wsServe(context.Background(), nil, newWsConfig(s.endpoint(),
+		WithWsTimeouts(50*time.Millisecond, time.Second),
+		WithWsReconnect(false),
+	), func(data []byte) {}, func(err error) {})
+	r.NoError(err)
+	s.accept()
+
+	select {
+	case <-stream.Done():
+	case <-time.After(5 * time.Second):
+		s.FailNow("read timeout is not detected")
+	}
+	r.Error(stream.Err())
+}
+
+func (s *websocketTestSuite) TestProxy() {
+	r := s.Require()
+	_, err := wsServe(context.Background(), nil, newWsConfig(s.endpoint(),
+		WithWsProxy("http://127.0.0.1:1"),
+		WithWsReconnect(false),
+	), func(data []byte) {}, func(err error) {})
+	r.Error(err)
+}
+
+func (s *websocketTestSuite) TestContextCancel() {
+	r := s.Require()
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := wsServe(ctx, nil, newWsConfig(s.endpoint()), func(data []byte) {}, func(err error) {})
+	r.NoError(err)
+	s.accept()
+	r.NoError(stream.Err())
+
+	cancel()
+	select {
+	case <-stream.Done():
+	case <-time.After(5 * time.Second):
+		s.FailNow("stream is not stopped")
+	}
+	r.ErrorIs(stream.Err(), context.Canceled)
+	r.ErrorIs(stream.Close(), context.Canceled)
+}
+
+func (s *websocketTestSuite) TestReconnect() {
+	r := s.Require()
+
+	var mu sync.Mutex
+	var messages []string
+	var connected, disconnected, reconnecting, errs int
+	var gaps []WsGap
+	messageC := make(chan struct{}, 10)
+
+	stream, err := wsServe(context.Background(), []byte("sub"), newWsConfig(s.endpoint(),
+		WithWsBackoff(10*time.Millisecond, 20*time.Millisecond),
+		WithWsOnConnected(func() {
+			mu.Lock()
+			connected++
+			mu.Unlock()
+		}),
+		WithWsOnDisconnected(func(err error) {
+			mu.Lock()
+			disconnected++
+			mu.Unlock()
+		}),
+		WithWsOnReconnecting(func(attempt int, delay time.Duration) {
+			mu.Lock()
+			reconnecting++
+			mu.Unlock()
+		}),
+		WithWsGapHandler(func(gap WsGap) {
+			mu.Lock()
+			gaps = append(gaps, gap)
+			mu.Unlock()
+		}),
+	), func(data []byte) {
+		mu.Lock()
+		messages = append(messages, string(data))
+		mu.Unlock()
+		messageC <- struct{}{}
+	}, func(err error) {
+		mu.Lock()
+		errs++
+		mu.Unlock()
+	})
+	r.NoError(err)
+
+	conn := s.accept()
+	r.Equal("sub", s.readText(conn))
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage("first")))
+	<-messageC
+	conn.Close()
+
+	conn = s.accept()
+	r.Equal("sub", s.readText(conn))
+	r.NoError(conn.WriteMessage(websocket.BinaryMessage, gzipMessage("second")))
+	<-messageC
+
+	r.NoError(stream.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	r.Equal([]string{"first", "second"}, messages)
+	r.Equal(2, connected)
+	r.Equal(1, disconnected)
+	r.Equal(1, reconnecting)
+	r.Equal(1, errs)
+	r.Len(gaps, 1)
+	r.False(gaps[0].ReconnectedAt.Before(gaps[0].DisconnectedAt))
+}
+
+func (s *websocketTestSuite) TestNoReconnect() {
+	r := s.Require()
+	errC := make(chan error, 1)
+	stream, err := wsServe(context.Background(), nil, newWsConfig(s.endpoint(), WithWsReconnect(false)), func(data []byte) {}, func(err error) {
+		errC <- err
+	})
+	r.NoError(err)
+
+	s.accept().Close()
+	<-stream.Done()
+	connErr := <-errC
+	r.Error(connErr)
+	r.Equal(connErr, stream.Err())
+	r.Equal(connErr, stream.Close())
+}
+
+func (s *websocketTestSuite) TestGiveUpReconnect() {
+	r := s.Require()
+	stream, err := wsServe(context.Background(), nil, newWsConfig(s.endpoint(),
+		WithWsBackoff(time.Millisecond, time.Millisecond),
+		WithWsMaxReconnectAttempts(2),
+	), func(data []byte) {}, func(err error) {})
+	r.NoError(err)
+
+	conn := s.accept()
+	s.server.Close()
+	conn.Close()
+
+	select {
+	case <-stream.Done():
+	case <-time.After(5 * time.Second):
+		s.FailNow("reconnect was not given up")
+	}
+	r.Error(stream.Err())
+}
+
+func (s *websocketTestSuite) TestQueueBlocksReader() {
+	r := s.Require()
+	c := newWsConn(newWsConfig("", WithWsBuffer(2)), func(data []byte) {}, nil, nil)
+
+	var mu sync.Mutex
+	queued := 0
+	go func() {
+		for i := 0; i < 10; i++ {
+			c.enqueue([]byte{byte(i)})
+			mu.Lock()
+			queued++
+			mu.Unlock()
+		}
+	}()
+	queueLen := func() int {
+		c.queueMu.Lock()
+		defer c.queueMu.Unlock()
+		return len(c.queue)
+	}
+
+	// consumer is stalled, reader waits instead of growing queue
+	r.Eventually(func() bool { return queueLen() == 2 }, time.Second, time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	r.Equal(2, queued)
+	mu.Unlock()
+	r.Equal(2, queueLen())
+
+	msg, ok := c.next()
+	r.True(ok)
+	r.Equal([]byte{0}, msg)
+	r.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return queued == 3
+	}, time.Second, time.Millisecond)
+	r.Equal(2, queueLen())
+
+	c.stop()
+	r.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return queued == 10
+	}, time.Second, time.Millisecond)
+}
+
+func (s *websocketTestSuite) TestQueueDropOldest() {
+	r := s.Require()
+	c := newWsConn(newWsConfig("", WithWsBuffer(2), WithWsOverflow(OverflowDropOldest)), func(data []byte) {}, nil, nil)
+	for i := 0; i < 10; i++ {
+		r.NoError(c.enqueue([]byte{byte(i)}))
+	}
+	r.Equal([][]byte{{8}, {9}}, c.queue)
+}
+
+func (s *websocketTestSuite) TestQueueDisconnect() {
+	r := s.Require()
+	handlingC, releaseC := make(chan struct{}, 10), make(chan struct{})
+	handled := 0
+	stream, err := wsServe(context.Background(), nil, newWsConfig(s.endpoint(), WithWsBuffer(2), WithWsOverflow(OverflowDisconnect)), func(data []byte) {
+		handlingC <- struct{}{}
+		<-releaseC
+		handled++
+	}, func(err error) {})
+	r.NoError(err)
+
+	conn := s.accept()
+	r.NoError(conn.WriteMessage(websocket.TextMessage, []byte("{}")))
+	<-handlingC
+	for i := 0; i < 10; i++ {
+		if conn.WriteMessage(websocket.TextMessage, []byte("{}")) != nil {
+			break
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(releaseC)
+
+	select {
+	case <-stream.Done():
+	case <-time.After(5 * time.Second):
+		s.FailNow("stream is not stopped on overflow")
+	}
+	r.ErrorIs(stream.Err(), ErrWsOverflow)
+	// message being handled and queued ones are delivered before stream stops
+	r.Equal(3, handled)
+}